@@ -0,0 +1,215 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConversionDataAnnotation holds the v1alpha3 spec and status of an Orchestrator read as v1alpha2 when they have
+// fields that cannot be represented in v1alpha2, so that converting it back to v1alpha3 does not lose them.
+const ConversionDataAnnotation = "rhdh.redhat.com/conversion-data"
+
+// conversionData is the content of the ConversionDataAnnotation.
+type conversionData struct {
+	Spec   v1alpha3.OrchestratorSpec   `json:"spec"`
+	Status v1alpha3.OrchestratorStatus `json:"status"`
+}
+
+var _ conversion.Convertible = &Orchestrator{}
+
+// ConvertTo converts this Orchestrator to the Hub version (v1alpha3).
+func (src *Orchestrator) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha3.Orchestrator)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha3 Orchestrator but got %T", dstRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = convertSpecToHub(src.Spec)
+	dst.Status = convertStatusToHub(src.Status)
+
+	// restore the fields that only exist in v1alpha3
+	data, found := dst.Annotations[ConversionDataAnnotation]
+	if !found {
+		return nil
+	}
+	restored := conversionData{}
+	if err := json.Unmarshal([]byte(data), &restored); err != nil {
+		return fmt.Errorf("failed to unmarshal %s annotation: %w", ConversionDataAnnotation, err)
+	}
+	restoreHubOnlyFields(dst, &restored)
+
+	delete(dst.Annotations, ConversionDataAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha3) to this version.
+func (dst *Orchestrator) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha3.Orchestrator)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha3 Orchestrator but got %T", srcRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = convertSpecFromHub(src.Spec)
+	dst.Status = convertStatusFromHub(src.Status)
+
+	// preserve the fields that cannot be represented in v1alpha2
+	if apiequality.Semantic.DeepEqual(convertSpecToHub(dst.Spec), src.Spec) &&
+		apiequality.Semantic.DeepEqual(convertStatusToHub(dst.Status), src.Status) {
+		return nil
+	}
+	data, err := json.Marshal(conversionData{Spec: src.Spec, Status: src.Status})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s annotation: %w", ConversionDataAnnotation, err)
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = map[string]string{}
+	}
+	dst.Annotations[ConversionDataAnnotation] = string(data)
+	return nil
+}
+
+// restoreHubOnlyFields copies the fields that do not exist in v1alpha2 from restored to dst.
+func restoreHubOnlyFields(dst *v1alpha3.Orchestrator, restored *conversionData) {
+	dst.Spec.ServerlessLogicOperator.Subscription = restored.Spec.ServerlessLogicOperator.Subscription
+	dst.Spec.ServerlessOperator.Subscription = restored.Spec.ServerlessOperator.Subscription
+	dst.Spec.ServerlessOperator.Knative = restored.Spec.ServerlessOperator.Knative
+	dst.Spec.RHDHConfig.Subscription = restored.Spec.RHDHConfig.Subscription
+	dst.Spec.RHDHConfig.RHDHPlugins = restored.Spec.RHDHConfig.RHDHPlugins
+	dst.Spec.PlatformConfig.Monitoring = restored.Spec.PlatformConfig.Monitoring
+	dst.Spec.PlatformConfig.NetworkPolicies = restored.Spec.PlatformConfig.NetworkPolicies
+	dst.Spec.ArgoCd.Project = restored.Spec.ArgoCd.Project
+	dst.Spec.ArgoCd.Workflows = restored.Spec.ArgoCd.Workflows
+	dst.Spec.Tekton.Namespace = restored.Spec.Tekton.Namespace
+	dst.Spec.Tekton.Pipeline = restored.Spec.Tekton.Pipeline
+	dst.Spec.DeletionPolicy = restored.Spec.DeletionPolicy
+	dst.Spec.PlatformConfig.Eventing.Broker.Create = restored.Spec.PlatformConfig.Eventing.Broker.Create
+	dst.Spec.PlatformConfig.Eventing.Broker.Type = restored.Spec.PlatformConfig.Eventing.Broker.Type
+	dst.Spec.PlatformConfig.Eventing.Broker.Kafka = restored.Spec.PlatformConfig.Eventing.Broker.Kafka
+
+	dst.Status.PendingInstallPlans = restored.Status.PendingInstallPlans
+	dst.Status.Operators = restored.Status.Operators
+	dst.Status.NetworkPolicyNamespaces = restored.Status.NetworkPolicyNamespaces
+	dst.Status.Workflows = restored.Status.Workflows
+	dst.Status.TektonResources = restored.Status.TektonResources
+}
+
+func convertStatusToHub(src OrchestratorStatus) v1alpha3.OrchestratorStatus {
+	return v1alpha3.OrchestratorStatus{
+		Conditions: src.DeepCopy().Conditions,
+		Phase:      v1alpha3.OrchestratorPhase(src.Phase),
+	}
+}
+
+func convertStatusFromHub(src v1alpha3.OrchestratorStatus) OrchestratorStatus {
+	return OrchestratorStatus{
+		Conditions: src.DeepCopy().Conditions,
+		Phase:      OrchestratorPhase(src.Phase),
+	}
+}
+
+func convertSpecToHub(src OrchestratorSpec) v1alpha3.OrchestratorSpec {
+	return v1alpha3.OrchestratorSpec{
+		ServerlessLogicOperator: v1alpha3.ServerlessLogicOperator{
+			InstallOperator: src.ServerlessLogicOperator.InstallOperator,
+		},
+		ServerlessOperator: v1alpha3.ServerlessOperator{
+			InstallOperator: src.ServerlessOperator.InstallOperator,
+		},
+		RHDHConfig: v1alpha3.RHDHConfig{
+			Name:            src.RHDHConfig.Name,
+			Namespace:       src.RHDHConfig.Namespace,
+			InstallOperator: src.RHDHConfig.InstallOperator,
+			DevMode:         src.RHDHConfig.DevMode,
+		},
+		PostgresConfig: v1alpha3.PostgresConfig{
+			Name:         src.PostgresConfig.Name,
+			Namespace:    src.PostgresConfig.Namespace,
+			AuthSecret:   v1alpha3.PostgresAuthSecret(src.PostgresConfig.AuthSecret),
+			DatabaseName: src.PostgresConfig.DatabaseName,
+		},
+		PlatformConfig: v1alpha3.PlatformConfig{
+			Namespace: src.PlatformConfig.Namespace,
+			Resources: v1alpha3.Resource{
+				Requests: v1alpha3.MemoryCpu(src.PlatformConfig.Resources.Requests),
+				Limits:   v1alpha3.MemoryCpu(src.PlatformConfig.Resources.Limits),
+			},
+			Eventing: v1alpha3.Eventing{
+				Broker: v1alpha3.Broker{
+					Name:      src.PlatformConfig.Eventing.Broker.Name,
+					Namespace: src.PlatformConfig.Eventing.Broker.Namespace,
+				},
+			},
+		},
+		Tekton: v1alpha3.Tekton{Enabled: src.Tekton.Enabled},
+		ArgoCd: v1alpha3.ArgoCD{
+			Enabled:   src.ArgoCd.Enabled,
+			Namespace: src.ArgoCd.Namespace,
+		},
+	}
+}
+
+func convertSpecFromHub(src v1alpha3.OrchestratorSpec) OrchestratorSpec {
+	return OrchestratorSpec{
+		ServerlessLogicOperator: ServerlessLogicOperator{
+			InstallOperator: src.ServerlessLogicOperator.InstallOperator,
+		},
+		ServerlessOperator: ServerlessOperator{
+			InstallOperator: src.ServerlessOperator.InstallOperator,
+		},
+		RHDHConfig: RHDHConfig{
+			Name:            src.RHDHConfig.Name,
+			Namespace:       src.RHDHConfig.Namespace,
+			InstallOperator: src.RHDHConfig.InstallOperator,
+			DevMode:         src.RHDHConfig.DevMode,
+		},
+		PostgresConfig: PostgresConfig{
+			Name:         src.PostgresConfig.Name,
+			Namespace:    src.PostgresConfig.Namespace,
+			AuthSecret:   PostgresAuthSecret(src.PostgresConfig.AuthSecret),
+			DatabaseName: src.PostgresConfig.DatabaseName,
+		},
+		PlatformConfig: PlatformConfig{
+			Namespace: src.PlatformConfig.Namespace,
+			Resources: Resource{
+				Requests: MemoryCpu(src.PlatformConfig.Resources.Requests),
+				Limits:   MemoryCpu(src.PlatformConfig.Resources.Limits),
+			},
+			Eventing: Eventing{
+				Broker: Broker{
+					Name:      src.PlatformConfig.Eventing.Broker.Name,
+					Namespace: src.PlatformConfig.Eventing.Broker.Namespace,
+				},
+			},
+		},
+		Tekton: Tekton{Enabled: src.Tekton.Enabled},
+		ArgoCd: ArgoCD{
+			Enabled:   src.ArgoCd.Enabled,
+			Namespace: src.ArgoCd.Namespace,
+		},
+	}
+}
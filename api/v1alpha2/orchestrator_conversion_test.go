@@ -0,0 +1,126 @@
+package v1alpha2
+
+import (
+	"encoding/json"
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+	"github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const fuzzIterations = 1000
+
+func newFuzzer(seed int64) *fuzz.Fuzzer {
+	return fuzz.NewWithSeed(seed).NilChance(0.2).NumElements(0, 3).Funcs(
+		// raw JSON fields must hold valid JSON to be stored in the conversion annotation
+		func(j *apiextensionsv1.JSON, c fuzz.Continue) {
+			j.Raw, _ = json.Marshal(map[string]string{"key": c.RandString()})
+		},
+	)
+}
+
+func fuzzedObjectMeta(f *fuzz.Fuzzer) metav1.ObjectMeta {
+	objectMeta := metav1.ObjectMeta{Name: "orchestrator-sample", Namespace: "orchestrator"}
+	f.Fuzz(&objectMeta.Labels)
+	f.Fuzz(&objectMeta.Annotations)
+	return objectMeta
+}
+
+func TestHubSpokeHubRoundTrip(t *testing.T) {
+	f := newFuzzer(1)
+	for i := 0; i < fuzzIterations; i++ {
+		hub := &v1alpha3.Orchestrator{ObjectMeta: fuzzedObjectMeta(f)}
+		f.Fuzz(&hub.Spec)
+		f.Fuzz(&hub.Status)
+		delete(hub.Annotations, ConversionDataAnnotation)
+
+		spoke := &Orchestrator{}
+		assert.NoError(t, spoke.ConvertFrom(hub.DeepCopy()))
+
+		restored := &v1alpha3.Orchestrator{}
+		assert.NoError(t, spoke.ConvertTo(restored))
+		if len(hub.Annotations) == 0 {
+			assert.Empty(t, restored.Annotations)
+			restored.Annotations = hub.Annotations
+		}
+		if !assert.True(t, apiequality.Semantic.DeepEqual(hub, restored), "%+v\n%+v", hub, restored) {
+			return
+		}
+	}
+}
+
+func TestSpokeHubSpokeRoundTrip(t *testing.T) {
+	f := newFuzzer(2)
+	for i := 0; i < fuzzIterations; i++ {
+		spoke := &Orchestrator{ObjectMeta: fuzzedObjectMeta(f)}
+		f.Fuzz(&spoke.Spec)
+		f.Fuzz(&spoke.Status)
+		delete(spoke.Annotations, ConversionDataAnnotation)
+
+		hub := &v1alpha3.Orchestrator{}
+		assert.NoError(t, spoke.DeepCopy().ConvertTo(hub))
+
+		restored := &Orchestrator{}
+		assert.NoError(t, restored.ConvertFrom(hub))
+		if !assert.True(t, apiequality.Semantic.DeepEqual(spoke, restored), "%+v\n%+v", spoke, restored) {
+			return
+		}
+	}
+}
+
+func TestConvertFromPreservesHubOnlyFields(t *testing.T) {
+	hub := &v1alpha3.Orchestrator{
+		ObjectMeta: metav1.ObjectMeta{Name: "orchestrator-sample"},
+		Spec: v1alpha3.OrchestratorSpec{
+			RHDHConfig: v1alpha3.RHDHConfig{
+				Name:      "backstage",
+				Namespace: "rhdh-operator",
+				RHDHPlugins: v1alpha3.RHDHPlugins{
+					NotificationsConfig: v1alpha3.NotificationConfig{Enabled: true, Port: 587, Sender: "orchestrator@example.com"},
+				},
+			},
+			PlatformConfig: v1alpha3.PlatformConfig{
+				Namespace:  "sonataflow-infra",
+				Monitoring: v1alpha3.MonitoringConfig{Enabled: true},
+			},
+		},
+	}
+
+	spoke := &Orchestrator{}
+	assert.NoError(t, spoke.ConvertFrom(hub))
+	assert.Equal(t, "backstage", spoke.Spec.RHDHConfig.Name)
+	assert.Contains(t, spoke.Annotations, ConversionDataAnnotation)
+	// the source object must not be modified by the conversion
+	assert.Empty(t, hub.Annotations)
+
+	restored := &v1alpha3.Orchestrator{}
+	assert.NoError(t, spoke.ConvertTo(restored))
+	assert.Equal(t, hub, restored)
+}
+
+func TestConvertFromWithoutHubOnlyFields(t *testing.T) {
+	hub := &v1alpha3.Orchestrator{
+		ObjectMeta: metav1.ObjectMeta{Name: "orchestrator-sample"},
+		Spec: v1alpha3.OrchestratorSpec{
+			RHDHConfig: v1alpha3.RHDHConfig{Name: "backstage", Namespace: "rhdh-operator"},
+		},
+	}
+
+	spoke := &Orchestrator{}
+	assert.NoError(t, spoke.ConvertFrom(hub))
+	assert.NotContains(t, spoke.Annotations, ConversionDataAnnotation)
+}
+
+func TestConvertToWithInvalidAnnotation(t *testing.T) {
+	spoke := &Orchestrator{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "orchestrator-sample",
+			Annotations: map[string]string{ConversionDataAnnotation: "{"},
+		},
+	}
+	assert.Error(t, spoke.ConvertTo(&v1alpha3.Orchestrator{}))
+}
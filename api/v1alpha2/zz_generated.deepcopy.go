@@ -0,0 +1,315 @@
+//go:build !ignore_autogenerated
+
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1alpha2
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCD) DeepCopyInto(out *ArgoCD) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCD.
+func (in *ArgoCD) DeepCopy() *ArgoCD {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCD)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Broker) DeepCopyInto(out *Broker) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Broker.
+func (in *Broker) DeepCopy() *Broker {
+	if in == nil {
+		return nil
+	}
+	out := new(Broker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Eventing) DeepCopyInto(out *Eventing) {
+	*out = *in
+	out.Broker = in.Broker
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Eventing.
+func (in *Eventing) DeepCopy() *Eventing {
+	if in == nil {
+		return nil
+	}
+	out := new(Eventing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryCpu) DeepCopyInto(out *MemoryCpu) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryCpu.
+func (in *MemoryCpu) DeepCopy() *MemoryCpu {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryCpu)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Orchestrator) DeepCopyInto(out *Orchestrator) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Orchestrator.
+func (in *Orchestrator) DeepCopy() *Orchestrator {
+	if in == nil {
+		return nil
+	}
+	out := new(Orchestrator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Orchestrator) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrchestratorList) DeepCopyInto(out *OrchestratorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Orchestrator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorList.
+func (in *OrchestratorList) DeepCopy() *OrchestratorList {
+	if in == nil {
+		return nil
+	}
+	out := new(OrchestratorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OrchestratorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrchestratorSpec) DeepCopyInto(out *OrchestratorSpec) {
+	*out = *in
+	out.ServerlessLogicOperator = in.ServerlessLogicOperator
+	out.ServerlessOperator = in.ServerlessOperator
+	out.RHDHConfig = in.RHDHConfig
+	out.PostgresConfig = in.PostgresConfig
+	out.PlatformConfig = in.PlatformConfig
+	out.Tekton = in.Tekton
+	out.ArgoCd = in.ArgoCd
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorSpec.
+func (in *OrchestratorSpec) DeepCopy() *OrchestratorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OrchestratorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrchestratorStatus) DeepCopyInto(out *OrchestratorStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorStatus.
+func (in *OrchestratorStatus) DeepCopy() *OrchestratorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OrchestratorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlatformConfig) DeepCopyInto(out *PlatformConfig) {
+	*out = *in
+	out.Resources = in.Resources
+	out.Eventing = in.Eventing
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlatformConfig.
+func (in *PlatformConfig) DeepCopy() *PlatformConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PlatformConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresAuthSecret) DeepCopyInto(out *PostgresAuthSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresAuthSecret.
+func (in *PostgresAuthSecret) DeepCopy() *PostgresAuthSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresAuthSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresConfig) DeepCopyInto(out *PostgresConfig) {
+	*out = *in
+	out.AuthSecret = in.AuthSecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfig.
+func (in *PostgresConfig) DeepCopy() *PostgresConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHDHConfig) DeepCopyInto(out *RHDHConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHDHConfig.
+func (in *RHDHConfig) DeepCopy() *RHDHConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RHDHConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Resource) DeepCopyInto(out *Resource) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resource.
+func (in *Resource) DeepCopy() *Resource {
+	if in == nil {
+		return nil
+	}
+	out := new(Resource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerlessLogicOperator) DeepCopyInto(out *ServerlessLogicOperator) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerlessLogicOperator.
+func (in *ServerlessLogicOperator) DeepCopy() *ServerlessLogicOperator {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerlessLogicOperator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerlessOperator) DeepCopyInto(out *ServerlessOperator) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerlessOperator.
+func (in *ServerlessOperator) DeepCopy() *ServerlessOperator {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerlessOperator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tekton) DeepCopyInto(out *Tekton) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tekton.
+func (in *Tekton) DeepCopy() *Tekton {
+	if in == nil {
+		return nil
+	}
+	out := new(Tekton)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package v1alpha2 contains API Schema definitions for the orchestrator v1alpha2 API group
+// Package v1alpha3 contains API Schema definitions for the orchestrator v1alpha3 API group
 // +kubebuilder:object:generate=true
 // +groupName=rhdh.redhat.com
 package v1alpha3
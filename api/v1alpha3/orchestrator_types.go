@@ -17,7 +17,10 @@ limitations under the License.
 package v1alpha3
 
 import (
+	networkingv1 "k8s.io/api/networking/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -26,6 +29,19 @@ const (
 	FailedPhase    OrchestratorPhase = "Failed"
 )
 
+const (
+	// ApprovalPolicyPinned approves only the InstallPlans of the starting CSV and of the allow-listed CSVs.
+	ApprovalPolicyPinned = "pinned"
+	// ApprovalPolicyAutomaticWithinChannel approves every InstallPlan of the subscribed channel.
+	ApprovalPolicyAutomaticWithinChannel = "automatic-within-channel"
+	// ApprovalPolicyManual approves only the InstallPlans explicitly approved with the ApproveCSVAnnotation.
+	ApprovalPolicyManual = "manual"
+
+	// ApproveCSVAnnotation is the Orchestrator annotation used to approve the pending InstallPlan
+	// that installs the ClusterServiceVersion named in its value.
+	ApproveCSVAnnotation = "rhdh.redhat.com/approve-csv"
+)
+
 // OrchestratorSpec defines the desired state of Orchestrator
 type OrchestratorSpec struct {
 	// Configuration for ServerlessLogic. Optional
@@ -56,18 +72,123 @@ type OrchestratorSpec struct {
 	// Configuration for ArgoCD. Optional
 	// +kubebuilder:default={enabled: false}
 	ArgoCd ArgoCD `json:"argocd,omitempty"`
+
+	// What happens to the resources of every subsystem when the Orchestrator is deleted. Optional
+	// +kubebuilder:default={}
+	DeletionPolicy DeletionPolicies `json:"deletionPolicy,omitempty"`
+}
+
+// DeletionPolicy describes what happens to the resources of a subsystem when the Orchestrator is deleted.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyRetain keeps the resources, which are adopted again by an Orchestrator of the same name
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyDelete deletes the resources, including the namespaces created by the operator
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan keeps the resources and removes the labels of the operator from them, so that no
+	// Orchestrator manages them anymore
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// DeletionPolicies defines the deletion policy of the resources of every subsystem.
+type DeletionPolicies struct {
+	// Policy of the SonataFlow platforms, of the Brokers created by the operator and of the Serverless Logic operator
+	// namespace
+	// +kubebuilder:validation:Enum=Retain;Delete;Orphan
+	// +kubebuilder:default=Retain
+	ServerlessLogic DeletionPolicy `json:"serverlessLogic,omitempty"`
+
+	// Policy of the Knative namespaces and of the Serverless operator namespace
+	// +kubebuilder:validation:Enum=Retain;Delete;Orphan
+	// +kubebuilder:default=Retain
+	Serverless DeletionPolicy `json:"serverless,omitempty"`
+
+	// Policy of the Backstage CR, of the RHDH namespace once it has no other Backstage CR, and of the RHDH
+	// operator namespace
+	// +kubebuilder:validation:Enum=Retain;Delete;Orphan
+	// +kubebuilder:default=Retain
+	RHDH DeletionPolicy `json:"rhdh,omitempty"`
+
+	// Policy of the ArgoCD AppProject and Applications and of the Tekton Pipeline and Tasks
+	// +kubebuilder:validation:Enum=Retain;Delete;Orphan
+	// +kubebuilder:default=Retain
+	GitOps DeletionPolicy `json:"gitops,omitempty"`
 }
 
 type ServerlessLogicOperator struct {
 	// Determines whether to install the ServerlessLogic operator
 	// +kubebuilder:default=true
 	InstallOperator bool `json:"installOperator"`
+
+	// Configuration for the OLM Subscription of the ServerlessLogic operator. Optional
+	Subscription Subscription `json:"subscription,omitempty"`
 }
 
 type ServerlessOperator struct {
 	// Determines whether to install the Serverless operator
 	// +kubebuilder:default=true
 	InstallOperator bool `json:"installOperator"`
+
+	// Configuration for the OLM Subscription of the Serverless operator. Optional
+	Subscription Subscription `json:"subscription,omitempty"`
+
+	// Configuration of the Knative Serving and Knative Eventing instances. Optional
+	Knative Knative `json:"knative,omitempty"`
+}
+
+// Knative contains the specs of the KnativeServing and KnativeEventing CRs managed by the Orchestrator operator.
+// They are passed through to the CRs as is, changes are applied to the CRs on every reconciliation.
+type Knative struct {
+	// Spec of the KnativeServing CR, i.e. the high-availability replicas, the workload overrides,
+	// the config maps (autoscaler, features, network) or the ingress class. Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +optional
+	Serving *apiextensionsv1.JSON `json:"serving,omitempty"`
+
+	// Spec of the KnativeEventing CR, i.e. the default broker class, the high-availability replicas or
+	// the workload overrides. Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +optional
+	Eventing *apiextensionsv1.JSON `json:"eventing,omitempty"`
+}
+
+// Subscription configures the OLM Subscription used to install an operator.
+// Fields left empty default to the values supported by this release of the Orchestrator operator.
+type Subscription struct {
+	// Channel of the operator package to subscribe to
+	Channel string `json:"channel,omitempty"`
+
+	// Name of the ClusterServiceVersion to install first
+	StartingCSV string `json:"startingCSV,omitempty"`
+
+	// Name of the CatalogSource providing the operator package
+	// Defaults to redhat-operators
+	Source string `json:"source,omitempty"`
+
+	// Namespace of the CatalogSource providing the operator package
+	// Defaults to openshift-marketplace
+	SourceNamespace string `json:"sourceNamespace,omitempty"`
+
+	// Approval strategy of the InstallPlans created for the Subscription
+	// Defaults to Manual
+	// +kubebuilder:validation:Enum=Manual;Automatic
+	InstallPlanApproval string `json:"installPlanApproval,omitempty"`
+
+	// Policy used by the Orchestrator operator to approve the InstallPlans when installPlanApproval is Manual:
+	// pinned approves only the startingCSV and the allowedCSVs, automatic-within-channel approves every
+	// InstallPlan of the channel and manual approves none. An InstallPlan can always be approved by setting
+	// the rhdh.redhat.com/approve-csv annotation of the Orchestrator to the CSV it installs.
+	// Defaults to pinned
+	// +kubebuilder:validation:Enum=pinned;automatic-within-channel;manual
+	ApprovalPolicy string `json:"approvalPolicy,omitempty"`
+
+	// CSVs approved in addition to the startingCSV when the approvalPolicy is pinned
+	AllowedCSVs []string `json:"allowedCSVs,omitempty"`
 }
 
 type RHDHConfig struct {
@@ -81,6 +202,7 @@ type RHDHConfig struct {
 
 	// Determines whether the RHDH operator should be installed
 	// This determines the deployment of the RHDH instance.
+	// When false, the orchestrator plugins are configured in the existing RHDH instance.
 	// Defaults to false
 	// +kubebuilder:default=false
 	InstallOperator bool `json:"installOperator,omitempty"`
@@ -91,6 +213,10 @@ type RHDHConfig struct {
 	// +kubebuilder:default=false
 	DevMode bool `json:"devMode,omitempty"`
 
+	// Configuration for the OLM Subscription of the RHDH operator. Optional
+	// Only used when installOperator is true.
+	Subscription Subscription `json:"subscription,omitempty"`
+
 	// Configuration for RHDH Plugins.
 	RHDHPlugins RHDHPlugins `json:"plugins,omitempty"`
 }
@@ -98,6 +224,41 @@ type RHDHConfig struct {
 type RHDHPlugins struct {
 	// Notification email plugin configuration
 	NotificationsConfig NotificationConfig `json:"notificationsEmail,omitempty"`
+
+	// Overrides of the dynamic plugins rendered by the operator.
+	// An override replaces the values of the plugin with the same package, or adds the plugin when there is none.
+	// +optional
+	// +listType=map
+	// +listMapKey=package
+	Overrides []PluginOverride `json:"overrides,omitempty"`
+}
+
+// PluginOverride overrides the configuration of a dynamic plugin
+type PluginOverride struct {
+	// Package of the plugin without its version, such as @redhat/backstage-plugin-orchestrator
+	// or ./dynamic-plugins/dist/backstage-plugin-notifications
+	// +kubebuilder:validation:MinLength=1
+	Package string `json:"package"`
+
+	// Version of the package. Requires the integrity of the package when set.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Integrity of the package, as a sha512- prefixed base64 encoded SHA-512 digest
+	// +kubebuilder:validation:Pattern=`^sha512-[A-Za-z0-9+/]+={0,2}$`
+	// +optional
+	Integrity string `json:"integrity,omitempty"`
+
+	// Determines whether the plugin is disabled
+	// +optional
+	Disabled *bool `json:"disabled,omitempty"`
+
+	// Configuration of the plugin, merged into the configuration rendered by the operator
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +optional
+	PluginConfig *apiextensionsv1.JSON `json:"pluginConfig,omitempty"`
 }
 
 type NotificationConfig struct {
@@ -166,6 +327,49 @@ type PlatformConfig struct {
 
 	// Configuration for sonataflow platform monitoring
 	Monitoring MonitoringConfig `json:"monitoring,omitempty"`
+
+	// Configuration of the network policies of the workflow namespace. Optional
+	NetworkPolicies NetworkPolicies `json:"networkPolicies,omitempty"`
+}
+
+// NetworkPolicies configures the network policies created by the operator in the workflow namespace, in addition
+// to the ones allowing the traffic from RHDH, Knative, OpenShift Serverless Logic and the monitoring stack.
+type NetworkPolicies struct {
+	// Determines whether the operator stops managing the network policies of the workflow namespace, including
+	// the ones built from additionalIngress and egress
+	// +kubebuilder:default=false
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Peers allowed to reach the pods of the workflow namespace, i.e. an API gateway, a service mesh control plane
+	// or a custom monitoring stack
+	AdditionalIngress []networkingv1.NetworkPolicyPeer `json:"additionalIngress,omitempty"`
+
+	// Egress rules of the pods of the workflow namespace. When set, the egress of the pods is restricted to these
+	// rules, the DNS, the workflow namespace, the database namespace and the Knative namespaces
+	Egress []NetworkPolicyEgressRule `json:"egress,omitempty"`
+}
+
+// NetworkPolicyEgressRule allows the traffic from the pods of the workflow namespace to the peers on the ports.
+type NetworkPolicyEgressRule struct {
+	// Destinations of the traffic. An empty list allows all destinations
+	To []networkingv1.NetworkPolicyPeer `json:"to,omitempty"`
+
+	// Destination ports of the traffic. An empty list allows all ports
+	Ports []NetworkPolicyPort `json:"ports,omitempty"`
+}
+
+// NetworkPolicyPort is a port, or a range of ports, on a protocol.
+type NetworkPolicyPort struct {
+	// Protocol of the traffic
+	// +kubebuilder:validation:Enum=TCP;UDP;SCTP
+	// +kubebuilder:default=TCP
+	Protocol string `json:"protocol,omitempty"`
+
+	// Numerical or named port. An empty port allows all ports
+	Port *intstr.IntOrString `json:"port,omitempty"`
+
+	// End of the range of ports starting at port
+	EndPort *int32 `json:"endPort,omitempty"`
 }
 
 type Eventing struct {
@@ -180,29 +384,67 @@ type MonitoringConfig struct {
 }
 
 type Broker struct {
-	// Name of existing Broker instance
+	// Name of existing Broker instance, or of the Broker instance to create
 	Name string `json:"name,omitempty"`
 
-	// Namespace of existing Broker instance
+	// Namespace of existing Broker instance. A Broker instance to create is created in the workflow namespace
 	Namespace string `json:"namespace,omitempty"`
+
+	// Determines whether the operator creates the Broker instance. The SonataFlowPlatform references the
+	// Broker once it is ready
+	Create bool `json:"create,omitempty"`
+
+	// Type of the Broker instance to create: InMemory, backed by InMemoryChannel, is intended for development
+	// and Kafka for production.
+	// Defaults to InMemory
+	// +kubebuilder:validation:Enum=InMemory;Kafka
+	Type BrokerType `json:"type,omitempty"`
+
+	// Configuration of the Kafka Broker instance to create
+	Kafka KafkaBroker `json:"kafka,omitempty"`
+}
+
+// BrokerType is the type of the Broker instance created by the operator
+type BrokerType string
+
+const (
+	// BrokerTypeInMemory is a Broker backed by InMemoryChannel, events are lost when the Broker restarts
+	BrokerTypeInMemory BrokerType = "InMemory"
+	// BrokerTypeKafka is a Broker backed by a Kafka cluster
+	BrokerTypeKafka BrokerType = "Kafka"
+)
+
+type KafkaBroker struct {
+	// Comma-separated list of the bootstrap servers of the Kafka cluster. Required for Kafka Broker instances
+	BootstrapServers string `json:"bootstrapServers,omitempty"`
+
+	// Number of partitions of the topic of the Broker
+	// Defaults to 10
+	// +kubebuilder:validation:Minimum=1
+	Partitions int32 `json:"partitions,omitempty"`
+
+	// Replication factor of the topic of the Broker, it cannot exceed the number of brokers of the Kafka cluster
+	// Defaults to 1
+	// +kubebuilder:validation:Minimum=1
+	ReplicationFactor int32 `json:"replicationFactor,omitempty"`
 }
 
 type Resource struct {
 	// Describe the minimum amount of compute resources required.
 	// Requests cannot exceed Limits. More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+	// Defaults to 64Mi of memory and 250m of CPU, or to the matching limit when it is lower.
 	Requests MemoryCpu `json:"requests,omitempty"`
 	// Describes the maximum amount of compute resources allowed.
+	// Defaults to 1Gi of memory and 500m of CPU.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/'
 	Limits MemoryCpu `json:"limits,omitempty"`
 }
 
 type MemoryCpu struct {
 	// Defines the memory resource limits
-	// +kubebuilder:default="1Gi"
 	Memory string `json:"memory,omitempty"`
 
 	// Defines the CPU resource limits
-	// +kubebuilder:default="500m"
 	Cpu string `json:"cpu,omitempty"`
 }
 
@@ -210,6 +452,85 @@ type Tekton struct {
 	// Determines whether to create the Tekton pipeline resources. Defaults to false.
 	// +kubebuilder:default=false
 	Enabled bool `json:"enabled,omitempty"`
+
+	// Namespace where the Tekton pipeline resources are created. Required when Tekton is enabled, defaults to the
+	// ArgoCD namespace
+	Namespace string `json:"namespace,omitempty"`
+
+	// Configuration of the workflow-deployment Pipeline and its Tasks. Optional
+	Pipeline TektonPipeline `json:"pipeline,omitempty"`
+}
+
+// TektonPipeline configures the workflow-deployment Pipeline, which builds the image of a workflow, pushes it to
+// a registry and pushes its manifests to the gitops repository of the workflow.
+type TektonPipeline struct {
+	// Host of the registry the workflow images are pushed to. Defaults to quay.io
+	RegistryHost string `json:"registryHost,omitempty"`
+
+	// Path of the workflow images in the registry, which can reference the pipeline params, i.e.
+	// my-project/$(params.workflowId). Defaults to $(params.quayOrgName)/$(params.quayRepoName)
+	ImagePathTemplate string `json:"imagePathTemplate,omitempty"`
+
+	// Identity of the commits pushed to the gitops repositories
+	GitAuthor GitAuthor `json:"gitAuthor,omitempty"`
+
+	// Home directory of the user of the git tasks. Defaults to /home/git
+	GitUserHome string `json:"gitUserHome,omitempty"`
+
+	// Tool building the workflow images: buildah, with the buildah Task of OpenShift Pipelines, or kaniko, with a
+	// Task created by the operator. Defaults to buildah
+	// +kubebuilder:validation:Enum=buildah;kaniko
+	Builder TektonBuilder `json:"builder,omitempty"`
+
+	// Images of the steps of the Tasks
+	Images TektonTaskImages `json:"images,omitempty"`
+
+	// Additional params of the Pipeline, which can be referenced by the image path template
+	// +listType=map
+	// +listMapKey=name
+	ExtraParams []TektonParam `json:"extraParams,omitempty"`
+}
+
+// TektonBuilder is the tool building the workflow images
+type TektonBuilder string
+
+const (
+	BuilderBuildah TektonBuilder = "buildah"
+	BuilderKaniko  TektonBuilder = "kaniko"
+)
+
+// GitAuthor is the identity of the commits pushed by the Pipeline.
+type GitAuthor struct {
+	// Name of the author. Defaults to The Orchestrator Tekton Pipeline
+	Name string `json:"name,omitempty"`
+
+	// Email of the author. Defaults to rhdhorchestrator@redhat.com
+	Email string `json:"email,omitempty"`
+}
+
+// TektonTaskImages are the images of the steps of the Tasks created by the operator.
+type TektonTaskImages struct {
+	// Image of the git-cli Task. Defaults to cgr.dev/chainguard/git
+	Git string `json:"git,omitempty"`
+
+	// Image of the flattener, build-manifests and build-gitops Tasks. Defaults to registry.access.redhat.com/ubi9-minimal
+	Base string `json:"base,omitempty"`
+
+	// Image of the kaniko Task. Defaults to gcr.io/kaniko-project/executor
+	Kaniko string `json:"kaniko,omitempty"`
+}
+
+// TektonParam is a string param of the Pipeline.
+type TektonParam struct {
+	// Name of the param
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_-]*$`
+	Name string `json:"name"`
+
+	// Description of the param
+	Description string `json:"description,omitempty"`
+
+	// Default value of the param. The param is required in the PipelineRuns when it is not set
+	Default *string `json:"default,omitempty"`
 }
 
 type ArgoCD struct {
@@ -220,25 +541,265 @@ type ArgoCD struct {
 	// Namespace where the ArgoCD operator is installed and watching for argoapp CR instances
 	// Ensure to add the Namespace if ArgoCD is installed
 	Namespace string `json:"namespace,omitempty"`
+
+	// Scope of the orchestrator-gitops AppProject. Optional
+	Project ArgoCDProject `json:"project,omitempty"`
+
+	// Workflows deployed from their gitops repository by an ArgoCD Application of the orchestrator-gitops
+	// AppProject. Optional
+	// +listType=map
+	// +listMapKey=id
+	Workflows []GitOpsWorkflow `json:"workflows,omitempty"`
+}
+
+// GitOpsWorkflow is a workflow deployed by an ArgoCD Application, named after the workflow ID, in the ArgoCD namespace.
+type GitOpsWorkflow struct {
+	// ID of the workflow, used as name of its Application
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	ID string `json:"id"`
+
+	// URL of the gitops repository of the workflow
+	RepoURL string `json:"repoURL"`
+
+	// Path of the manifests of the workflow in the gitops repository, i.e. kustomize/overlays/prod
+	Path string `json:"path"`
+
+	// Revision of the gitops repository to deploy. Defaults to HEAD
+	TargetRevision string `json:"targetRevision,omitempty"`
+
+	// Namespace where the workflow is deployed. Defaults to the workflow namespace of the platform
+	Namespace string `json:"namespace,omitempty"`
+
+	// Sync policy of the Application
+	SyncPolicy GitOpsSyncPolicy `json:"syncPolicy,omitempty"`
+}
+
+// GitOpsSyncPolicy configures how ArgoCD syncs the Application of a workflow.
+type GitOpsSyncPolicy struct {
+	// Whether ArgoCD syncs the Application automatically when the gitops repository changes
+	Automated bool `json:"automated,omitempty"`
+
+	// Whether the automated sync deletes the resources removed from the gitops repository
+	Prune bool `json:"prune,omitempty"`
+
+	// Whether the automated sync reverts the changes made to the resources in the cluster
+	SelfHeal bool `json:"selfHeal,omitempty"`
+
+	// Options of the sync, i.e. CreateNamespace=true
+	SyncOptions []string `json:"syncOptions,omitempty"`
+}
+
+// ArgoCDProject configures the spec of the orchestrator-gitops AppProject. The source repositories and the
+// destinations default to all repositories and destinations when they are not set.
+type ArgoCDProject struct {
+	// Patterns of the repositories the applications of the project can be deployed from, i.e.
+	// https://github.com/my-org/*. Defaults to all repositories
+	SourceRepos []string `json:"sourceRepos,omitempty"`
+
+	// Clusters and namespaces the applications of the project can be deployed to. Defaults to all destinations
+	Destinations []ArgoCDDestination `json:"destinations,omitempty"`
+
+	// Cluster-scoped resources the applications of the project can deploy. No cluster-scoped resource is
+	// allowed when it is not set
+	ClusterResourceWhitelist []metav1.GroupKind `json:"clusterResourceWhitelist,omitempty"`
+
+	// Cluster-scoped resources the applications of the project cannot deploy
+	ClusterResourceBlacklist []metav1.GroupKind `json:"clusterResourceBlacklist,omitempty"`
+
+	// Roles of the project, granting access to its applications
+	Roles []ArgoCDProjectRole `json:"roles,omitempty"`
+
+	// Time windows during which the applications of the project can or cannot be synced
+	SyncWindows []ArgoCDSyncWindow `json:"syncWindows,omitempty"`
+}
+
+// ArgoCDDestination is a cluster, identified by its server URL or name, and a namespace of this cluster.
+// Both support patterns, i.e. team-*
+type ArgoCDDestination struct {
+	// URL of the API server of the cluster
+	Server string `json:"server,omitempty"`
+
+	// Name of the cluster
+	Name string `json:"name,omitempty"`
+
+	// Namespace of the cluster
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ArgoCDProjectRole is a role of the AppProject.
+type ArgoCDProjectRole struct {
+	// Name of the role
+	Name string `json:"name"`
+
+	// Description of the role
+	Description string `json:"description,omitempty"`
+
+	// Casbin policies of the role, i.e. p, proj:orchestrator-gitops:read-only, applications, get, orchestrator-gitops/*, allow
+	Policies []string `json:"policies,omitempty"`
+
+	// OIDC groups the role is granted to
+	Groups []string `json:"groups,omitempty"`
+}
+
+// ArgoCDSyncWindow is a time window during which the applications of the AppProject can or cannot be synced.
+type ArgoCDSyncWindow struct {
+	// Whether the syncs are allowed or denied during the window
+	// +kubebuilder:validation:Enum=allow;deny
+	Kind string `json:"kind"`
+
+	// Cron schedule of the start of the window, i.e. 0 22 * * *
+	Schedule string `json:"schedule"`
+
+	// Duration of the window, i.e. 1h
+	Duration string `json:"duration"`
+
+	// Patterns of the applications the window applies to
+	Applications []string `json:"applications,omitempty"`
+
+	// Patterns of the namespaces the window applies to
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Patterns of the clusters the window applies to
+	Clusters []string `json:"clusters,omitempty"`
+
+	// Whether manual syncs are allowed during a deny window
+	ManualSync bool `json:"manualSync,omitempty"`
+
+	// Time zone of the schedule. Defaults to UTC
+	TimeZone string `json:"timeZone,omitempty"`
 }
 
 type OrchestratorPhase string
 
 // OrchestratorStatus defines the observed state of Orchestrator
 type OrchestratorStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Conditions of the Orchestrator. Each managed subsystem reports its own condition (ServerlessLogicReady,
+	// KnativeReady, RHDHReady, NetworkPoliciesReady, ArgoCDReady, TektonReady, PostgresReachable)
+	// and the Ready condition summarizes all of them. The DeletionPlanned condition lists the resources removed
+	// once the Orchestrator is deleted, and the OperatorsUninstalled condition the resources removed when an
+	// installOperator flag is switched off.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 	// +kubebuilder:validation:Enum={"Running","Completed", "Failed"}
 	Phase OrchestratorPhase `json:"phase,omitempty" protobuf:"bytes,1,opt,casttype=OrchestratorPhase"`
+
+	// InstallPlans of the managed operators waiting for approval
+	PendingInstallPlans []PendingInstallPlan `json:"pendingInstallPlans,omitempty"`
+
+	// Installation status of the managed operators
+	Operators []OperatorStatus `json:"operators,omitempty"`
+
+	// Namespaces where the network policies of the Orchestrator were applied. The network policies that are not
+	// desired anymore are deleted from these namespaces, i.e. after a change of the workflow namespace
+	NetworkPolicyNamespaces []string `json:"networkPolicyNamespaces,omitempty"`
+
+	// Sync and health status of the Applications of the gitops workflows
+	Workflows []WorkflowStatus `json:"workflows,omitempty"`
+
+	// Versions of the Tekton Tasks and Pipeline created by the operator
+	TektonResources []TektonResourceStatus `json:"tektonResources,omitempty"`
+}
+
+// TektonResourceStatus describes the version of a Tekton Task or Pipeline created by the operator.
+type TektonResourceStatus struct {
+	// Kind of the resource: Task or Pipeline
+	Kind string `json:"kind"`
+
+	// Name of the resource
+	Name string `json:"name"`
+
+	// Version of the operator that last rendered the resource
+	OperatorVersion string `json:"operatorVersion,omitempty"`
+
+	// Hash of the spec the operator last rendered into the resource
+	ContentHash string `json:"contentHash,omitempty"`
+}
+
+// WorkflowStatus describes the ArgoCD Application of a gitops workflow.
+type WorkflowStatus struct {
+	// ID of the workflow
+	ID string `json:"id"`
+
+	// Name of the Application of the workflow
+	Application string `json:"application"`
+
+	// Sync status of the Application: Synced, OutOfSync or Unknown
+	SyncStatus string `json:"syncStatus,omitempty"`
+
+	// Health status of the Application, i.e. Healthy, Progressing or Degraded
+	HealthStatus string `json:"healthStatus,omitempty"`
+
+	// Revision of the gitops repository the Application is synced to
+	Revision string `json:"revision,omitempty"`
+
+	// Human-readable details about the conditions of the Application, or the conflict with an existing Application
+	// not created by the Orchestrator
+	Message string `json:"message,omitempty"`
+}
+
+// OperatorStatus describes the ClusterServiceVersion installed by the Subscription of a managed operator.
+type OperatorStatus struct {
+	// Name of the Subscription of the operator, empty for an operator installed without Subscription
+	Subscription string `json:"subscription"`
+
+	// Namespace of the Subscription of the operator, or of the ClusterServiceVersion of an operator
+	// installed without Subscription
+	Namespace string `json:"namespace"`
+
+	// Ownership of the operator installation: Managed when installed by the Orchestrator operator, Detected when
+	// installed outside of it while installOperator is true, and Unmanaged when installOperator is false
+	// +kubebuilder:validation:Enum=Managed;Detected;Unmanaged
+	Ownership OperatorOwnership `json:"ownership,omitempty"`
+
+	// ClusterServiceVersion installed by the Subscription
+	InstalledCSV string `json:"installedCSV,omitempty"`
+
+	// Phase of the installed ClusterServiceVersion
+	Phase string `json:"phase,omitempty"`
+
+	// Reason of the phase of the installed ClusterServiceVersion
+	Reason string `json:"reason,omitempty"`
+
+	// Human-readable details about the phase of the installed ClusterServiceVersion
+	Message string `json:"message,omitempty"`
+}
+
+// OperatorOwnership describes who installed an operator required by the Orchestrator
+type OperatorOwnership string
+
+const (
+	// OperatorManaged is an operator installed by the Orchestrator operator
+	OperatorManaged OperatorOwnership = "Managed"
+	// OperatorDetected is an operator installed outside of the Orchestrator operator, which does not install it
+	// again nor manage its Subscription
+	OperatorDetected OperatorOwnership = "Detected"
+	// OperatorUnmanaged is an operator found while its installation is disabled in the Orchestrator
+	OperatorUnmanaged OperatorOwnership = "Unmanaged"
+)
+
+// PendingInstallPlan describes an InstallPlan that was not approved by the approval policy.
+type PendingInstallPlan struct {
+	// Name of the InstallPlan
+	Name string `json:"name"`
+
+	// Namespace of the InstallPlan
+	Namespace string `json:"namespace"`
+
+	// Name of the Subscription the InstallPlan was created for
+	Subscription string `json:"subscription"`
+
+	// ClusterServiceVersion the InstallPlan would install
+	CSV string `json:"csv"`
 }
 
 //+kubebuilder:object:root=true
 
 // Orchestrator is the Schema for the orchestrators API
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp",description="Age"
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=".status.phase",description="Status"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Ready"
 // +kubebuilder:metadata:annotations=orchestrator-package=backstage-plugin-orchestrator@1.6.1
 // +kubebuilder:metadata:annotations=orchestrator-integrity=sha512-6qQ/TLvrf4+gDhrF5JtKQ51hTrNkhEw0jE4lWvLmhauZKeD0EeJVYOlbAvDJZjmx7iJZXLFFydR6EnYuaHBZ+A==
 // +kubebuilder:metadata:annotations=orchestrator-backend-dynamic-package=backstage-plugin-orchestrator-backend-dynamic@1.6.1
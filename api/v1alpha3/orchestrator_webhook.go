@@ -0,0 +1,485 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	knativev1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// EnvironmentLabelKey is the namespace label used to identify the environment of a namespace.
+	EnvironmentLabelKey = "rhdh.redhat.com/environment"
+	// ProductionEnvironment is the value of EnvironmentLabelKey for production namespaces.
+	// RHDH instances deployed in those namespaces cannot enable the development mode.
+	ProductionEnvironment = "production"
+
+	// DefaultRequestsMemory is the memory requested by the Data Index and Job Service when none is set, unless the
+	// memory limit is lower.
+	DefaultRequestsMemory = "64Mi"
+	// DefaultRequestsCpu is the CPU requested by the Data Index and Job Service when none is set, unless the CPU
+	// limit is lower.
+	DefaultRequestsCpu = "250m"
+	// DefaultLimitsMemory is the memory limit of the Data Index and Job Service when none is set.
+	DefaultLimitsMemory = "1Gi"
+	// DefaultLimitsCpu is the CPU limit of the Data Index and Job Service when none is set.
+	DefaultLimitsCpu = "500m"
+	// DefaultNotificationsEmailPort is the SMTP port used by the Notifications Email plugin when none is set.
+	DefaultNotificationsEmailPort = 587
+
+	sha512IntegrityPrefix = "sha512-"
+	localPackagePrefix    = "./"
+)
+
+// pipelineParams are the params declared by the workflow-deployment Pipeline.
+var pipelineParams = []string{"gitUrl", "gitOpsUrl", "workflowId", "convertToFlat", "quayOrgName", "quayRepoName"}
+
+// pipelineParamReference matches the params referenced by a value of the Pipeline.
+var pipelineParamReference = regexp.MustCompile(`\$\(params\.([^)]*)\)`)
+
+// log is for logging in this package.
+var orchestratorlog = logf.Log.WithName("orchestrator-resource")
+
+// SetupWebhookWithManager will setup the manager to manage the webhooks
+func (r *Orchestrator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(&OrchestratorCustomDefaulter{}).
+		WithValidator(&OrchestratorCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-rhdh-redhat-com-v1alpha3-orchestrator,mutating=true,failurePolicy=fail,sideEffects=None,groups=rhdh.redhat.com,resources=orchestrators,verbs=create;update,versions=v1alpha3,name=morchestrator.kb.io,admissionReviewVersions=v1
+
+// OrchestratorCustomDefaulter sets the default values of the Orchestrator resource when it is created or updated.
+// +kubebuilder:object:generate=false
+type OrchestratorCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &OrchestratorCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type
+func (d *OrchestratorCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	orchestrator, ok := obj.(*Orchestrator)
+	if !ok {
+		return fmt.Errorf("expected an Orchestrator object but got %T", obj)
+	}
+	orchestratorlog.Info("default", "name", orchestrator.Name)
+
+	orchestrator.Default()
+	return nil
+}
+
+// Default sets the values the CRD schema defaults cannot express, such as the fields of
+// nested structs that were omitted altogether.
+func (r *Orchestrator) Default() {
+	resources := &r.Spec.PlatformConfig.Resources
+	setDefault(&resources.Limits.Memory, DefaultLimitsMemory)
+	setDefault(&resources.Limits.Cpu, DefaultLimitsCpu)
+	setDefaultRequest(&resources.Requests.Memory, DefaultRequestsMemory, resources.Limits.Memory)
+	setDefaultRequest(&resources.Requests.Cpu, DefaultRequestsCpu, resources.Limits.Cpu)
+
+	notifications := &r.Spec.RHDHConfig.RHDHPlugins.NotificationsConfig
+	if notifications.Enabled && notifications.Port == 0 {
+		notifications.Port = DefaultNotificationsEmailPort
+	}
+
+	// the Tekton resources were created in the ArgoCD namespace before Tekton had its own namespace
+	if r.Spec.Tekton.Enabled {
+		setDefault(&r.Spec.Tekton.Namespace, r.Spec.ArgoCd.Namespace)
+	}
+}
+
+func setDefault(value *string, defaultValue string) {
+	if *value == "" {
+		*value = defaultValue
+	}
+}
+
+// setDefaultRequest defaults an unset request to defaultValue, or to limit when it is lower, so that the defaulted
+// request never exceeds the effective limit.
+func setDefaultRequest(value *string, defaultValue, limit string) {
+	if *value != "" {
+		return
+	}
+	*value = defaultValue
+	limitQuantity, err := resource.ParseQuantity(limit)
+	if err != nil {
+		// left to the validating webhook
+		return
+	}
+	if defaultQuantity := resource.MustParse(defaultValue); defaultQuantity.Cmp(limitQuantity) > 0 {
+		*value = limit
+	}
+}
+
+// +kubebuilder:webhook:path=/validate-rhdh-redhat-com-v1alpha3-orchestrator,mutating=false,failurePolicy=fail,sideEffects=None,groups=rhdh.redhat.com,resources=orchestrators,verbs=create;update,versions=v1alpha3,name=vorchestrator.kb.io,admissionReviewVersions=v1
+
+// OrchestratorCustomValidator validates the Orchestrator resource when it is created or updated.
+// +kubebuilder:object:generate=false
+type OrchestratorCustomValidator struct {
+	// Client is used to look up the namespaces referenced by the Orchestrator.
+	Client client.Reader
+}
+
+var _ webhook.CustomValidator = &OrchestratorCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *OrchestratorCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	orchestrator, ok := obj.(*Orchestrator)
+	if !ok {
+		return nil, fmt.Errorf("expected an Orchestrator object but got %T", obj)
+	}
+	orchestratorlog.Info("validate create", "name", orchestrator.Name)
+
+	return nil, v.validateOrchestrator(ctx, orchestrator)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *OrchestratorCustomValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	orchestrator, ok := newObj.(*Orchestrator)
+	if !ok {
+		return nil, fmt.Errorf("expected an Orchestrator object but got %T", newObj)
+	}
+	orchestratorlog.Info("validate update", "name", orchestrator.Name)
+
+	// allow the finalizer to be removed from an Orchestrator being deleted, whatever its spec
+	if !orchestrator.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	return nil, v.validateOrchestrator(ctx, orchestrator)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *OrchestratorCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *OrchestratorCustomValidator) validateOrchestrator(ctx context.Context, orchestrator *Orchestrator) error {
+	specPath := field.NewPath("spec")
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateResources(orchestrator.Spec.PlatformConfig.Resources, specPath.Child("platform", "resources"))...)
+	allErrs = append(allErrs, validateBroker(orchestrator.Spec.PlatformConfig.Eventing.Broker, orchestrator.Spec.PlatformConfig.Namespace, specPath.Child("platform", "eventing", "broker"))...)
+	allErrs = append(allErrs, validateNetworkPolicies(orchestrator.Spec.PlatformConfig.NetworkPolicies, specPath.Child("platform", "networkPolicies"))...)
+	allErrs = append(allErrs, validateArgoCD(orchestrator.Spec.ArgoCd, specPath.Child("argocd"))...)
+	allErrs = append(allErrs, validateTekton(orchestrator.Spec.Tekton, specPath.Child("tekton"))...)
+	allErrs = append(allErrs, validateKnative(orchestrator.Spec.ServerlessOperator.Knative, specPath.Child("serverless", "knative"))...)
+	allErrs = append(allErrs, validatePluginOverrides(orchestrator.Spec.RHDHConfig.RHDHPlugins.Overrides, specPath.Child("rhdh", "plugins", "overrides"))...)
+
+	devModeErrs, err := v.validateDevMode(ctx, orchestrator.Spec.RHDHConfig, specPath.Child("rhdh"))
+	if err != nil {
+		return err
+	}
+	allErrs = append(allErrs, devModeErrs...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("Orchestrator").GroupKind(), orchestrator.Name, allErrs)
+}
+
+// validateResources ensures the resource quantities can be parsed and that requests do not exceed limits.
+func validateResources(resources Resource, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	requests, errs := parseMemoryCpu(resources.Requests, fldPath.Child("requests"))
+	allErrs = append(allErrs, errs...)
+	limits, errs := parseMemoryCpu(resources.Limits, fldPath.Child("limits"))
+	allErrs = append(allErrs, errs...)
+
+	for _, name := range []string{"cpu", "memory"} {
+		request, requestSet := requests[name]
+		limit, limitSet := limits[name]
+		if requestSet && limitSet && request.Cmp(limit) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("requests", name), request.String(),
+				fmt.Sprintf("must be less than or equal to %s limit %s", name, limit.String())))
+		}
+	}
+	return allErrs
+}
+
+// parseMemoryCpu parses the non-empty quantities of a MemoryCpu and returns them keyed by their field name.
+func parseMemoryCpu(memoryCpu MemoryCpu, fldPath *field.Path) (map[string]resource.Quantity, field.ErrorList) {
+	var allErrs field.ErrorList
+	quantities := map[string]resource.Quantity{}
+
+	for _, entry := range []struct{ name, value string }{{"cpu", memoryCpu.Cpu}, {"memory", memoryCpu.Memory}} {
+		name, value := entry.name, entry.value
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(name), value, err.Error()))
+			continue
+		}
+		quantities[name] = quantity
+	}
+	return quantities, allErrs
+}
+
+// validateBroker ensures a broker reference is either empty or has both a name and a namespace, and that a broker
+// created by the operator has a name, lives in the workflow namespace and, for Kafka, has bootstrap servers.
+func validateBroker(broker Broker, workflowNamespace string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if broker.Create {
+		if broker.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("name"), "must be set when the broker is created"))
+		}
+		if broker.Namespace != "" && broker.Namespace != workflowNamespace {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("namespace"), broker.Namespace,
+				fmt.Sprintf("must be empty or the workflow namespace %s when the broker is created", workflowNamespace)))
+		}
+		if broker.Type == BrokerTypeKafka && broker.Kafka.BootstrapServers == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("kafka", "bootstrapServers"), "must be set for a Kafka broker"))
+		}
+		return allErrs
+	}
+	if broker.Name != "" && broker.Namespace == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("namespace"), "must be set when the broker name is set"))
+	}
+	if broker.Name == "" && broker.Namespace != "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "must be set when the broker namespace is set"))
+	}
+	return allErrs
+}
+
+// validateNetworkPolicies ensures every peer of the network policies selects pods, namespaces or an IP block, but
+// not both, that the IP blocks are valid CIDRs which contain their exceptions, and that port ranges are valid.
+func validateNetworkPolicies(networkPolicies NetworkPolicies, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, peer := range networkPolicies.AdditionalIngress {
+		allErrs = append(allErrs, validateNetworkPolicyPeer(peer, fldPath.Child("additionalIngress").Index(i))...)
+	}
+	for i, rule := range networkPolicies.Egress {
+		rulePath := fldPath.Child("egress").Index(i)
+		for j, peer := range rule.To {
+			allErrs = append(allErrs, validateNetworkPolicyPeer(peer, rulePath.Child("to").Index(j))...)
+		}
+		for j, port := range rule.Ports {
+			if port.EndPort == nil {
+				continue
+			}
+			portPath := rulePath.Child("ports").Index(j)
+			if port.Port == nil || port.Port.Type != intstr.Int {
+				allErrs = append(allErrs, field.Required(portPath.Child("port"), "must be a number when endPort is set"))
+			} else if *port.EndPort < port.Port.IntVal {
+				allErrs = append(allErrs, field.Invalid(portPath.Child("endPort"), *port.EndPort, "must not be lower than port"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateNetworkPolicyPeer(peer networkingv1.NetworkPolicyPeer, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	hasSelector := peer.PodSelector != nil || peer.NamespaceSelector != nil
+	if !hasSelector && peer.IPBlock == nil {
+		return append(allErrs, field.Required(fldPath, "must set podSelector, namespaceSelector or ipBlock"))
+	}
+	if hasSelector && peer.IPBlock != nil {
+		return append(allErrs, field.Forbidden(fldPath.Child("ipBlock"), "cannot be set with podSelector or namespaceSelector"))
+	}
+	if peer.IPBlock == nil {
+		return allErrs
+	}
+	_, cidr, err := net.ParseCIDR(peer.IPBlock.CIDR)
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath.Child("ipBlock", "cidr"), peer.IPBlock.CIDR, err.Error()))
+	}
+	for i, except := range peer.IPBlock.Except {
+		exceptIP, _, err := net.ParseCIDR(except)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ipBlock", "except").Index(i), except, err.Error()))
+		} else if !cidr.Contains(exceptIP) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ipBlock", "except").Index(i), except,
+				fmt.Sprintf("must be within %s", peer.IPBlock.CIDR)))
+		}
+	}
+	return allErrs
+}
+
+// validateArgoCD ensures the ArgoCD namespace is set when ArgoCD is enabled, and that the destinations of the
+// AppProject identify a cluster, that its sync windows have a valid duration, and that the workflows only prune or
+// self-heal with an automated sync.
+func validateArgoCD(argoCD ArgoCD, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if argoCD.Enabled && argoCD.Namespace == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("namespace"), "must be set when ArgoCD is enabled"))
+	}
+	projectPath := fldPath.Child("project")
+	for i, destination := range argoCD.Project.Destinations {
+		if destination.Server == "" && destination.Name == "" {
+			allErrs = append(allErrs, field.Required(projectPath.Child("destinations").Index(i),
+				"server or name must be set"))
+		}
+	}
+	for i, syncWindow := range argoCD.Project.SyncWindows {
+		if _, err := time.ParseDuration(syncWindow.Duration); err != nil {
+			allErrs = append(allErrs, field.Invalid(projectPath.Child("syncWindows").Index(i).Child("duration"),
+				syncWindow.Duration, err.Error()))
+		}
+	}
+	for i, workflow := range argoCD.Workflows {
+		syncPolicy := workflow.SyncPolicy
+		if !syncPolicy.Automated && (syncPolicy.Prune || syncPolicy.SelfHeal) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("workflows").Index(i).Child("syncPolicy", "automated"),
+				syncPolicy.Automated, "must be true when prune or selfHeal is set"))
+		}
+	}
+	return allErrs
+}
+
+// validateTekton ensures the Tekton namespace is set when Tekton is enabled, and validates the Pipeline.
+func validateTekton(tekton Tekton, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if tekton.Enabled && tekton.Namespace == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("namespace"), "must be set when Tekton is enabled"))
+	}
+	return append(allErrs, validateTektonPipeline(tekton.Pipeline, fldPath.Child("pipeline"))...)
+}
+
+// validateTektonPipeline ensures the extra params of the Pipeline do not redeclare its own params, and that the
+// image path template only references params of the Pipeline.
+func validateTektonPipeline(pipeline TektonPipeline, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	params := slices.Clone(pipelineParams)
+	for i, param := range pipeline.ExtraParams {
+		if slices.Contains(pipelineParams, param.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("extraParams").Index(i).Child("name"), param.Name,
+				"must not be a param of the Pipeline"))
+		}
+		params = append(params, param.Name)
+	}
+	for _, match := range pipelineParamReference.FindAllStringSubmatch(pipeline.ImagePathTemplate, -1) {
+		if !slices.Contains(params, match[1]) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("imagePathTemplate"), pipeline.ImagePathTemplate,
+				fmt.Sprintf("references the unknown param %s", match[1])))
+		}
+	}
+	return allErrs
+}
+
+// validateKnative ensures the Knative specs can be decoded into the specs of the KnativeServing and
+// KnativeEventing CRs, so that unknown fields are not silently dropped.
+func validateKnative(knative Knative, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if knative.Serving != nil {
+		if err := decodeStrict(knative.Serving.Raw, &knativev1beta1.KnativeServingSpec{}); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("serving"), string(knative.Serving.Raw), err.Error()))
+		}
+	}
+	if knative.Eventing != nil {
+		if err := decodeStrict(knative.Eventing.Raw, &knativev1beta1.KnativeEventingSpec{}); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("eventing"), string(knative.Eventing.Raw), err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// decodeStrict decodes data into out, rejecting the fields unknown to out.
+func decodeStrict(data []byte, out any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(out)
+}
+
+// validatePluginOverrides ensures the plugin overrides reference a package without its version, have a valid
+// SHA-512 integrity, which is required to change the version of a package from a registry, and a plugin
+// configuration that is an object. The packages bundled with RHDH have no version.
+func validatePluginOverrides(overrides []PluginOverride, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, override := range overrides {
+		overridePath := fldPath.Index(i)
+		if strings.LastIndex(override.Package, "@") > 0 {
+			allErrs = append(allErrs, field.Invalid(overridePath.Child("package"), override.Package,
+				"must not include the version of the package, which is set with the version field"))
+		}
+		if override.Integrity != "" && !isSHA512Integrity(override.Integrity) {
+			allErrs = append(allErrs, field.Invalid(overridePath.Child("integrity"), override.Integrity,
+				"must be sha512- followed by the base64 encoded SHA-512 digest of the package"))
+		}
+		if override.Version != "" && strings.HasPrefix(override.Package, localPackagePrefix) {
+			allErrs = append(allErrs, field.Invalid(overridePath.Child("version"), override.Version,
+				"cannot be set for a package bundled with RHDH"))
+		} else if override.Version != "" && override.Integrity == "" {
+			allErrs = append(allErrs, field.Required(overridePath.Child("integrity"), "must be set when the version is set"))
+		}
+		if override.PluginConfig != nil {
+			pluginConfig := map[string]any{}
+			if err := json.Unmarshal(override.PluginConfig.Raw, &pluginConfig); err != nil {
+				allErrs = append(allErrs, field.Invalid(overridePath.Child("pluginConfig"), string(override.PluginConfig.Raw), "must be an object"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// isSHA512Integrity returns whether integrity is a subresource integrity of a SHA-512 digest.
+func isSHA512Integrity(integrity string) bool {
+	digest, found := strings.CutPrefix(integrity, sha512IntegrityPrefix)
+	if !found {
+		return false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(digest)
+	return err == nil && len(decoded) == sha512.Size
+}
+
+// validateDevMode rejects the development mode for RHDH instances deployed in production namespaces.
+func (v *OrchestratorCustomValidator) validateDevMode(ctx context.Context, rhdhConfig RHDHConfig, fldPath *field.Path) (field.ErrorList, error) {
+	if !rhdhConfig.DevMode || rhdhConfig.Namespace == "" || v.Client == nil {
+		return nil, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := v.Client.Get(ctx, types.NamespacedName{Name: rhdhConfig.Namespace}, namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, apierrors.NewInternalError(fmt.Errorf("failed to retrieve namespace %s: %w", rhdhConfig.Namespace, err))
+	}
+
+	if namespace.Labels[EnvironmentLabelKey] == ProductionEnvironment {
+		return field.ErrorList{field.Forbidden(fldPath.Child("devMode"),
+			fmt.Sprintf("cannot be enabled in namespace %s labelled %s=%s", rhdhConfig.Namespace, EnvironmentLabelKey, ProductionEnvironment))}, nil
+	}
+	return nil, nil
+}
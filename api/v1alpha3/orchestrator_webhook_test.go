@@ -0,0 +1,420 @@
+package v1alpha3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	rhdhNamespace   = "rhdh-operator"
+	pluginIntegrity = "sha512-6qQ/TLvrf4+gDhrF5JtKQ51hTrNkhEw0jE4lWvLmhauZKeD0EeJVYOlbAvDJZjmx7iJZXLFFydR6EnYuaHBZ+A=="
+)
+
+func TestValidateOrchestrator(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+
+	productionNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   rhdhNamespace,
+			Labels: map[string]string{EnvironmentLabelKey: ProductionEnvironment},
+		},
+	}
+	developmentNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: rhdhNamespace}}
+	disabled := true
+	httpsPort, namedPort := intstr.FromInt32(443), intstr.FromString("https")
+	endPort, lowEndPort := int32(8443), int32(80)
+
+	testCases := []struct {
+		name           string
+		mutate         func(orchestrator *Orchestrator)
+		objects        []client.Object
+		expectedFields []string
+	}{
+		{
+			name:   "Valid orchestrator",
+			mutate: func(orchestrator *Orchestrator) {},
+		},
+		{
+			name: "Unparsable resource quantity",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.PlatformConfig.Resources.Limits.Memory = "one gigabyte"
+			},
+			expectedFields: []string{"spec.platform.resources.limits.memory"},
+		},
+		{
+			name: "Requests exceed limits",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.PlatformConfig.Resources.Requests = MemoryCpu{Cpu: "500m", Memory: "2Gi"}
+				orchestrator.Spec.PlatformConfig.Resources.Limits = MemoryCpu{Cpu: "250m", Memory: "1Gi"}
+			},
+			expectedFields: []string{"spec.platform.resources.requests.cpu", "spec.platform.resources.requests.memory"},
+		},
+		{
+			name: "Broker name without namespace",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.PlatformConfig.Eventing.Broker = Broker{Name: "kafka-broker"}
+			},
+			expectedFields: []string{"spec.platform.eventing.broker.namespace"},
+		},
+		{
+			name: "Broker namespace without name",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.PlatformConfig.Eventing.Broker = Broker{Namespace: "sonataflow-infra"}
+			},
+			expectedFields: []string{"spec.platform.eventing.broker.name"},
+		},
+		{
+			name: "Broker created in the workflow namespace",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.PlatformConfig.Eventing.Broker = Broker{
+					Name:   "kafka-broker",
+					Create: true,
+					Type:   BrokerTypeKafka,
+					Kafka:  KafkaBroker{BootstrapServers: "my-cluster-kafka-bootstrap.kafka:9092"},
+				}
+			},
+		},
+		{
+			name: "Invalid broker to create",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.PlatformConfig.Eventing.Broker = Broker{Namespace: "knative", Create: true, Type: BrokerTypeKafka}
+			},
+			expectedFields: []string{
+				"spec.platform.eventing.broker.name",
+				"spec.platform.eventing.broker.namespace",
+				"spec.platform.eventing.broker.kafka.bootstrapServers",
+			},
+		},
+		{
+			name: "ArgoCD enabled without namespace",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.ArgoCd = ArgoCD{Enabled: true}
+			},
+			expectedFields: []string{"spec.argocd.namespace"},
+		},
+		{
+			name: "Tekton enabled without namespace",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.Tekton.Enabled = true
+			},
+			expectedFields: []string{"spec.tekton.namespace"},
+		},
+		{
+			name: "Tekton enabled without ArgoCD namespace",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.Tekton = Tekton{Enabled: true, Namespace: "orchestrator-tekton"}
+			},
+		},
+		{
+			name: "Valid plugin overrides",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.RHDHConfig.RHDHPlugins.Overrides = []PluginOverride{
+					{Package: "@redhat/backstage-plugin-orchestrator", Version: "1.6.2", Integrity: pluginIntegrity},
+					{Package: "./dynamic-plugins/dist/backstage-plugin-notifications", Disabled: &disabled},
+					{Package: "@redhat/backstage-plugin-orchestrator-backend-dynamic", PluginConfig: &apiextensionsv1.JSON{Raw: []byte(`{"orchestrator":{}}`)}},
+				}
+			},
+		},
+		{
+			name: "Invalid plugin overrides",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.RHDHConfig.RHDHPlugins.Overrides = []PluginOverride{
+					{Package: "@redhat/backstage-plugin-orchestrator@1.6.2", Integrity: pluginIntegrity},
+					{Package: "@redhat/backstage-plugin-orchestrator-backend-dynamic", Integrity: "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="},
+					{Package: "@redhat/backstage-plugin-orchestrator-form-widgets", Version: "1.6.2"},
+					{Package: "@redhat/backstage-plugin-scaffolder-backend-module-orchestrator-dynamic", PluginConfig: &apiextensionsv1.JSON{Raw: []byte(`[]`)}},
+					{Package: "./dynamic-plugins/dist/backstage-plugin-signals", Version: "1.6.2"},
+				}
+			},
+			expectedFields: []string{
+				"spec.rhdh.plugins.overrides[0].package",
+				"spec.rhdh.plugins.overrides[1].integrity",
+				"spec.rhdh.plugins.overrides[2].integrity",
+				"spec.rhdh.plugins.overrides[3].pluginConfig",
+				"spec.rhdh.plugins.overrides[4].version",
+			},
+		},
+		{
+			name: "Valid Knative specs",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.ServerlessOperator.Knative = Knative{
+					Serving:  &apiextensionsv1.JSON{Raw: []byte(`{"high-availability":{"replicas":2},"config":{"network":{"ingress-class":"kourier.ingress.networking.knative.dev"}}}`)},
+					Eventing: &apiextensionsv1.JSON{Raw: []byte(`{"defaultBrokerClass":"Kafka"}`)},
+				}
+			},
+		},
+		{
+			name: "Invalid Knative specs",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.ServerlessOperator.Knative = Knative{
+					Serving:  &apiextensionsv1.JSON{Raw: []byte(`{"highAvailability":{"replicas":2}}`)},
+					Eventing: &apiextensionsv1.JSON{Raw: []byte(`{"defaultBrokerClass":1}`)},
+				}
+			},
+			expectedFields: []string{"spec.serverless.knative.serving", "spec.serverless.knative.eventing"},
+		},
+		{
+			name: "Valid network policies",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.PlatformConfig.NetworkPolicies = NetworkPolicies{
+					AdditionalIngress: []networkingv1.NetworkPolicyPeer{
+						{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "gateway"}}},
+						{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/16", Except: []string{"10.0.1.0/24"}}},
+					},
+					Egress: []NetworkPolicyEgressRule{
+						{
+							To:    []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0"}}},
+							Ports: []NetworkPolicyPort{{Port: &httpsPort}, {Port: &httpsPort, EndPort: &endPort}},
+						},
+					},
+				}
+			},
+		},
+		{
+			name: "Invalid network policies",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.PlatformConfig.NetworkPolicies = NetworkPolicies{
+					AdditionalIngress: []networkingv1.NetworkPolicyPeer{
+						{},
+						{PodSelector: &metav1.LabelSelector{}, IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/16"}},
+						{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/16", Except: []string{"192.168.0.0/24"}}},
+					},
+					Egress: []NetworkPolicyEgressRule{
+						{
+							To:    []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.300/8"}}},
+							Ports: []NetworkPolicyPort{{Port: &namedPort, EndPort: &endPort}, {Port: &httpsPort, EndPort: &lowEndPort}},
+						},
+					},
+				}
+			},
+			expectedFields: []string{
+				"spec.platform.networkPolicies.additionalIngress[0]",
+				"spec.platform.networkPolicies.additionalIngress[1].ipBlock",
+				"spec.platform.networkPolicies.additionalIngress[2].ipBlock.except[0]",
+				"spec.platform.networkPolicies.egress[0].to[0].ipBlock.cidr",
+				"spec.platform.networkPolicies.egress[0].ports[0].port",
+				"spec.platform.networkPolicies.egress[0].ports[1].endPort",
+			},
+		},
+		{
+			name: "Valid ArgoCD project",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.ArgoCd.Project = ArgoCDProject{
+					SourceRepos:  []string{"https://github.com/my-org/*"},
+					Destinations: []ArgoCDDestination{{Server: "https://kubernetes.default.svc", Namespace: "workflows-*"}},
+					SyncWindows:  []ArgoCDSyncWindow{{Kind: "deny", Schedule: "0 22 * * *", Duration: "8h"}},
+				}
+			},
+		},
+		{
+			name: "Invalid ArgoCD project",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.ArgoCd.Project = ArgoCDProject{
+					Destinations: []ArgoCDDestination{{Namespace: "workflows"}},
+					SyncWindows:  []ArgoCDSyncWindow{{Kind: "deny", Schedule: "0 22 * * *", Duration: "8 hours"}},
+				}
+				orchestrator.Spec.ArgoCd.Workflows = []GitOpsWorkflow{
+					{ID: "greeting", RepoURL: "https://github.com/my-org/greeting-gitops", Path: "kustomize", SyncPolicy: GitOpsSyncPolicy{Prune: true}},
+				}
+			},
+			expectedFields: []string{
+				"spec.argocd.project.destinations[0]",
+				"spec.argocd.project.syncWindows[0].duration",
+				"spec.argocd.workflows[0].syncPolicy.automated",
+			},
+		},
+		{
+			name: "Valid Tekton pipeline",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.Tekton.Pipeline = TektonPipeline{
+					RegistryHost:      "registry.example.com",
+					ImagePathTemplate: "$(params.team)/$(params.workflowId)",
+					ExtraParams:       []TektonParam{{Name: "team"}},
+				}
+			},
+		},
+		{
+			name: "Invalid Tekton pipeline",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.Tekton.Pipeline = TektonPipeline{
+					ImagePathTemplate: "$(params.team)/$(params.workflowId)",
+					ExtraParams:       []TektonParam{{Name: "gitUrl"}},
+				}
+			},
+			expectedFields: []string{
+				"spec.tekton.pipeline.extraParams[0].name",
+				"spec.tekton.pipeline.imagePathTemplate",
+			},
+		},
+		{
+			name: "DevMode in production namespace",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.RHDHConfig.DevMode = true
+			},
+			objects:        []client.Object{productionNamespace},
+			expectedFields: []string{"spec.rhdh.devMode"},
+		},
+		{
+			name: "DevMode in development namespace",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.RHDHConfig.DevMode = true
+			},
+			objects: []client.Object{developmentNamespace},
+		},
+		{
+			name: "DevMode in namespace not created yet",
+			mutate: func(orchestrator *Orchestrator) {
+				orchestrator.Spec.RHDHConfig.DevMode = true
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objects...).Build()
+			validator := &OrchestratorCustomValidator{Client: fakeClient}
+
+			orchestrator := newValidOrchestrator()
+			tc.mutate(orchestrator)
+
+			_, err := validator.ValidateCreate(ctx, orchestrator)
+			if len(tc.expectedFields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, apierrors.IsInvalid(err))
+			statusErr, ok := err.(*apierrors.StatusError)
+			assert.True(t, ok)
+			var fields []string
+			for _, cause := range statusErr.ErrStatus.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			assert.Equal(t, tc.expectedFields, fields)
+		})
+	}
+}
+
+func TestValidateUpdateOfDeletedOrchestrator(t *testing.T) {
+	validator := &OrchestratorCustomValidator{}
+
+	orchestrator := newValidOrchestrator()
+	orchestrator.Spec.ArgoCd = ArgoCD{Enabled: true}
+	now := metav1.Now()
+	orchestrator.DeletionTimestamp = &now
+	orchestrator.Finalizers = []string{"rhdh.redhat.com/orchestrator-cleanup"}
+
+	_, err := validator.ValidateUpdate(context.TODO(), orchestrator, orchestrator)
+	assert.NoError(t, err)
+}
+
+func newValidOrchestrator() *Orchestrator {
+	return &Orchestrator{
+		ObjectMeta: metav1.ObjectMeta{Name: "orchestrator-sample"},
+		Spec: OrchestratorSpec{
+			RHDHConfig: RHDHConfig{Name: "backstage", Namespace: rhdhNamespace},
+			PlatformConfig: PlatformConfig{
+				Namespace: "sonataflow-infra",
+				Resources: Resource{
+					Requests: MemoryCpu{Cpu: "250m", Memory: "64Mi"},
+					Limits:   MemoryCpu{Cpu: "500m", Memory: "1Gi"},
+				},
+			},
+		},
+	}
+}
+
+func TestDefault(t *testing.T) {
+	orchestrator := &Orchestrator{
+		Spec: OrchestratorSpec{
+			RHDHConfig: RHDHConfig{
+				RHDHPlugins: RHDHPlugins{NotificationsConfig: NotificationConfig{Enabled: true}},
+			},
+			PlatformConfig: PlatformConfig{
+				Resources: Resource{Limits: MemoryCpu{Memory: "2Gi"}},
+			},
+		},
+	}
+
+	err := (&OrchestratorCustomDefaulter{}).Default(context.TODO(), orchestrator)
+	assert.NoError(t, err)
+	assert.Equal(t, MemoryCpu{Memory: DefaultRequestsMemory, Cpu: DefaultRequestsCpu}, orchestrator.Spec.PlatformConfig.Resources.Requests)
+	assert.Equal(t, MemoryCpu{Memory: "2Gi", Cpu: DefaultLimitsCpu}, orchestrator.Spec.PlatformConfig.Resources.Limits)
+	assert.Equal(t, DefaultNotificationsEmailPort, orchestrator.Spec.RHDHConfig.RHDHPlugins.NotificationsConfig.Port)
+	assert.Empty(t, orchestrator.Spec.Tekton.Namespace)
+}
+
+func TestDefaultTektonNamespace(t *testing.T) {
+	orchestrator := &Orchestrator{
+		Spec: OrchestratorSpec{
+			ArgoCd: ArgoCD{Namespace: "orchestrator-gitops"},
+			Tekton: Tekton{Enabled: true},
+		},
+	}
+	orchestrator.Default()
+	assert.Equal(t, "orchestrator-gitops", orchestrator.Spec.Tekton.Namespace)
+
+	orchestrator.Spec.Tekton.Namespace = "orchestrator-tekton"
+	orchestrator.Default()
+	assert.Equal(t, "orchestrator-tekton", orchestrator.Spec.Tekton.Namespace)
+}
+
+func TestDefaultRequestsWithinLimits(t *testing.T) {
+	testCases := []struct {
+		name             string
+		resources        Resource
+		expectedRequests MemoryCpu
+		expectedErrors   int
+	}{
+		{
+			name:             "Limits above the default requests",
+			resources:        Resource{Limits: MemoryCpu{Cpu: "2", Memory: "4Gi"}},
+			expectedRequests: MemoryCpu{Cpu: DefaultRequestsCpu, Memory: DefaultRequestsMemory},
+		},
+		{
+			name:             "CPU limit below the default request",
+			resources:        Resource{Limits: MemoryCpu{Cpu: "200m"}},
+			expectedRequests: MemoryCpu{Cpu: "200m", Memory: DefaultRequestsMemory},
+		},
+		{
+			name:             "Memory limit below the default request",
+			resources:        Resource{Limits: MemoryCpu{Memory: "32Mi"}},
+			expectedRequests: MemoryCpu{Cpu: DefaultRequestsCpu, Memory: "32Mi"},
+		},
+		{
+			name:             "Request set below a lower limit",
+			resources:        Resource{Requests: MemoryCpu{Cpu: "100m"}, Limits: MemoryCpu{Cpu: "200m"}},
+			expectedRequests: MemoryCpu{Cpu: "100m", Memory: DefaultRequestsMemory},
+		},
+		{
+			name:             "Unparsable limit",
+			resources:        Resource{Limits: MemoryCpu{Cpu: "two"}},
+			expectedRequests: MemoryCpu{Cpu: DefaultRequestsCpu, Memory: DefaultRequestsMemory},
+			expectedErrors:   1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			orchestrator := &Orchestrator{Spec: OrchestratorSpec{PlatformConfig: PlatformConfig{Resources: tc.resources}}}
+			orchestrator.Default()
+			assert.Equal(t, tc.expectedRequests, orchestrator.Spec.PlatformConfig.Resources.Requests)
+			assert.Len(t, validateResources(orchestrator.Spec.PlatformConfig.Resources, field.NewPath("resources")), tc.expectedErrors)
+		})
+	}
+}
@@ -21,13 +21,24 @@ limitations under the License.
 package v1alpha3
 
 import (
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCD) DeepCopyInto(out *ArgoCD) {
 	*out = *in
+	in.Project.DeepCopyInto(&out.Project)
+	if in.Workflows != nil {
+		in, out := &in.Workflows, &out.Workflows
+		*out = make([]GitOpsWorkflow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCD.
@@ -40,9 +51,129 @@ func (in *ArgoCD) DeepCopy() *ArgoCD {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDDestination) DeepCopyInto(out *ArgoCDDestination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDestination.
+func (in *ArgoCDDestination) DeepCopy() *ArgoCDDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDProject) DeepCopyInto(out *ArgoCDProject) {
+	*out = *in
+	if in.SourceRepos != nil {
+		in, out := &in.SourceRepos, &out.SourceRepos
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]ArgoCDDestination, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterResourceWhitelist != nil {
+		in, out := &in.ClusterResourceWhitelist, &out.ClusterResourceWhitelist
+		*out = make([]metav1.GroupKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterResourceBlacklist != nil {
+		in, out := &in.ClusterResourceBlacklist, &out.ClusterResourceBlacklist
+		*out = make([]metav1.GroupKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]ArgoCDProjectRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SyncWindows != nil {
+		in, out := &in.SyncWindows, &out.SyncWindows
+		*out = make([]ArgoCDSyncWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDProject.
+func (in *ArgoCDProject) DeepCopy() *ArgoCDProject {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDProject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDProjectRole) DeepCopyInto(out *ArgoCDProjectRole) {
+	*out = *in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDProjectRole.
+func (in *ArgoCDProjectRole) DeepCopy() *ArgoCDProjectRole {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDProjectRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDSyncWindow) DeepCopyInto(out *ArgoCDSyncWindow) {
+	*out = *in
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDSyncWindow.
+func (in *ArgoCDSyncWindow) DeepCopy() *ArgoCDSyncWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDSyncWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Broker) DeepCopyInto(out *Broker) {
 	*out = *in
+	out.Kafka = in.Kafka
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Broker.
@@ -55,6 +186,21 @@ func (in *Broker) DeepCopy() *Broker {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionPolicies) DeepCopyInto(out *DeletionPolicies) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionPolicies.
+func (in *DeletionPolicies) DeepCopy() *DeletionPolicies {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionPolicies)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Eventing) DeepCopyInto(out *Eventing) {
 	*out = *in
@@ -71,6 +217,97 @@ func (in *Eventing) DeepCopy() *Eventing {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitAuthor) DeepCopyInto(out *GitAuthor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitAuthor.
+func (in *GitAuthor) DeepCopy() *GitAuthor {
+	if in == nil {
+		return nil
+	}
+	out := new(GitAuthor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsSyncPolicy) DeepCopyInto(out *GitOpsSyncPolicy) {
+	*out = *in
+	if in.SyncOptions != nil {
+		in, out := &in.SyncOptions, &out.SyncOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsSyncPolicy.
+func (in *GitOpsSyncPolicy) DeepCopy() *GitOpsSyncPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsSyncPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsWorkflow) DeepCopyInto(out *GitOpsWorkflow) {
+	*out = *in
+	in.SyncPolicy.DeepCopyInto(&out.SyncPolicy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsWorkflow.
+func (in *GitOpsWorkflow) DeepCopy() *GitOpsWorkflow {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsWorkflow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaBroker) DeepCopyInto(out *KafkaBroker) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaBroker.
+func (in *KafkaBroker) DeepCopy() *KafkaBroker {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaBroker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Knative) DeepCopyInto(out *Knative) {
+	*out = *in
+	if in.Serving != nil {
+		in, out := &in.Serving, &out.Serving
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Eventing != nil {
+		in, out := &in.Eventing, &out.Eventing
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Knative.
+func (in *Knative) DeepCopy() *Knative {
+	if in == nil {
+		return nil
+	}
+	out := new(Knative)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MemoryCpu) DeepCopyInto(out *MemoryCpu) {
 	*out = *in
@@ -101,6 +338,89 @@ func (in *MonitoringConfig) DeepCopy() *MonitoringConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicies) DeepCopyInto(out *NetworkPolicies) {
+	*out = *in
+	if in.AdditionalIngress != nil {
+		in, out := &in.AdditionalIngress, &out.AdditionalIngress
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = make([]NetworkPolicyEgressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicies.
+func (in *NetworkPolicies) DeepCopy() *NetworkPolicies {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyEgressRule) DeepCopyInto(out *NetworkPolicyEgressRule) {
+	*out = *in
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]NetworkPolicyPort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyEgressRule.
+func (in *NetworkPolicyEgressRule) DeepCopy() *NetworkPolicyEgressRule {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyEgressRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyPort) DeepCopyInto(out *NetworkPolicyPort) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.EndPort != nil {
+		in, out := &in.EndPort, &out.EndPort
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyPort.
+func (in *NetworkPolicyPort) DeepCopy() *NetworkPolicyPort {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NotificationConfig) DeepCopyInto(out *NotificationConfig) {
 	*out = *in
@@ -116,12 +436,27 @@ func (in *NotificationConfig) DeepCopy() *NotificationConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorStatus) DeepCopyInto(out *OperatorStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorStatus.
+func (in *OperatorStatus) DeepCopy() *OperatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Orchestrator) DeepCopyInto(out *Orchestrator) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -178,13 +513,14 @@ func (in *OrchestratorList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OrchestratorSpec) DeepCopyInto(out *OrchestratorSpec) {
 	*out = *in
-	out.ServerlessLogicOperator = in.ServerlessLogicOperator
-	out.ServerlessOperator = in.ServerlessOperator
-	out.RHDHConfig = in.RHDHConfig
+	in.ServerlessLogicOperator.DeepCopyInto(&out.ServerlessLogicOperator)
+	in.ServerlessOperator.DeepCopyInto(&out.ServerlessOperator)
+	in.RHDHConfig.DeepCopyInto(&out.RHDHConfig)
 	out.PostgresConfig = in.PostgresConfig
-	out.PlatformConfig = in.PlatformConfig
-	out.Tekton = in.Tekton
-	out.ArgoCd = in.ArgoCd
+	in.PlatformConfig.DeepCopyInto(&out.PlatformConfig)
+	in.Tekton.DeepCopyInto(&out.Tekton)
+	in.ArgoCd.DeepCopyInto(&out.ArgoCd)
+	out.DeletionPolicy = in.DeletionPolicy
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorSpec.
@@ -202,11 +538,36 @@ func (in *OrchestratorStatus) DeepCopyInto(out *OrchestratorStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PendingInstallPlans != nil {
+		in, out := &in.PendingInstallPlans, &out.PendingInstallPlans
+		*out = make([]PendingInstallPlan, len(*in))
+		copy(*out, *in)
+	}
+	if in.Operators != nil {
+		in, out := &in.Operators, &out.Operators
+		*out = make([]OperatorStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkPolicyNamespaces != nil {
+		in, out := &in.NetworkPolicyNamespaces, &out.NetworkPolicyNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Workflows != nil {
+		in, out := &in.Workflows, &out.Workflows
+		*out = make([]WorkflowStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.TektonResources != nil {
+		in, out := &in.TektonResources, &out.TektonResources
+		*out = make([]TektonResourceStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorStatus.
@@ -219,12 +580,28 @@ func (in *OrchestratorStatus) DeepCopy() *OrchestratorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingInstallPlan) DeepCopyInto(out *PendingInstallPlan) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingInstallPlan.
+func (in *PendingInstallPlan) DeepCopy() *PendingInstallPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingInstallPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlatformConfig) DeepCopyInto(out *PlatformConfig) {
 	*out = *in
 	out.Resources = in.Resources
 	out.Eventing = in.Eventing
 	out.Monitoring = in.Monitoring
+	in.NetworkPolicies.DeepCopyInto(&out.NetworkPolicies)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlatformConfig.
@@ -237,6 +614,31 @@ func (in *PlatformConfig) DeepCopy() *PlatformConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginOverride) DeepCopyInto(out *PluginOverride) {
+	*out = *in
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PluginConfig != nil {
+		in, out := &in.PluginConfig, &out.PluginConfig
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginOverride.
+func (in *PluginOverride) DeepCopy() *PluginOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresAuthSecret) DeepCopyInto(out *PostgresAuthSecret) {
 	*out = *in
@@ -271,7 +673,8 @@ func (in *PostgresConfig) DeepCopy() *PostgresConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RHDHConfig) DeepCopyInto(out *RHDHConfig) {
 	*out = *in
-	out.RHDHPlugins = in.RHDHPlugins
+	in.Subscription.DeepCopyInto(&out.Subscription)
+	in.RHDHPlugins.DeepCopyInto(&out.RHDHPlugins)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHDHConfig.
@@ -288,6 +691,13 @@ func (in *RHDHConfig) DeepCopy() *RHDHConfig {
 func (in *RHDHPlugins) DeepCopyInto(out *RHDHPlugins) {
 	*out = *in
 	out.NotificationsConfig = in.NotificationsConfig
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]PluginOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHDHPlugins.
@@ -320,6 +730,7 @@ func (in *Resource) DeepCopy() *Resource {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerlessLogicOperator) DeepCopyInto(out *ServerlessLogicOperator) {
 	*out = *in
+	in.Subscription.DeepCopyInto(&out.Subscription)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerlessLogicOperator.
@@ -335,6 +746,8 @@ func (in *ServerlessLogicOperator) DeepCopy() *ServerlessLogicOperator {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerlessOperator) DeepCopyInto(out *ServerlessOperator) {
 	*out = *in
+	in.Subscription.DeepCopyInto(&out.Subscription)
+	in.Knative.DeepCopyInto(&out.Knative)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerlessOperator.
@@ -347,9 +760,30 @@ func (in *ServerlessOperator) DeepCopy() *ServerlessOperator {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Subscription) DeepCopyInto(out *Subscription) {
+	*out = *in
+	if in.AllowedCSVs != nil {
+		in, out := &in.AllowedCSVs, &out.AllowedCSVs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subscription.
+func (in *Subscription) DeepCopy() *Subscription {
+	if in == nil {
+		return nil
+	}
+	out := new(Subscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Tekton) DeepCopyInto(out *Tekton) {
 	*out = *in
+	in.Pipeline.DeepCopyInto(&out.Pipeline)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tekton.
@@ -361,3 +795,92 @@ func (in *Tekton) DeepCopy() *Tekton {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TektonParam) DeepCopyInto(out *TektonParam) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TektonParam.
+func (in *TektonParam) DeepCopy() *TektonParam {
+	if in == nil {
+		return nil
+	}
+	out := new(TektonParam)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TektonPipeline) DeepCopyInto(out *TektonPipeline) {
+	*out = *in
+	out.GitAuthor = in.GitAuthor
+	out.Images = in.Images
+	if in.ExtraParams != nil {
+		in, out := &in.ExtraParams, &out.ExtraParams
+		*out = make([]TektonParam, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TektonPipeline.
+func (in *TektonPipeline) DeepCopy() *TektonPipeline {
+	if in == nil {
+		return nil
+	}
+	out := new(TektonPipeline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TektonResourceStatus) DeepCopyInto(out *TektonResourceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TektonResourceStatus.
+func (in *TektonResourceStatus) DeepCopy() *TektonResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TektonResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TektonTaskImages) DeepCopyInto(out *TektonTaskImages) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TektonTaskImages.
+func (in *TektonTaskImages) DeepCopy() *TektonTaskImages {
+	if in == nil {
+		return nil
+	}
+	out := new(TektonTaskImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowStatus) DeepCopyInto(out *WorkflowStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowStatus.
+func (in *WorkflowStatus) DeepCopy() *WorkflowStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -43,7 +43,8 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
-	orchestratorv1alpha2 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	orchestratorv1alpha2 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha2"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
 	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller"
 	//+kubebuilder:scaffold:imports
 )
@@ -59,6 +60,7 @@ func init() {
 	utilruntime.Must(operatorsv1.AddToScheme(scheme))
 	utilruntime.Must(operatorsv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(orchestratorv1alpha2.AddToScheme(scheme))
+	utilruntime.Must(orchestratorv1alpha3.AddToScheme(scheme))
 	utilruntime.Must(v1beta1.AddToScheme(scheme))
 	utilruntime.Must(rhdhv1alpha3.AddToScheme(scheme))
 	utilruntime.Must(configv1.AddToScheme(scheme))
@@ -170,6 +172,12 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Orchestrator")
 		os.Exit(1)
 	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&orchestratorv1alpha3.Orchestrator{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Orchestrator")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,248 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/knative"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Definition to manage Orchestrator condition status.
+	TypeReady                = "Ready"
+	TypeServerlessLogicReady = "ServerlessLogicReady"
+	TypeKnativeReady         = "KnativeReady"
+	TypeRHDHReady            = "RHDHReady"
+	TypeNetworkPoliciesReady = "NetworkPoliciesReady"
+	TypeArgoCDReady          = "ArgoCDReady"
+	TypeTektonReady          = "TektonReady"
+	TypePostgresReachable    = "PostgresReachable"
+	// TypeRHDHConfigSynced reports whether the RHDH configuration rendered by the operator could be applied.
+	// It does not take part in the Ready condition, since the objects modified by users are left untouched.
+	TypeRHDHConfigSynced = "RHDHConfigSynced"
+	// TypeDeletionPlanned lists the resources removed according to the deletion policies once the Orchestrator is
+	// deleted. It does not take part in the Ready condition either.
+	TypeDeletionPlanned = "DeletionPlanned"
+	// TypeOperatorsUninstalled reports the last uninstallation of the operators whose installOperator flag was
+	// switched off. It does not take part in the Ready condition either.
+	TypeOperatorsUninstalled = "OperatorsUninstalled"
+
+	// Definition of the reasons used by the Orchestrator conditions.
+	ReasonReconciling           = "Reconciling"
+	ReasonReconcileSucceeded    = "ReconcileSucceeded"
+	ReasonReconcileFailed       = "ReconcileFailed"
+	ReasonWaitingForDependency  = "WaitingForDependency"
+	ReasonDisabled              = "Disabled"
+	ReasonAllSubsystemsReady    = "AllSubsystemsReady"
+	ReasonSubsystemsNotReady    = "SubsystemsNotReady"
+	ReasonSubsystemsReconciling = "SubsystemsReconciling"
+	ReasonConfigSynced          = "ConfigSynced"
+	ReasonConfigConflict        = "ConfigConflict"
+	ReasonResourcesToRemove     = "ResourcesToRemove"
+	ReasonNothingToRemove       = "NothingToRemove"
+	ReasonOperatorsUninstalled  = "OperatorsUninstalled"
+)
+
+// SubsystemConditionTypes lists the per-subsystem conditions used to compute the top-level Ready condition.
+var SubsystemConditionTypes = []string{
+	TypeServerlessLogicReady,
+	TypeKnativeReady,
+	TypeRHDHReady,
+	TypeNetworkPoliciesReady,
+	TypeArgoCDReady,
+	TypeTektonReady,
+	TypePostgresReachable,
+}
+
+// legacyConditionTypes are the conditions written by previous versions of the operator.
+// They are removed from the status as they no longer reflect the state of the Orchestrator.
+var legacyConditionTypes = []string{"Available", "Completed", "Degrading", "GitOpsReady"}
+
+// setSubsystemCondition records the outcome of reconciling a subsystem as a condition on the Orchestrator status.
+// A disabled subsystem that reconciled without errors is reported as ready, since it does not block the Orchestrator.
+func setSubsystemCondition(
+	orchestrator *orchestratorv1alpha3.Orchestrator,
+	conditionType, subsystemName string, enabled bool, err error) {
+
+	condition := metav1.Condition{
+		Type:               conditionType,
+		ObservedGeneration: orchestrator.Generation,
+	}
+
+	switch {
+	case err == nil && !enabled:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonDisabled
+		condition.Message = fmt.Sprintf("%s is disabled", subsystemName)
+	case err == nil:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonReconcileSucceeded
+		condition.Message = fmt.Sprintf("%s resources are reconciled", subsystemName)
+	case isWaitingForDependency(err):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonWaitingForDependency
+		condition.Message = fmt.Sprintf("%s is waiting for a dependency: %s", subsystemName, err.Error())
+	default:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonReconcileFailed
+		condition.Message = err.Error()
+	}
+	meta.SetStatusCondition(&orchestrator.Status.Conditions, condition)
+}
+
+// isWaitingForDependency returns whether err reports a dependency of a subsystem that is not available yet,
+// such as a CRD or a resource not created yet, an operator whose CSV is still being installed or a Broker
+// that is not ready yet.
+func isWaitingForDependency(err error) bool {
+	if kube.IsCSVNotSucceeded(err) {
+		return !kube.IsCSVFailed(err)
+	}
+	return apierrors.IsNotFound(err) || meta.IsNoMatchError(err) || knative.IsBrokerNotReady(err)
+}
+
+// setRHDHConfigSyncedCondition reports the RHDH objects that were modified outside of the operator
+// and therefore not updated with the configuration rendered from the Orchestrator.
+func setRHDHConfigSyncedCondition(orchestrator *orchestratorv1alpha3.Orchestrator, conflicts []string) {
+	condition := metav1.Condition{
+		Type:               TypeRHDHConfigSynced,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonConfigSynced,
+		Message:            "RHDH configuration is up to date",
+		ObservedGeneration: orchestrator.Generation,
+	}
+	if len(conflicts) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonConfigConflict
+		condition.Message = fmt.Sprintf(
+			"The following objects were modified outside of the operator and are not updated: %s. "+
+				"Remove their %s annotation to let the operator overwrite them",
+			strings.Join(conflicts, ", "), kube.ConfigHashAnnotation)
+	}
+	meta.SetStatusCondition(&orchestrator.Status.Conditions, condition)
+}
+
+// setDeletionPlannedCondition reports the resources deleted and orphaned once the Orchestrator is deleted, or the
+// error that prevented listing them.
+func setDeletionPlannedCondition(orchestrator *orchestratorv1alpha3.Orchestrator, deleted, orphaned []string, err error) {
+	condition := metav1.Condition{
+		Type:               TypeDeletionPlanned,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonNothingToRemove,
+		Message:            "Deleting the Orchestrator retains all its resources",
+		ObservedGeneration: orchestrator.Generation,
+	}
+	switch {
+	case err != nil:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = ReasonReconcileFailed
+		condition.Message = err.Error()
+	case len(deleted) > 0 || len(orphaned) > 0:
+		var removals []string
+		if len(deleted) > 0 {
+			removals = append(removals, fmt.Sprintf("deletes %s", strings.Join(deleted, ", ")))
+		}
+		if len(orphaned) > 0 {
+			removals = append(removals, fmt.Sprintf("orphans %s", strings.Join(orphaned, ", ")))
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonResourcesToRemove
+		condition.Message = fmt.Sprintf("Deleting the Orchestrator %s", strings.Join(removals, " and "))
+	}
+	meta.SetStatusCondition(&orchestrator.Status.Conditions, condition)
+}
+
+// setOperatorsUninstalledCondition reports the objects removed when uninstalling the disabled operators, or the error
+// that prevented uninstalling them. The condition is left unchanged when there was nothing to uninstall, and removed
+// once every operator is installed again.
+func setOperatorsUninstalledCondition(orchestrator *orchestratorv1alpha3.Orchestrator, removed []string, err error) {
+	spec := orchestrator.Spec
+	if spec.ServerlessLogicOperator.InstallOperator && spec.ServerlessOperator.InstallOperator && spec.RHDHConfig.InstallOperator {
+		meta.RemoveStatusCondition(&orchestrator.Status.Conditions, TypeOperatorsUninstalled)
+		return
+	}
+	condition := metav1.Condition{
+		Type:               TypeOperatorsUninstalled,
+		ObservedGeneration: orchestrator.Generation,
+	}
+	switch {
+	case err != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonReconcileFailed
+		condition.Message = err.Error()
+	case len(removed) > 0:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonOperatorsUninstalled
+		condition.Message = fmt.Sprintf("The disabled operators were uninstalled: deleted %s", strings.Join(removed, ", "))
+	default:
+		// a failed uninstallation is no longer reported once nothing is left to uninstall
+		if meta.IsStatusConditionFalse(orchestrator.Status.Conditions, TypeOperatorsUninstalled) {
+			meta.RemoveStatusCondition(&orchestrator.Status.Conditions, TypeOperatorsUninstalled)
+		}
+		return
+	}
+	meta.SetStatusCondition(&orchestrator.Status.Conditions, condition)
+}
+
+// setReadyCondition computes the top-level Ready condition and the phase from the subsystem conditions.
+func setReadyCondition(orchestrator *orchestratorv1alpha3.Orchestrator) {
+	for _, conditionType := range legacyConditionTypes {
+		meta.RemoveStatusCondition(&orchestrator.Status.Conditions, conditionType)
+	}
+
+	var failed, pending []string
+	for _, conditionType := range SubsystemConditionTypes {
+		condition := meta.FindStatusCondition(orchestrator.Status.Conditions, conditionType)
+		switch {
+		case condition == nil || condition.Status == metav1.ConditionUnknown:
+			pending = append(pending, conditionType)
+		case condition.Status == metav1.ConditionFalse && condition.Reason == ReasonReconcileFailed:
+			failed = append(failed, conditionType)
+		case condition.Status == metav1.ConditionFalse:
+			pending = append(pending, conditionType)
+		}
+	}
+
+	ready := metav1.Condition{
+		Type:               TypeReady,
+		ObservedGeneration: orchestrator.Generation,
+	}
+	switch {
+	case len(failed) > 0:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = ReasonSubsystemsNotReady
+		ready.Message = fmt.Sprintf("The following subsystems failed to reconcile: %s", strings.Join(failed, ", "))
+		orchestrator.Status.Phase = orchestratorv1alpha3.FailedPhase
+	case len(pending) > 0:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = ReasonSubsystemsReconciling
+		ready.Message = fmt.Sprintf("The following subsystems are not ready yet: %s", strings.Join(pending, ", "))
+		orchestrator.Status.Phase = orchestratorv1alpha3.RunningPhase
+	default:
+		ready.Status = metav1.ConditionTrue
+		ready.Reason = ReasonAllSubsystemsReady
+		ready.Message = "Reconciliation has completed"
+		orchestrator.Status.Phase = orchestratorv1alpha3.CompletedPhase
+	}
+	meta.SetStatusCondition(&orchestrator.Status.Conditions, ready)
+}
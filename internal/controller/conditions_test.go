@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/knative"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestSetSubsystemCondition(t *testing.T) {
+	testCases := []struct {
+		name           string
+		enabled        bool
+		err            error
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "Subsystem reconciled",
+			enabled:        true,
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: ReasonReconcileSucceeded,
+		},
+		{
+			name:           "Subsystem disabled",
+			enabled:        false,
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: ReasonDisabled,
+		},
+		{
+			name:           "Subsystem waiting for a dependency",
+			enabled:        true,
+			err:            apierrors.NewNotFound(schema.GroupResource{}, "crd"),
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: ReasonWaitingForDependency,
+		},
+		{
+			name:           "Subsystem failed",
+			enabled:        true,
+			err:            fmt.Errorf("failed"),
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: ReasonReconcileFailed,
+		},
+		{
+			name:    "Subsystem waiting for the operator CSV",
+			enabled: true,
+			err: &kube.CSVNotSucceededError{OperatorStatus: orchestratorv1alpha3.OperatorStatus{
+				Subscription: "serverless-operator", InstalledCSV: "serverless-operator.v1.36.0", Phase: "Installing",
+			}},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: ReasonWaitingForDependency,
+		},
+		{
+			name:    "Subsystem operator CSV failed",
+			enabled: true,
+			err: &kube.CSVNotSucceededError{OperatorStatus: orchestratorv1alpha3.OperatorStatus{
+				Subscription: "serverless-operator", InstalledCSV: "serverless-operator.v1.36.0", Phase: "Failed", Reason: "ComponentFailed",
+			}},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: ReasonReconcileFailed,
+		},
+		{
+			name:           "Subsystem waiting for the broker",
+			enabled:        true,
+			err:            &knative.BrokerNotReadyError{Name: "kafka-broker", Namespace: "sonataflow-infra"},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: ReasonWaitingForDependency,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			orchestrator := &orchestratorv1alpha3.Orchestrator{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+			setSubsystemCondition(orchestrator, TypeKnativeReady, "K-Native Serverless", tc.enabled, tc.err)
+
+			condition := meta.FindStatusCondition(orchestrator.Status.Conditions, TypeKnativeReady)
+			assert.NotNil(t, condition)
+			assert.Equal(t, tc.expectedStatus, condition.Status)
+			assert.Equal(t, tc.expectedReason, condition.Reason)
+			assert.Equal(t, int64(3), condition.ObservedGeneration)
+		})
+	}
+}
+
+func TestSetReadyCondition(t *testing.T) {
+	testCases := []struct {
+		name           string
+		errors         map[string]error
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+		expectedPhase  orchestratorv1alpha3.OrchestratorPhase
+	}{
+		{
+			name:           "All subsystems ready",
+			errors:         map[string]error{},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: ReasonAllSubsystemsReady,
+			expectedPhase:  orchestratorv1alpha3.CompletedPhase,
+		},
+		{
+			name:           "One subsystem waiting for a dependency",
+			errors:         map[string]error{TypeRHDHReady: apierrors.NewNotFound(schema.GroupResource{}, "crd")},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: ReasonSubsystemsReconciling,
+			expectedPhase:  orchestratorv1alpha3.RunningPhase,
+		},
+		{
+			name: "One subsystem failed",
+			errors: map[string]error{
+				TypeRHDHReady:    apierrors.NewNotFound(schema.GroupResource{}, "crd"),
+				TypeKnativeReady: fmt.Errorf("failed"),
+			},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: ReasonSubsystemsNotReady,
+			expectedPhase:  orchestratorv1alpha3.FailedPhase,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			orchestrator := &orchestratorv1alpha3.Orchestrator{}
+			orchestrator.Status.Conditions = []metav1.Condition{
+				{Type: "Degrading", Status: metav1.ConditionFalse, Reason: "ReconcilingRHDHResourcesFailed"},
+			}
+			for _, conditionType := range SubsystemConditionTypes {
+				setSubsystemCondition(orchestrator, conditionType, conditionType, true, tc.errors[conditionType])
+			}
+			setReadyCondition(orchestrator)
+
+			ready := meta.FindStatusCondition(orchestrator.Status.Conditions, TypeReady)
+			assert.NotNil(t, ready)
+			assert.Equal(t, tc.expectedStatus, ready.Status)
+			assert.Equal(t, tc.expectedReason, ready.Reason)
+			assert.Equal(t, tc.expectedPhase, orchestrator.Status.Phase)
+			assert.Nil(t, meta.FindStatusCondition(orchestrator.Status.Conditions, "Degrading"))
+		})
+	}
+}
+
+func TestSetRHDHConfigSyncedCondition(t *testing.T) {
+	orchestrator := &orchestratorv1alpha3.Orchestrator{}
+
+	setRHDHConfigSyncedCondition(orchestrator, []string{"ConfigMap rhdh/dynamic-plugins-rhdh"})
+	condition := meta.FindStatusCondition(orchestrator.Status.Conditions, TypeRHDHConfigSynced)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonConfigConflict, condition.Reason)
+	assert.Contains(t, condition.Message, "ConfigMap rhdh/dynamic-plugins-rhdh")
+
+	setRHDHConfigSyncedCondition(orchestrator, nil)
+	condition = meta.FindStatusCondition(orchestrator.Status.Conditions, TypeRHDHConfigSynced)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonConfigSynced, condition.Reason)
+}
+
+func TestSetDeletionPlannedCondition(t *testing.T) {
+	orchestrator := &orchestratorv1alpha3.Orchestrator{}
+
+	setDeletionPlannedCondition(orchestrator, nil, nil, nil)
+	condition := meta.FindStatusCondition(orchestrator.Status.Conditions, TypeDeletionPlanned)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonNothingToRemove, condition.Reason)
+
+	setDeletionPlannedCondition(orchestrator, []string{"Namespace knative-eventing", "Backstage rhdh/backstage"},
+		[]string{"Task orchestrator-gitops/flattener"}, nil)
+	condition = meta.FindStatusCondition(orchestrator.Status.Conditions, TypeDeletionPlanned)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonResourcesToRemove, condition.Reason)
+	assert.Equal(t, "Deleting the Orchestrator deletes Namespace knative-eventing, Backstage rhdh/backstage "+
+		"and orphans Task orchestrator-gitops/flattener", condition.Message)
+
+	setDeletionPlannedCondition(orchestrator, nil, nil, fmt.Errorf("failed to list the RHDH resources to clean up"))
+	condition = meta.FindStatusCondition(orchestrator.Status.Conditions, TypeDeletionPlanned)
+	assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+	assert.Equal(t, "failed to list the RHDH resources to clean up", condition.Message)
+}
+
+func TestSetOperatorsUninstalledCondition(t *testing.T) {
+	orchestrator := &orchestratorv1alpha3.Orchestrator{}
+	orchestrator.Spec.ServerlessLogicOperator.InstallOperator = true
+	orchestrator.Spec.ServerlessOperator.InstallOperator = false
+	orchestrator.Spec.RHDHConfig.InstallOperator = true
+
+	setOperatorsUninstalledCondition(orchestrator, nil, nil)
+	assert.Nil(t, meta.FindStatusCondition(orchestrator.Status.Conditions, TypeOperatorsUninstalled))
+
+	setOperatorsUninstalledCondition(orchestrator, nil, fmt.Errorf("failed to uninstall the K-Native Serverless operator"))
+	condition := meta.FindStatusCondition(orchestrator.Status.Conditions, TypeOperatorsUninstalled)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonReconcileFailed, condition.Reason)
+
+	setOperatorsUninstalledCondition(orchestrator,
+		[]string{"Namespace knative-eventing", "Subscription openshift-serverless/serverless-operator"}, nil)
+	condition = meta.FindStatusCondition(orchestrator.Status.Conditions, TypeOperatorsUninstalled)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonOperatorsUninstalled, condition.Reason)
+	assert.Equal(t, "The disabled operators were uninstalled: deleted Namespace knative-eventing, "+
+		"Subscription openshift-serverless/serverless-operator", condition.Message)
+
+	// the last uninstallation is kept while the operator is disabled
+	setOperatorsUninstalledCondition(orchestrator, nil, nil)
+	assert.True(t, meta.IsStatusConditionTrue(orchestrator.Status.Conditions, TypeOperatorsUninstalled))
+
+	orchestrator.Spec.ServerlessOperator.InstallOperator = true
+	setOperatorsUninstalledCondition(orchestrator, nil, nil)
+	assert.Nil(t, meta.FindStatusCondition(orchestrator.Status.Conditions, TypeOperatorsUninstalled))
+}
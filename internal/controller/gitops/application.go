@@ -0,0 +1,255 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	argocdv1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/metrics"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	applicationCRDName    = "applications.argoproj.io"
+	applicationKind       = "Application"
+	WorkflowLabelKey      = "orchestrator.rhdh.redhat.com/workflow"
+	inClusterServer       = "https://kubernetes.default.svc"
+	defaultTargetRevision = "HEAD"
+)
+
+// HandleWorkflowApplications creates or updates the ArgoCD Application of every gitops workflow in the ArgoCD
+// namespace, and deletes the Applications of the workflows removed from the instance Orchestrator. The workflows are
+// deployed to the workflow namespace unless they set their own namespace. It returns the sync and health status of
+// the Applications.
+func HandleWorkflowApplications(client client.Client, ctx context.Context, gitOpsNamespace, workflowNamespace, instance string,
+	workflows []orchestratorv1alpha3.GitOpsWorkflow) ([]orchestratorv1alpha3.WorkflowStatus, error) {
+	appLogger := log.FromContext(ctx)
+
+	owner := kube.Owner{Instance: instance}
+	if len(workflows) == 0 {
+		return nil, handleWorkflowApplicationsCleanUp(client, ctx, gitOpsNamespace, owner, nil)
+	}
+
+	if err := kube.CheckCRDExists(ctx, client, applicationCRDName); err != nil {
+		appLogger.Error(err, "ArgoCD Application CRD does not exist. Install ArgoCD Operator")
+		return nil, err
+	}
+
+	var errorList []error
+	var workflowStatuses []orchestratorv1alpha3.WorkflowStatus
+	desiredApplications := make(map[string]bool, len(workflows))
+	for _, workflow := range workflows {
+		desiredApplications[workflow.ID] = true
+		application, owned, err := handleWorkflowApplication(client, ctx, gitOpsNamespace, workflowNamespace, instance, workflow)
+		if err != nil {
+			errorList = append(errorList, fmt.Errorf("failed to reconcile the Application of workflow %s: %w", workflow.ID, err))
+			continue
+		}
+		if !owned {
+			workflowStatuses = append(workflowStatuses, getWorkflowConflictStatus(workflow.ID, application))
+			continue
+		}
+		workflowStatuses = append(workflowStatuses, getWorkflowStatus(workflow.ID, application))
+	}
+
+	if err := handleWorkflowApplicationsCleanUp(client, ctx, gitOpsNamespace, owner, desiredApplications); err != nil {
+		errorList = append(errorList, err)
+	}
+	return workflowStatuses, errors.NewAggregate(errorList)
+}
+
+// handleWorkflowApplication creates the Application of the workflow, or updates its spec when it drifted from
+// the Orchestrator, and returns it. An existing Application that was not created by the operator for the instance
+// Orchestrator is left untouched, and returned as not owned.
+func handleWorkflowApplication(client client.Client, ctx context.Context, gitOpsNamespace, workflowNamespace, instance string,
+	workflow orchestratorv1alpha3.GitOpsWorkflow) (*argocdv1alpha1.Application, bool, error) {
+	appLogger := log.FromContext(ctx)
+
+	desiredApplication := getWorkflowApplication(gitOpsNamespace, workflowNamespace, instance, workflow)
+	existingApplication := &argocdv1alpha1.Application{}
+	err := client.Get(ctx, types.NamespacedName{Namespace: gitOpsNamespace, Name: desiredApplication.Name}, existingApplication)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			appLogger.Error(err, "Error occurred when retrieving ArgoCD Application", "CR", desiredApplication.Name)
+			return nil, false, err
+		}
+		appLogger.Info("Creating ArgoCD Application...", "CR", desiredApplication.Name)
+		if err := client.Create(ctx, desiredApplication); err != nil {
+			appLogger.Error(err, "Error occurred when creating ArgoCD Application", "CR", desiredApplication.Name)
+			return nil, false, err
+		}
+		return desiredApplication, true, nil
+	}
+
+	// the Applications created by previous versions of the operator have no instance label and are adopted
+	existingInstance, labelled := existingApplication.Labels[kube.InstanceLabelKey]
+	if !kube.CheckLabelExist(existingApplication.Labels) || (labelled && existingInstance != instance) {
+		appLogger.Info("ArgoCD Application was not created by the Orchestrator and is not updated", "CR", desiredApplication.Name)
+		return existingApplication, false, nil
+	}
+
+	// Compare the current and desired state
+	adopted := adoptLabels(existingApplication, desiredApplication)
+	if adopted || !reflect.DeepEqual(desiredApplication.Spec, existingApplication.Spec) {
+		existingApplication.Spec = desiredApplication.Spec
+		if err := client.Update(ctx, existingApplication); err != nil {
+			appLogger.Error(err, "Error occurred when updating ArgoCD Application", "CR", desiredApplication.Name)
+			return nil, false, err
+		}
+		metrics.RecordDriftCorrection("Application")
+	}
+	return existingApplication, true, nil
+}
+
+func getWorkflowApplication(gitOpsNamespace, workflowNamespace, instance string, workflow orchestratorv1alpha3.GitOpsWorkflow) *argocdv1alpha1.Application {
+	labels := kube.GetInstanceLabels(instance)
+	labels[WorkflowLabelKey] = workflow.ID
+
+	targetRevision := workflow.TargetRevision
+	if targetRevision == "" {
+		targetRevision = defaultTargetRevision
+	}
+	namespace := workflow.Namespace
+	if namespace == "" {
+		namespace = workflowNamespace
+	}
+
+	syncPolicy := &argocdv1alpha1.SyncPolicy{
+		SyncOptions: workflow.SyncPolicy.SyncOptions,
+	}
+	if workflow.SyncPolicy.Automated {
+		syncPolicy.Automated = &argocdv1alpha1.SyncPolicyAutomated{
+			Prune:    workflow.SyncPolicy.Prune,
+			SelfHeal: workflow.SyncPolicy.SelfHeal,
+		}
+	}
+
+	return &argocdv1alpha1.Application{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: argoCDAPIVersion,
+			Kind:       applicationKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workflow.ID,
+			Namespace: gitOpsNamespace,
+			Labels:    labels,
+		},
+		Spec: argocdv1alpha1.ApplicationSpec{
+			Project: argoCDCRName,
+			Source: &argocdv1alpha1.ApplicationSource{
+				RepoURL:        workflow.RepoURL,
+				Path:           workflow.Path,
+				TargetRevision: targetRevision,
+			},
+			Destination: argocdv1alpha1.ApplicationDestination{
+				Server:    inClusterServer,
+				Namespace: namespace,
+			},
+			SyncPolicy: syncPolicy,
+		},
+	}
+}
+
+// getWorkflowStatus returns the sync and health status of the Application of a workflow, with the messages of
+// its conditions, which report the errors preventing the sync.
+func getWorkflowStatus(workflowID string, application *argocdv1alpha1.Application) orchestratorv1alpha3.WorkflowStatus {
+	var messages []string
+	for _, condition := range application.Status.Conditions {
+		messages = append(messages, fmt.Sprintf("%s: %s", condition.Type, condition.Message))
+	}
+	return orchestratorv1alpha3.WorkflowStatus{
+		ID:           workflowID,
+		Application:  application.Name,
+		SyncStatus:   string(application.Status.Sync.Status),
+		HealthStatus: string(application.Status.Health.Status),
+		Revision:     application.Status.Sync.Revision,
+		Message:      strings.Join(messages, "; "),
+	}
+}
+
+// getWorkflowConflictStatus returns the status of a workflow whose Application exists but was not created by the
+// operator for the Orchestrator.
+func getWorkflowConflictStatus(workflowID string, application *argocdv1alpha1.Application) orchestratorv1alpha3.WorkflowStatus {
+	return orchestratorv1alpha3.WorkflowStatus{
+		ID:          workflowID,
+		Application: application.Name,
+		Message: fmt.Sprintf("Conflict: the Application %s/%s was not created by the Orchestrator and is not managed",
+			application.Namespace, application.Name),
+	}
+}
+
+// handleWorkflowApplicationsCleanUp deletes the Applications of workflows owned by owner, except the desired ones.
+// The resources of the workflows are left in the cluster, unless the resources finalizer of ArgoCD was added to
+// their Application.
+func handleWorkflowApplicationsCleanUp(k8Client client.Client, ctx context.Context, gitOpsNamespace string, owner kube.Owner,
+	desiredApplications map[string]bool) error {
+	applications, err := listWorkflowApplicationsCleanUpObjects(k8Client, ctx, gitOpsNamespace, owner, desiredApplications)
+	if err != nil {
+		return err
+	}
+	return kube.ApplyDeletionPolicy(ctx, k8Client, orchestratorv1alpha3.DeletionPolicyDelete, applications)
+}
+
+// listWorkflowApplicationsCleanUpObjects returns the Applications of workflows owned by owner, except the desired
+// ones.
+func listWorkflowApplicationsCleanUpObjects(k8Client client.Client, ctx context.Context, gitOpsNamespace string, owner kube.Owner,
+	desiredApplications map[string]bool) ([]client.Object, error) {
+	appLogger := log.FromContext(ctx)
+
+	// the Applications are only listed in the ArgoCD namespace, never cluster wide
+	if gitOpsNamespace == "" {
+		return nil, nil
+	}
+	if err := kube.CheckCRDExists(ctx, k8Client, applicationCRDName); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	applicationList := &argocdv1alpha1.ApplicationList{}
+	listOptions := []client.ListOption{
+		client.InNamespace(gitOpsNamespace),
+		client.MatchingLabels{kube.CreatedByLabelKey: kube.CreatedByLabelValue},
+		client.HasLabels{WorkflowLabelKey},
+	}
+	if err := k8Client.List(ctx, applicationList, listOptions...); err != nil {
+		appLogger.Error(err, "Error occurred when listing ArgoCD Applications", "NS", gitOpsNamespace)
+		return nil, err
+	}
+
+	var applications []client.Object
+	for i := range applicationList.Items {
+		application := &applicationList.Items[i]
+		if desiredApplications[application.Name] || !owner.Owns(application) {
+			continue
+		}
+		applications = append(applications, application)
+	}
+	return applications, nil
+}
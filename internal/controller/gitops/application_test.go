@@ -0,0 +1,170 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitops
+
+import (
+	"context"
+	"testing"
+
+	argocdv1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testWorkflowNamespace = "sonataflow-infra"
+
+func TestGetWorkflowApplication(t *testing.T) {
+	workflow := orchestratorv1alpha3.GitOpsWorkflow{
+		ID:      "greeting",
+		RepoURL: "https://github.com/my-org/greeting-gitops",
+		Path:    "kustomize/overlays/prod",
+		SyncPolicy: orchestratorv1alpha3.GitOpsSyncPolicy{
+			Automated:   true,
+			SelfHeal:    true,
+			SyncOptions: []string{"CreateNamespace=true"},
+		},
+	}
+
+	application := getWorkflowApplication(testGitOpsNamespace, testWorkflowNamespace, testInstance, workflow)
+	assert.Equal(t, "greeting", application.Name)
+	assert.Equal(t, testGitOpsNamespace, application.Namespace)
+	assert.Equal(t, "greeting", application.Labels[WorkflowLabelKey])
+	assert.Equal(t, kube.CreatedByLabelValue, application.Labels[kube.CreatedByLabelKey])
+	assert.Equal(t, testInstance, application.Labels[kube.InstanceLabelKey])
+	assert.Equal(t, argocdv1alpha1.ApplicationSpec{
+		Project: argoCDCRName,
+		Source: &argocdv1alpha1.ApplicationSource{
+			RepoURL:        "https://github.com/my-org/greeting-gitops",
+			Path:           "kustomize/overlays/prod",
+			TargetRevision: defaultTargetRevision,
+		},
+		Destination: argocdv1alpha1.ApplicationDestination{Server: inClusterServer, Namespace: testWorkflowNamespace},
+		SyncPolicy: &argocdv1alpha1.SyncPolicy{
+			Automated:   &argocdv1alpha1.SyncPolicyAutomated{SelfHeal: true},
+			SyncOptions: argocdv1alpha1.SyncOptions{"CreateNamespace=true"},
+		},
+	}, application.Spec)
+
+	workflow.Namespace = "workflows"
+	workflow.TargetRevision = "v1.0.0"
+	workflow.SyncPolicy = orchestratorv1alpha3.GitOpsSyncPolicy{}
+	application = getWorkflowApplication(testGitOpsNamespace, testWorkflowNamespace, testInstance, workflow)
+	assert.Equal(t, "workflows", application.Spec.Destination.Namespace)
+	assert.Equal(t, "v1.0.0", application.Spec.Source.TargetRevision)
+	assert.Nil(t, application.Spec.SyncPolicy.Automated)
+}
+
+func TestHandleWorkflowApplications(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(argocdv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+
+	crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: applicationCRDName}}
+	greeting := orchestratorv1alpha3.GitOpsWorkflow{ID: "greeting", RepoURL: "https://github.com/my-org/greeting-gitops", Path: "kustomize"}
+
+	// the Application of greeting drifted from the Orchestrator and reports its status
+	driftedApplication := getWorkflowApplication(testGitOpsNamespace, testWorkflowNamespace, testInstance, greeting)
+	driftedApplication.Spec.Source.Path = "manual"
+	driftedApplication.Status = argocdv1alpha1.ApplicationStatus{
+		Sync:   argocdv1alpha1.SyncStatus{Status: argocdv1alpha1.SyncStatusCodeOutOfSync, Revision: "abc123"},
+		Health: argocdv1alpha1.HealthStatus{Status: "Degraded"},
+		Conditions: []argocdv1alpha1.ApplicationCondition{
+			{Type: argocdv1alpha1.ApplicationConditionComparisonError, Message: "path does not exist"},
+		},
+	}
+	// the Application of a workflow removed from the Orchestrator
+	removedApplication := getWorkflowApplication(testGitOpsNamespace, testWorkflowNamespace, testInstance,
+		orchestratorv1alpha3.GitOpsWorkflow{ID: "removed", RepoURL: "https://github.com/my-org/removed-gitops", Path: "kustomize"})
+	// an Application not created by the operator
+	unmanagedApplication := &argocdv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: testGitOpsNamespace},
+	}
+	// the Application of a workflow of another Orchestrator
+	otherApplication := getWorkflowApplication(testGitOpsNamespace, testWorkflowNamespace, "other",
+		orchestratorv1alpha3.GitOpsWorkflow{ID: "other", RepoURL: "https://github.com/my-org/other-gitops", Path: "kustomize"})
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(crd, driftedApplication, removedApplication, unmanagedApplication, otherApplication).
+		WithStatusSubresource(&argocdv1alpha1.Application{}).Build()
+
+	echo := orchestratorv1alpha3.GitOpsWorkflow{ID: "echo", RepoURL: "https://github.com/my-org/echo-gitops", Path: "kustomize"}
+	// the workflows whose Application exists but was not created for the Orchestrator are reported as conflicts
+	unmanaged := orchestratorv1alpha3.GitOpsWorkflow{ID: "unmanaged", RepoURL: "https://github.com/my-org/unmanaged-gitops", Path: "kustomize"}
+	other := orchestratorv1alpha3.GitOpsWorkflow{ID: "other", RepoURL: "https://github.com/my-org/echo-gitops", Path: "kustomize"}
+	statuses, err := HandleWorkflowApplications(fakeClient, ctx, testGitOpsNamespace, testWorkflowNamespace, testInstance,
+		[]orchestratorv1alpha3.GitOpsWorkflow{greeting, echo, unmanaged, other})
+	assert.NoError(t, err)
+	assert.Equal(t, []orchestratorv1alpha3.WorkflowStatus{
+		{
+			ID:           "greeting",
+			Application:  "greeting",
+			SyncStatus:   "OutOfSync",
+			HealthStatus: "Degraded",
+			Revision:     "abc123",
+			Message:      "ComparisonError: path does not exist",
+		},
+		{ID: "echo", Application: "echo"},
+		{
+			ID:          "unmanaged",
+			Application: "unmanaged",
+			Message:     "Conflict: the Application " + testGitOpsNamespace + "/unmanaged was not created by the Orchestrator and is not managed",
+		},
+		{
+			ID:          "other",
+			Application: "other",
+			Message:     "Conflict: the Application " + testGitOpsNamespace + "/other was not created by the Orchestrator and is not managed",
+		},
+	}, statuses)
+
+	application := &argocdv1alpha1.Application{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "greeting", Namespace: testGitOpsNamespace}, application))
+	assert.Equal(t, "kustomize", application.Spec.Source.Path)
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "echo", Namespace: testGitOpsNamespace}, application))
+	assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(unmanagedApplication), application))
+	assert.Nil(t, application.Labels)
+	assert.Nil(t, application.Spec.Source)
+	assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(otherApplication), application))
+	assert.Equal(t, "https://github.com/my-org/other-gitops", application.Spec.Source.RepoURL)
+	err = fakeClient.Get(ctx, client.ObjectKeyFromObject(removedApplication), application)
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestHandleWorkflowApplicationsWithoutCRD(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(argocdv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	// nothing to clean up when ArgoCD is not installed
+	statuses, err := HandleWorkflowApplications(fakeClient, ctx, testGitOpsNamespace, testWorkflowNamespace, testInstance, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, statuses)
+
+	workflows := []orchestratorv1alpha3.GitOpsWorkflow{{ID: "greeting", RepoURL: "https://github.com/my-org/greeting-gitops", Path: "kustomize"}}
+	_, err = HandleWorkflowApplications(fakeClient, ctx, testGitOpsNamespace, testWorkflowNamespace, testInstance, workflows)
+	assert.True(t, apierrors.IsNotFound(err))
+}
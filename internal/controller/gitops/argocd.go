@@ -31,7 +31,9 @@ package gitops
 import (
 	"context"
 	argocdv1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
 	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/metrics"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -47,7 +49,7 @@ const (
 	argoCDKind       = "AppProject"
 )
 
-func handleArgoCDProject(gitOpsNamespace string, client client.Client, ctx context.Context) error {
+func handleArgoCDProject(gitOpsNamespace, instance string, project orchestratorv1alpha3.ArgoCDProject, client client.Client, ctx context.Context) error {
 	argoLogger := log.FromContext(ctx)
 	argoLogger.Info("Handling ArgoCD Project...")
 
@@ -64,18 +66,9 @@ func handleArgoCDProject(gitOpsNamespace string, client client.Client, ctx conte
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      argoCDCRName,
 			Namespace: gitOpsNamespace,
-			Labels:    kube.GetOrchestratorLabel(),
-		},
-		Spec: argocdv1alpha1.AppProjectSpec{
-			Destinations: []argocdv1alpha1.ApplicationDestination{
-				{
-					Name:      "*",
-					Namespace: "*",
-					Server:    "*",
-				},
-			},
-			SourceRepos: []string{"*"},
+			Labels:    kube.GetInstanceLabels(instance),
 		},
+		Spec: getAppProjectSpec(project),
 	}
 	existingAppProject := &argocdv1alpha1.AppProject{}
 
@@ -98,62 +91,78 @@ func handleArgoCDProject(gitOpsNamespace string, client client.Client, ctx conte
 		return err
 	} else {
 		// Compare the current and desired state
-		if !reflect.DeepEqual(desiredAppProject.Spec, existingAppProject.Spec) {
+		adopted := adoptLabels(existingAppProject, desiredAppProject)
+		if adopted || !reflect.DeepEqual(desiredAppProject.Spec, existingAppProject.Spec) {
 			existingAppProject.Spec = desiredAppProject.Spec
 			if err := client.Update(ctx, existingAppProject); err != nil {
 				argoLogger.Error(err, "Error occurred when updating GitOps", "ArgoCD", argoCDCRName)
 				return err
 			}
+			metrics.RecordDriftCorrection("AppProject")
 		}
 	}
 	return nil
 }
 
-func handleArgoCDProjectCleanUp(gitOpsNamespace string, client client.Client, ctx context.Context) error {
-	argoLogger := log.FromContext(ctx)
-
-	argoLogger.Info("Handling ArgoCD ProjectCleanUp...")
-
-	namespaceExist, _ := kube.CheckNamespaceExist(ctx, client, gitOpsNamespace)
-	if namespaceExist {
-		argoCDProjectCRList, err := listArgoCDProjectCR(ctx, client, gitOpsNamespace)
-
-		if err != nil || len(argoCDProjectCRList) == 0 {
-			argoLogger.Info("Failed to list or have no ArgoCD Project CRs created by Orchestrator Operator and cannot perform clean up process")
-			return nil
-		}
-
-		if len(argoCDProjectCRList) == 1 {
-			// remove ArgoCD Project CR
-			err := client.Delete(ctx, &argoCDProjectCRList[0])
-			if err != nil {
-				argoLogger.Error(err, "Error occurred when deleting ArgoCD Project App", "ArgoCD", argoCDCRName)
-				return err
-
-			}
-			argoLogger.Info("Successfully deleted ArgoCD Project CR created by orchestrator", "ArgoCD Project", argoCDCRName)
-			return nil
+// getAppProjectSpec returns the spec of the AppProject configured in the Orchestrator. The project allows all
+// source repositories and destinations unless they are configured.
+func getAppProjectSpec(project orchestratorv1alpha3.ArgoCDProject) argocdv1alpha1.AppProjectSpec {
+	spec := argocdv1alpha1.AppProjectSpec{
+		Destinations: []argocdv1alpha1.ApplicationDestination{
+			{
+				Name:      "*",
+				Namespace: "*",
+				Server:    "*",
+			},
+		},
+		SourceRepos:              []string{"*"},
+		ClusterResourceWhitelist: project.ClusterResourceWhitelist,
+		ClusterResourceBlacklist: project.ClusterResourceBlacklist,
+	}
+	if len(project.SourceRepos) > 0 {
+		spec.SourceRepos = project.SourceRepos
+	}
+	if len(project.Destinations) > 0 {
+		spec.Destinations = nil
+		for _, destination := range project.Destinations {
+			spec.Destinations = append(spec.Destinations, argocdv1alpha1.ApplicationDestination{
+				Server:    destination.Server,
+				Name:      destination.Name,
+				Namespace: destination.Namespace,
+			})
 		}
 	}
-	return nil
-}
-
-func listArgoCDProjectCR(ctx context.Context, k8client client.Client, namespace string) ([]argocdv1alpha1.AppProject, error) {
-	argoLogger := log.FromContext(ctx)
-
-	crList := &argocdv1alpha1.AppProjectList{}
-
-	listOptions := []client.ListOption{
-		client.InNamespace(namespace),
-		client.MatchingLabels{kube.CreatedByLabelKey: kube.CreatedByLabelValue},
+	for _, role := range project.Roles {
+		spec.Roles = append(spec.Roles, argocdv1alpha1.ProjectRole{
+			Name:        role.Name,
+			Description: role.Description,
+			Policies:    role.Policies,
+			Groups:      role.Groups,
+		})
 	}
-
-	// List the CRs
-	if err := k8client.List(ctx, crList, listOptions...); err != nil {
-		argoLogger.Error(err, "Error occurred when listing ArgoCD Project CRs", "CR", argoCDCRName)
-		return nil, err
+	for _, syncWindow := range project.SyncWindows {
+		spec.SyncWindows = append(spec.SyncWindows, &argocdv1alpha1.SyncWindow{
+			Kind:         syncWindow.Kind,
+			Schedule:     syncWindow.Schedule,
+			Duration:     syncWindow.Duration,
+			Applications: syncWindow.Applications,
+			Namespaces:   syncWindow.Namespaces,
+			Clusters:     syncWindow.Clusters,
+			ManualSync:   syncWindow.ManualSync,
+			TimeZone:     syncWindow.TimeZone,
+		})
 	}
+	return spec
+}
 
-	argoLogger.Info("Successfully listed ArgoCD Project CRs", "Total", len(crList.Items))
-	return crList.Items, nil
+// listArgoCDProjectCleanUpObjects returns the AppProjects owned by owner.
+func listArgoCDProjectCleanUpObjects(ctx context.Context, k8Client client.Client, gitOpsNamespace string, owner kube.Owner) ([]client.Object, error) {
+	return kube.ListOwnedCustomResources(ctx, k8Client, &argocdv1alpha1.AppProjectList{},
+		func(list *argocdv1alpha1.AppProjectList) []*argocdv1alpha1.AppProject {
+			objs := make([]*argocdv1alpha1.AppProject, len(list.Items))
+			for i := range list.Items {
+				objs[i] = &list.Items[i]
+			}
+			return objs
+		}, gitOpsNamespace, owner)
 }
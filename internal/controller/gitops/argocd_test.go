@@ -0,0 +1,167 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitops
+
+import (
+	"context"
+	"testing"
+
+	argocdv1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/stretchr/testify/assert"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testGitOpsNamespace = "orchestrator-gitops"
+	testInstance        = "orchestrator"
+)
+
+func TestGetAppProjectSpec(t *testing.T) {
+	testCases := []struct {
+		name     string
+		project  orchestratorv1alpha3.ArgoCDProject
+		expected argocdv1alpha1.AppProjectSpec
+	}{
+		{
+			name:    "Defaults to all repositories and destinations",
+			project: orchestratorv1alpha3.ArgoCDProject{},
+			expected: argocdv1alpha1.AppProjectSpec{
+				Destinations: []argocdv1alpha1.ApplicationDestination{{Name: "*", Namespace: "*", Server: "*"}},
+				SourceRepos:  []string{"*"},
+			},
+		},
+		{
+			name: "Passes the project configuration through",
+			project: orchestratorv1alpha3.ArgoCDProject{
+				SourceRepos: []string{"https://github.com/my-org/*"},
+				Destinations: []orchestratorv1alpha3.ArgoCDDestination{
+					{Server: "https://kubernetes.default.svc", Namespace: "workflows"},
+				},
+				ClusterResourceWhitelist: []metav1.GroupKind{{Group: "", Kind: "Namespace"}},
+				ClusterResourceBlacklist: []metav1.GroupKind{{Group: "rbac.authorization.k8s.io", Kind: "*"}},
+				Roles: []orchestratorv1alpha3.ArgoCDProjectRole{
+					{
+						Name:     "read-only",
+						Policies: []string{"p, proj:orchestrator-gitops:read-only, applications, get, orchestrator-gitops/*, allow"},
+						Groups:   []string{"workflow-viewers"},
+					},
+				},
+				SyncWindows: []orchestratorv1alpha3.ArgoCDSyncWindow{
+					{Kind: "deny", Schedule: "0 22 * * *", Duration: "8h", Applications: []string{"*"}, ManualSync: true},
+				},
+			},
+			expected: argocdv1alpha1.AppProjectSpec{
+				Destinations: []argocdv1alpha1.ApplicationDestination{
+					{Server: "https://kubernetes.default.svc", Namespace: "workflows"},
+				},
+				SourceRepos:              []string{"https://github.com/my-org/*"},
+				ClusterResourceWhitelist: []metav1.GroupKind{{Group: "", Kind: "Namespace"}},
+				ClusterResourceBlacklist: []metav1.GroupKind{{Group: "rbac.authorization.k8s.io", Kind: "*"}},
+				Roles: []argocdv1alpha1.ProjectRole{
+					{
+						Name:     "read-only",
+						Policies: []string{"p, proj:orchestrator-gitops:read-only, applications, get, orchestrator-gitops/*, allow"},
+						Groups:   []string{"workflow-viewers"},
+					},
+				},
+				SyncWindows: argocdv1alpha1.SyncWindows{
+					{Kind: "deny", Schedule: "0 22 * * *", Duration: "8h", Applications: []string{"*"}, ManualSync: true},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getAppProjectSpec(tc.project))
+		})
+	}
+}
+
+func TestHandleArgoCDProjectUpdatesSpec(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(argocdv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+
+	crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: argoCDCRDName}}
+	existingAppProject := &argocdv1alpha1.AppProject{
+		ObjectMeta: metav1.ObjectMeta{Name: argoCDCRName, Namespace: testGitOpsNamespace, Labels: kube.GetOrchestratorLabel()},
+		Spec:       getAppProjectSpec(orchestratorv1alpha3.ArgoCDProject{}),
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(crd, existingAppProject).Build()
+
+	project := orchestratorv1alpha3.ArgoCDProject{SourceRepos: []string{"https://github.com/my-org/*"}}
+	assert.NoError(t, handleArgoCDProject(testGitOpsNamespace, testInstance, project, fakeClient, ctx))
+
+	appProject := &argocdv1alpha1.AppProject{}
+	err := fakeClient.Get(ctx, types.NamespacedName{Name: argoCDCRName, Namespace: testGitOpsNamespace}, appProject)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://github.com/my-org/*"}, appProject.Spec.SourceRepos)
+	assert.Equal(t, "*", appProject.Spec.Destinations[0].Server)
+	// the AppProject created by a previous version of the operator is adopted
+	assert.Equal(t, kube.GetInstanceLabels(testInstance), appProject.Labels)
+}
+
+func TestHandleGitOpsCleanUp(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(argocdv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(tektonv1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+	owner := kube.Owner{Instance: testInstance, Last: true}
+
+	appProject := &argocdv1alpha1.AppProject{
+		ObjectMeta: metav1.ObjectMeta{Name: argoCDCRName, Namespace: testGitOpsNamespace, Labels: kube.GetInstanceLabels(testInstance)},
+	}
+	otherAppProject := &argocdv1alpha1.AppProject{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-project", Namespace: testGitOpsNamespace, Labels: kube.GetInstanceLabels("other")},
+	}
+	// the Tekton resources have their own namespace
+	const testTektonNamespace = "orchestrator-tekton"
+	pipeline := getPipelineObject(testTektonNamespace, testInstance, withPipelineDefaults(orchestratorv1alpha3.TektonPipeline{}))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(appProject, otherAppProject, pipeline).Build()
+
+	objects, err := ListGitOpsCleanUpObjects(fakeClient, ctx, testGitOpsNamespace, testTektonNamespace, owner)
+	assert.NoError(t, err)
+	assert.Len(t, objects, 2)
+	objects, err = ListGitOpsCleanUpObjects(fakeClient, ctx, testGitOpsNamespace, testGitOpsNamespace, owner)
+	assert.NoError(t, err)
+	assert.Len(t, objects, 1)
+
+	// the Tekton clean up keeps the ArgoCD resources
+	assert.NoError(t, HandleTektonCleanUp(fakeClient, ctx, testTektonNamespace, owner))
+	err = fakeClient.Get(ctx, client.ObjectKeyFromObject(pipeline), &tektonv1.Pipeline{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(appProject), &argocdv1alpha1.AppProject{}))
+
+	// the AppProject of another Orchestrator is kept
+	assert.NoError(t, HandleArgoCDCleanUp(fakeClient, ctx, testGitOpsNamespace, owner))
+	err = fakeClient.Get(ctx, client.ObjectKeyFromObject(appProject), &argocdv1alpha1.AppProject{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(otherAppProject), &argocdv1alpha1.AppProject{}))
+}
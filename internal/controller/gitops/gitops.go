@@ -30,60 +30,154 @@ package gitops
 
 import (
 	"context"
+
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-// HandleGitOps performs the retrieval, creation and reconciling of Tekton and GitOps policy.
-// It returns an error if any occurs during retrieval, creation or reconciliation.
-func HandleGitOps(client client.Client, ctx context.Context, gitOpsNamespace string) error {
+// HandleArgoCD creates or updates the orchestrator-gitops AppProject once the ArgoCD CRDs are installed.
+func HandleArgoCD(client client.Client, ctx context.Context, gitOpsNamespace, instance string, argoCDProject orchestratorv1alpha3.ArgoCDProject) error {
 	logger := log.FromContext(ctx)
-	logger.Info("Handling GitOps resource")
+	logger.Info("Handling ArgoCD resource")
 
-	if err := handleArgoCDProject(gitOpsNamespace, client, ctx); err != nil {
+	if err := checkCRDs(ctx, client, argoCDCRDName, applicationCRDName); err != nil {
+		logger.Error(err, "ArgoCD CRDs do not exist. Install ArgoCD Operator")
 		return err
 	}
-
-	if err := handleTektonPipelineTasks(client, ctx, gitOpsNamespace); err != nil {
-		return err
-	}
-
-	return nil
+	return handleArgoCDProject(gitOpsNamespace, instance, argoCDProject, client, ctx)
 }
 
-func handleTektonPipelineTasks(client client.Client, ctx context.Context, gitOpsNamespace string) error {
+// HandleTekton creates or updates the Tekton Tasks and Pipeline in tektonNamespace once the Tekton CRDs are
+// installed. It returns the versions of the Tekton resources.
+func HandleTekton(client client.Client, ctx context.Context, tektonNamespace, instance string,
+	tektonPipeline orchestratorv1alpha3.TektonPipeline) ([]orchestratorv1alpha3.TektonResourceStatus, error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Handling Tekton resource")
 
+	if err := checkCRDs(ctx, client, tektonCRDName, pipelineCRDName); err != nil {
+		logger.Error(err, "Tekton CRDs do not exist. Install RedHat Openshift Pipelines Operator")
+		return nil, err
+	}
+
 	// handle tekton task
-	if err := HandleTektonTasks(client, ctx, gitOpsNamespace); err != nil {
-		return err
+	tektonStatuses, err := HandleTektonTasks(client, ctx, tektonNamespace, instance, tektonPipeline)
+	if err != nil {
+		return tektonStatuses, err
 	}
 
 	// handle tekton pipeline
-	if err := HandleTektonPipeline(client, ctx, gitOpsNamespace); err != nil {
-		return err
+	pipelineStatus, err := HandleTektonPipeline(client, ctx, tektonNamespace, instance, tektonPipeline)
+	if err != nil {
+		return tektonStatuses, err
+	}
+	return append(tektonStatuses, pipelineStatus), nil
+}
+
+// checkCRDs returns the error of the first CRD of crdNames that cannot be retrieved, i.e. NotFound when it is not
+// installed, so that no resource of a subsystem is created before all its CRDs are installed.
+func checkCRDs(ctx context.Context, client client.Client, crdNames ...string) error {
+	for _, crdName := range crdNames {
+		if err := kube.CheckCRDExists(ctx, client, crdName); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func HandleGitOpsCleanUp(client client.Client, ctx context.Context, gitOpsNamespace string) error {
+// HandleArgoCDCleanUp deletes the ArgoCD resources owned by owner.
+func HandleArgoCDCleanUp(client client.Client, ctx context.Context, gitOpsNamespace string, owner kube.Owner) error {
 	logger := log.FromContext(ctx)
-	logger.Info("Handling GitOps resource clean up")
+	logger.Info("Handling ArgoCD resource clean up")
 
-	// handle argocd clean up
-	if err := handleArgoCDProjectCleanUp(gitOpsNamespace, client, ctx); err != nil {
+	objects, err := ListArgoCDCleanUpObjects(client, ctx, gitOpsNamespace, owner)
+	if err != nil {
 		return err
 	}
+	return kube.ApplyDeletionPolicy(ctx, client, orchestratorv1alpha3.DeletionPolicyDelete, objects)
+}
 
-	// handle tekton pipeline clean up
-	if err := handleTektonPipelineCleanUp(client, ctx, gitOpsNamespace); err != nil {
+// HandleTektonCleanUp deletes the Tekton resources owned by owner in tektonNamespace.
+func HandleTektonCleanUp(client client.Client, ctx context.Context, tektonNamespace string, owner kube.Owner) error {
+	logger := log.FromContext(ctx)
+	logger.Info("Handling Tekton resource clean up")
+
+	objects, err := ListTektonCleanUpObjects(client, ctx, tektonNamespace, owner)
+	if err != nil {
 		return err
 	}
+	return kube.ApplyDeletionPolicy(ctx, client, orchestratorv1alpha3.DeletionPolicyDelete, objects)
+}
 
-	// handle tekton clean up
-	if err := handleTektonTaskCleanUp(client, ctx, gitOpsNamespace); err != nil {
-		return err
+// ListGitOpsCleanUpObjects returns the ArgoCD AppProject and Applications of gitOpsNamespace and the Tekton Pipeline
+// and Tasks of tektonNamespace owned by owner, which are removed in this order according to the gitops deletion
+// policy.
+func ListGitOpsCleanUpObjects(k8Client client.Client, ctx context.Context, gitOpsNamespace, tektonNamespace string,
+	owner kube.Owner) ([]client.Object, error) {
+	argoCDObjects, err := ListArgoCDCleanUpObjects(k8Client, ctx, gitOpsNamespace, owner)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	tektonObjects, err := ListTektonCleanUpObjects(k8Client, ctx, tektonNamespace, owner)
+	if err != nil {
+		return nil, err
+	}
+	return append(argoCDObjects, tektonObjects...), nil
+}
+
+// ListArgoCDCleanUpObjects returns the ArgoCD AppProject and Applications owned by owner.
+func ListArgoCDCleanUpObjects(k8Client client.Client, ctx context.Context, gitOpsNamespace string, owner kube.Owner) ([]client.Object, error) {
+	// the resources are only listed in the ArgoCD namespace, never cluster wide
+	if gitOpsNamespace == "" {
+		return nil, nil
+	}
+
+	appProjects, err := listArgoCDProjectCleanUpObjects(ctx, k8Client, gitOpsNamespace, owner)
+	if err != nil {
+		return nil, err
+	}
+	applications, err := listWorkflowApplicationsCleanUpObjects(k8Client, ctx, gitOpsNamespace, owner, nil)
+	if err != nil {
+		return nil, err
+	}
+	return append(appProjects, applications...), nil
+}
+
+// ListTektonCleanUpObjects returns the Tekton Pipeline and Tasks owned by owner in tektonNamespace.
+func ListTektonCleanUpObjects(k8Client client.Client, ctx context.Context, tektonNamespace string, owner kube.Owner) ([]client.Object, error) {
+	// the resources are only listed in the Tekton namespace, never cluster wide
+	if tektonNamespace == "" {
+		return nil, nil
+	}
+
+	pipelines, err := listTektonPipelineCleanUpObjects(ctx, k8Client, tektonNamespace, owner)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := listTektonTaskCleanUpObjects(ctx, k8Client, tektonNamespace, owner)
+	if err != nil {
+		return nil, err
+	}
+	return append(pipelines, tasks...), nil
+}
+
+// adoptLabels adds the labels of desired missing on obj, i.e. the instance label on the objects created by previous
+// versions of the operator, and returns whether some were added. The labels already set are kept, so an object
+// owned by another Orchestrator is never taken over.
+func adoptLabels(obj, desired metav1.Object) bool {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	adopted := false
+	for key, value := range desired.GetLabels() {
+		if _, ok := labels[key]; !ok {
+			labels[key] = value
+			adopted = true
+		}
+	}
+	obj.SetLabels(labels)
+	return adopted
 }
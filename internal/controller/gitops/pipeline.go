@@ -2,7 +2,12 @@ package gitops
 
 import (
 	"context"
+	"strings"
+
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
 	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/metrics"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/version"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,27 +28,108 @@ const (
 	buildAndPushImagePipelineTask   = "build-and-push-image"
 	pushWorkflowGitOpsPipelineTask  = "push-workflow-gitops"
 	pipelineCRDName                 = "pipelines.tekton.dev"
+	pipelineKind                    = "Pipeline"
+	quayOrgNameParam                = "quayOrgName"
+	quayRepoNameParam               = "quayRepoName"
+	defaultRegistryHost             = "quay.io"
+	defaultImagePathTemplate        = "$(params." + quayOrgNameParam + ")/$(params." + quayRepoNameParam + ")"
+	defaultGitUserName              = "The Orchestrator Tekton Pipeline"
+	defaultGitUserEmail             = "rhdhorchestrator@redhat.com"
+	defaultGitUserHome              = "/home/git"
+	defaultGitImage                 = "cgr.dev/chainguard/git:root-2.39@sha256:7759f87050dd8bacabe61354d75ccd7f864d6b6f8ec42697db7159eccd491139"
+	defaultBaseImage                = "registry.access.redhat.com/ubi9-minimal"
+	defaultKanikoImage              = "gcr.io/kaniko-project/executor:v1.23.2"
 )
 
-func HandleTektonPipeline(client client.Client, ctx context.Context, gitOpsNamespace string) error {
+// withPipelineDefaults returns the pipeline configuration of the Orchestrator with the defaults of the fields that
+// are not set.
+func withPipelineDefaults(config orchestratorv1alpha3.TektonPipeline) orchestratorv1alpha3.TektonPipeline {
+	setDefault := func(value *string, defaultValue string) {
+		if *value == "" {
+			*value = defaultValue
+		}
+	}
+	setDefault(&config.RegistryHost, defaultRegistryHost)
+	setDefault(&config.ImagePathTemplate, defaultImagePathTemplate)
+	setDefault(&config.GitAuthor.Name, defaultGitUserName)
+	setDefault(&config.GitAuthor.Email, defaultGitUserEmail)
+	setDefault(&config.GitUserHome, defaultGitUserHome)
+	setDefault(&config.Images.Git, defaultGitImage)
+	setDefault(&config.Images.Base, defaultBaseImage)
+	setDefault(&config.Images.Kaniko, defaultKanikoImage)
+	if config.Builder == "" {
+		config.Builder = orchestratorv1alpha3.BuilderBuildah
+	}
+	return config
+}
+
+func HandleTektonPipeline(client client.Client, ctx context.Context, gitOpsNamespace, instance string,
+	pipelineConfig orchestratorv1alpha3.TektonPipeline) (orchestratorv1alpha3.TektonResourceStatus, error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Handling tekton pipeline resources")
 
 	if err := kube.CheckCRDExists(ctx, client, pipelineCRDName); err != nil {
 		logger.Error(err, "Tekton Pipeline CRD does not exist. Install RedHat Openshift Pipelines Operator")
-		return err
+		return orchestratorv1alpha3.TektonResourceStatus{}, err
+	}
+
+	desiredPipeline := getPipelineObject(gitOpsNamespace, instance, withPipelineDefaults(pipelineConfig))
+	specHash, err := kube.ComputeConfigHash(desiredPipeline.Spec)
+	if err != nil {
+		logger.Error(err, "Error occurred when computing hash of Tekton Pipeline spec", "Pipeline", pipelineName)
+		return orchestratorv1alpha3.TektonResourceStatus{}, err
+	}
+	setTektonResourceVersion(desiredPipeline, specHash)
+
+	existingPipeline := &tektonv1.Pipeline{}
+	if err := client.Get(ctx, types.NamespacedName{
+		Namespace: gitOpsNamespace,
+		Name:      pipelineName,
+	}, existingPipeline); err != nil {
+		if errors.IsNotFound(err) {
+			if err := client.Create(ctx, desiredPipeline); err != nil {
+				logger.Error(err, "Error occurred when creating Tekton Pipeline", "Pipeline", pipelineName)
+				return orchestratorv1alpha3.TektonResourceStatus{}, err
+			}
+			logger.Info("Successfully created Tekton Pipeline", "Pipeline", pipelineName)
+			return getTektonResourceStatus(pipelineKind, desiredPipeline), nil
+		}
+		return orchestratorv1alpha3.TektonResourceStatus{}, err
+	}
+
+	adopted := adoptLabels(existingPipeline, desiredPipeline)
+	if adopted || existingPipeline.Annotations[kube.ConfigHashAnnotation] != specHash {
+		existingPipeline.Spec = desiredPipeline.Spec
+		setTektonResourceVersion(existingPipeline, specHash)
+		if err := client.Update(ctx, existingPipeline); err != nil {
+			logger.Error(err, "Error occurred when updating Tekton Pipeline", "Pipeline", pipelineName)
+			return orchestratorv1alpha3.TektonResourceStatus{}, err
+		}
+		logger.Info("Successfully updated Tekton Pipeline", "Pipeline", pipelineName, "Version", version.Version)
+		metrics.RecordDriftCorrection(pipelineKind)
+	}
+	return getTektonResourceStatus(pipelineKind, existingPipeline), nil
+}
+
+// getPipelineObject returns the workflow-deployment Pipeline rendered from the pipeline configuration.
+func getPipelineObject(gitOpsNamespace, instance string, pipelineConfig orchestratorv1alpha3.TektonPipeline) *tektonv1.Pipeline {
+	// the Quay params are optional when the image path does not reference them
+	var quayParamDefault *tektonv1.ParamValue
+	if !strings.Contains(pipelineConfig.ImagePathTemplate, "$(params."+quayOrgNameParam+")") &&
+		!strings.Contains(pipelineConfig.ImagePathTemplate, "$(params."+quayRepoNameParam+")") {
+		quayParamDefault = &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: ""}
 	}
 
 	// pipeline definition
 	desiredPipeline := &tektonv1.Pipeline{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: tektonAPIVersion,
-			Kind:       "Pipeline",
+			Kind:       pipelineKind,
 		},
 		ObjectMeta: ctrl.ObjectMeta{
 			Name:      pipelineName,
 			Namespace: gitOpsNamespace,
-			Labels:    kube.GetOrchestratorLabel(),
+			Labels:    kube.GetInstanceLabels(instance),
 		},
 		Spec: tektonv1.PipelineSpec{
 			Description: "This pipeline clones a git repo, builds a Docker image with Kaniko, and pushes it to a registry",
@@ -73,14 +159,16 @@ func HandleTektonPipeline(client client.Client, ctx context.Context, gitOpsNames
 					},
 				},
 				{
-					Name:        "quayOrgName",
+					Name:        quayOrgNameParam,
 					Description: "The Quay Organization Name of the published workflow",
 					Type:        tektonv1.ParamTypeString,
+					Default:     quayParamDefault,
 				},
 				{
-					Name:        "quayRepoName",
+					Name:        quayRepoNameParam,
 					Description: "The Quay Repository Name of the published workflow",
 					Type:        tektonv1.ParamTypeString,
+					Default:     quayParamDefault.DeepCopy(),
 				},
 			},
 			Workspaces: []tektonv1.PipelineWorkspaceDeclaration{
@@ -97,12 +185,7 @@ func HandleTektonPipeline(client client.Client, ctx context.Context, gitOpsNames
 						{Name: "source", Workspace: "workflow-source"},
 						{Name: "ssh-directory", Workspace: "ssh-creds"},
 					},
-					Params: []tektonv1.Param{
-						{Name: "GIT_USER_NAME", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "The Orchestrator Tekton Pipeline"}},
-						{Name: "GIT_USER_EMAIL", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "rhdhorchestrator@redhat.com"}},
-						{Name: "USER_HOME", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "/home/git"}},
-						{Name: "GIT_SCRIPT", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: gitCloneScript}},
-					},
+					Params: getGitCLIParams(pipelineConfig, gitCloneScript),
 				},
 				{
 					Name:    fetchWorkflowGitOpsPipelineTask,
@@ -111,12 +194,7 @@ func HandleTektonPipeline(client client.Client, ctx context.Context, gitOpsNames
 						{Name: "source", Workspace: "workflow-gitops"},
 						{Name: "ssh-directory", Workspace: "ssh-creds"},
 					},
-					Params: []tektonv1.Param{
-						{Name: "GIT_USER_NAME", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "The Orchestrator Tekton Pipeline"}},
-						{Name: "GIT_USER_EMAIL", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "rhdhorchestrator@redhat.com"}},
-						{Name: "USER_HOME", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "/home/git"}},
-						{Name: "GIT_SCRIPT", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: gitCloneGitOpsScript}},
-					},
+					Params: getGitCLIParams(pipelineConfig, gitCloneGitOpsScript),
 				},
 				{
 					Name:     flattenWorkflowPipelineTask,
@@ -151,36 +229,7 @@ func HandleTektonPipeline(client client.Client, ctx context.Context, gitOpsNames
 						{Name: "imageTag", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.fetch-workflow.results.commit)"}},
 					},
 				},
-				{
-					Name:     buildAndPushImagePipelineTask,
-					RunAfter: []string{flattenWorkflowPipelineTask},
-					TaskRef: &tektonv1.TaskRef{
-						ResolverRef: tektonv1.ResolverRef{
-							Resolver: "cluster",
-							Params: []tektonv1.Param{
-								{Name: "kind", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "task"}},
-								{Name: "name", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "buildah"}},
-								{Name: "namespace", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "openshift-pipelines"}},
-							},
-						},
-					},
-					Workspaces: []tektonv1.WorkspacePipelineTaskBinding{
-						{Name: "source", Workspace: "workflow-source"},
-						{Name: "dockerconfig", Workspace: "docker-credentials"},
-					},
-					Params: []tektonv1.Param{
-						{Name: "IMAGE", Value: tektonv1.ParamValue{
-							Type:      tektonv1.ParamTypeString,
-							StringVal: "quay.io/$(params.quayOrgName)/$(params.quayRepoName):$(tasks.fetch-workflow.results.commit)",
-						}},
-						{Name: "DOCKERFILE", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "flat/workflow-builder.Dockerfile"}},
-						{Name: "CONTEXT", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "flat/$(params.workflowId)"}},
-						{Name: "BUILD_EXTRA_ARGS", Value: tektonv1.ParamValue{
-							Type:      tektonv1.ParamTypeString,
-							StringVal: "--authfile=/workspace/dockerconfig/.dockerconfigjson --ulimit nofile=4096:4096 --build-arg WF_RESOURCES=.",
-						}},
-					},
-				},
+				getBuildAndPushImagePipelineTask(pipelineConfig),
 				{
 					Name:     pushWorkflowGitOpsPipelineTask,
 					RunAfter: []string{buildGitOpsPipelineTask, buildAndPushImagePipelineTask},
@@ -191,92 +240,83 @@ func HandleTektonPipeline(client client.Client, ctx context.Context, gitOpsNames
 						{Name: "source", Workspace: "workflow-gitops"},
 						{Name: "ssh-directory", Workspace: "ssh-creds"},
 					},
-					Params: []tektonv1.Param{
-						{Name: "GIT_USER_NAME", Value: tektonv1.ParamValue{
-							Type:      tektonv1.ParamTypeString,
-							StringVal: "The Orchestrator Tekton Pipeline",
-						}},
-						{Name: "GIT_USER_EMAIL", Value: tektonv1.ParamValue{
-							Type:      tektonv1.ParamTypeString,
-							StringVal: "rhdhorchestrator@redhat.com",
-						}},
-						{Name: "USER_HOME", Value: tektonv1.ParamValue{
-							Type:      tektonv1.ParamTypeString,
-							StringVal: "/home/git",
-						}},
-						{Name: "GIT_SCRIPT", Value: tektonv1.ParamValue{
-							Type:      tektonv1.ParamTypeString,
-							StringVal: gitScript,
-						}},
-					},
+					Params: getGitCLIParams(pipelineConfig, gitScript),
 				},
 			},
 		},
 	}
-	existingPipeline := &tektonv1.Pipeline{}
-
-	if err := client.Get(ctx, types.NamespacedName{
-		Namespace: gitOpsNamespace,
-		Name:      pipelineName,
-	}, existingPipeline); err != nil {
-		if errors.IsNotFound(err) {
-			if err := client.Create(ctx, desiredPipeline); err != nil {
-				logger.Error(err, "Error occurred when creating Tekton Pipeline", "Pipeline", pipelineName)
-				return err
-			}
-			logger.Info("Successfully created Tekton Pipeline", "Pipeline", pipelineName)
-			return err
+	for _, param := range pipelineConfig.ExtraParams {
+		paramSpec := tektonv1.ParamSpec{
+			Name:        param.Name,
+			Description: param.Description,
+			Type:        tektonv1.ParamTypeString,
 		}
-		return err
+		if param.Default != nil {
+			paramSpec.Default = &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: *param.Default}
+		}
+		desiredPipeline.Spec.Params = append(desiredPipeline.Spec.Params, paramSpec)
 	}
-	return nil
+	return desiredPipeline
 }
 
-func handleTektonPipelineCleanUp(client client.Client, ctx context.Context, gitOpsNamespace string) error {
-	pipelineLogger := log.FromContext(ctx)
-
-	pipelineLogger.Info("Handling Tekton Pipeline CleanUp...")
-
-	namespaceExist, _ := kube.CheckNamespaceExist(ctx, client, gitOpsNamespace)
-	if namespaceExist {
-		tektonPipelineCRList, err := listTektonPipelineCR(ctx, client, gitOpsNamespace)
-
-		if err != nil || len(tektonPipelineCRList) == 0 {
-			pipelineLogger.Info("Failed to list or have no Tekton Pipeline CRs created by Orchestrator Operator and cannot perform clean up process")
-			return nil
-		}
-
-		if len(tektonPipelineCRList) == 1 {
-			// remove Tekton Pipeline CR
-			err := client.Delete(ctx, &tektonPipelineCRList[0])
-			if err != nil {
-				pipelineLogger.Error(err, "Error occurred when deleting Tekton Pipeline", "Tekton Pipeline", pipelineName)
-				return err
-
-			}
-			pipelineLogger.Info("Successfully deleted Tekton Pipeline CR created by orchestrator", "Tekton Pipeline", pipelineName)
-			return nil
-		}
+// getGitCLIParams returns the params of a git-cli pipeline task running the script with the git author of the
+// pipeline configuration.
+func getGitCLIParams(pipelineConfig orchestratorv1alpha3.TektonPipeline, script string) []tektonv1.Param {
+	return []tektonv1.Param{
+		{Name: "GIT_USER_NAME", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: pipelineConfig.GitAuthor.Name}},
+		{Name: "GIT_USER_EMAIL", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: pipelineConfig.GitAuthor.Email}},
+		{Name: "USER_HOME", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: pipelineConfig.GitUserHome}},
+		{Name: "GIT_SCRIPT", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: script}},
 	}
-	return nil
 }
 
-func listTektonPipelineCR(ctx context.Context, k8client client.Client, namespace string) ([]tektonv1.Pipeline, error) {
-	pipelineLogger := log.FromContext(ctx)
-
-	crList := &tektonv1.PipelineList{}
-
-	listOptions := []client.ListOption{
-		client.InNamespace(namespace),
-		client.MatchingLabels{kube.CreatedByLabelKey: kube.CreatedByLabelValue},
+// getBuildAndPushImagePipelineTask returns the pipeline task building the workflow image with the builder of the
+// pipeline configuration and pushing it to the configured registry.
+func getBuildAndPushImagePipelineTask(pipelineConfig orchestratorv1alpha3.TektonPipeline) tektonv1.PipelineTask {
+	image := pipelineConfig.RegistryHost + "/" + pipelineConfig.ImagePathTemplate + ":$(tasks.fetch-workflow.results.commit)"
+	pipelineTask := tektonv1.PipelineTask{
+		Name:     buildAndPushImagePipelineTask,
+		RunAfter: []string{flattenWorkflowPipelineTask},
+		Workspaces: []tektonv1.WorkspacePipelineTaskBinding{
+			{Name: "source", Workspace: "workflow-source"},
+			{Name: "dockerconfig", Workspace: "docker-credentials"},
+		},
+		Params: []tektonv1.Param{
+			{Name: "IMAGE", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: image}},
+			{Name: "DOCKERFILE", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "flat/workflow-builder.Dockerfile"}},
+			{Name: "CONTEXT", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "flat/$(params.workflowId)"}},
+		},
 	}
 
-	// List the CRs
-	if err := k8client.List(ctx, crList, listOptions...); err != nil {
-		pipelineLogger.Error(err, "Error occurred when listing Tekton Pipeline CRs")
-		return nil, err
+	if pipelineConfig.Builder == orchestratorv1alpha3.BuilderKaniko {
+		pipelineTask.TaskRef = &tektonv1.TaskRef{Name: kanikoTask}
+		return pipelineTask
 	}
+	pipelineTask.TaskRef = &tektonv1.TaskRef{
+		ResolverRef: tektonv1.ResolverRef{
+			Resolver: "cluster",
+			Params: []tektonv1.Param{
+				{Name: "kind", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "task"}},
+				{Name: "name", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "buildah"}},
+				{Name: "namespace", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "openshift-pipelines"}},
+			},
+		},
+	}
+	pipelineTask.Params = append(pipelineTask.Params, tektonv1.Param{Name: "BUILD_EXTRA_ARGS", Value: tektonv1.ParamValue{
+		Type:      tektonv1.ParamTypeString,
+		StringVal: "--authfile=/workspace/dockerconfig/.dockerconfigjson --ulimit nofile=4096:4096 --build-arg WF_RESOURCES=.",
+	}})
+	return pipelineTask
+}
 
-	pipelineLogger.Info("Successfully listed Tekton Pipeline CRs", "Total", len(crList.Items))
-	return crList.Items, nil
+// listTektonPipelineCleanUpObjects returns the Pipelines owned by owner.
+func listTektonPipelineCleanUpObjects(ctx context.Context, k8Client client.Client, gitOpsNamespace string, owner kube.Owner) ([]client.Object, error) {
+	return kube.ListOwnedCustomResources(ctx, k8Client, &tektonv1.PipelineList{},
+		func(list *tektonv1.PipelineList) []*tektonv1.Pipeline {
+			objs := make([]*tektonv1.Pipeline, len(list.Items))
+			for i := range list.Items {
+				objs[i] = &list.Items[i]
+			}
+			return objs
+		}, gitOpsNamespace, owner)
 }
@@ -0,0 +1,241 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitops
+
+import (
+	"context"
+	"testing"
+
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/version"
+	"github.com/stretchr/testify/assert"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func getParam(params []tektonv1.Param, name string) string {
+	for _, param := range params {
+		if param.Name == name {
+			return param.Value.StringVal
+		}
+	}
+	return ""
+}
+
+func getPipelineTask(pipeline *tektonv1.Pipeline, name string) tektonv1.PipelineTask {
+	for _, task := range pipeline.Spec.Tasks {
+		if task.Name == name {
+			return task
+		}
+	}
+	return tektonv1.PipelineTask{}
+}
+
+func TestGetPipelineObjectDefaults(t *testing.T) {
+	pipeline := getPipelineObject(testGitOpsNamespace, testInstance, withPipelineDefaults(orchestratorv1alpha3.TektonPipeline{}))
+
+	buildTask := getPipelineTask(pipeline, buildAndPushImagePipelineTask)
+	assert.Equal(t, "quay.io/$(params.quayOrgName)/$(params.quayRepoName):$(tasks.fetch-workflow.results.commit)",
+		getParam(buildTask.Params, "IMAGE"))
+	assert.Equal(t, "cluster", string(buildTask.TaskRef.Resolver))
+	assert.NotEmpty(t, getParam(buildTask.Params, "BUILD_EXTRA_ARGS"))
+
+	fetchTask := getPipelineTask(pipeline, fetchWorkflowPipelineTask)
+	assert.Equal(t, defaultGitUserName, getParam(fetchTask.Params, "GIT_USER_NAME"))
+	assert.Equal(t, defaultGitUserEmail, getParam(fetchTask.Params, "GIT_USER_EMAIL"))
+	assert.Equal(t, defaultGitUserHome, getParam(fetchTask.Params, "USER_HOME"))
+
+	// the Quay params are referenced by the default image path, so they are required
+	for _, param := range pipeline.Spec.Params {
+		if param.Name == quayOrgNameParam || param.Name == quayRepoNameParam {
+			assert.Nil(t, param.Default)
+		}
+	}
+}
+
+func TestGetPipelineObject(t *testing.T) {
+	team := "workflows"
+	pipelineConfig := orchestratorv1alpha3.TektonPipeline{
+		RegistryHost:      "registry.example.com",
+		ImagePathTemplate: "$(params.team)/$(params.workflowId)",
+		GitAuthor:         orchestratorv1alpha3.GitAuthor{Name: "Workflow Bot", Email: "bot@example.com"},
+		GitUserHome:       "/home/bot",
+		Builder:           orchestratorv1alpha3.BuilderKaniko,
+		ExtraParams: []orchestratorv1alpha3.TektonParam{
+			{Name: "team", Description: "The team owning the workflow", Default: &team},
+		},
+	}
+	pipeline := getPipelineObject(testGitOpsNamespace, testInstance, withPipelineDefaults(pipelineConfig))
+
+	buildTask := getPipelineTask(pipeline, buildAndPushImagePipelineTask)
+	assert.Equal(t, "registry.example.com/$(params.team)/$(params.workflowId):$(tasks.fetch-workflow.results.commit)",
+		getParam(buildTask.Params, "IMAGE"))
+	assert.Equal(t, &tektonv1.TaskRef{Name: kanikoTask}, buildTask.TaskRef)
+	assert.Empty(t, getParam(buildTask.Params, "BUILD_EXTRA_ARGS"))
+
+	for _, name := range []string{fetchWorkflowPipelineTask, fetchWorkflowGitOpsPipelineTask, pushWorkflowGitOpsPipelineTask} {
+		task := getPipelineTask(pipeline, name)
+		assert.Equal(t, "Workflow Bot", getParam(task.Params, "GIT_USER_NAME"), name)
+		assert.Equal(t, "bot@example.com", getParam(task.Params, "GIT_USER_EMAIL"), name)
+		assert.Equal(t, "/home/bot", getParam(task.Params, "USER_HOME"), name)
+	}
+
+	params := map[string]tektonv1.ParamSpec{}
+	for _, param := range pipeline.Spec.Params {
+		params[param.Name] = param
+	}
+	assert.Equal(t, tektonv1.ParamSpec{
+		Name:        "team",
+		Description: "The team owning the workflow",
+		Type:        tektonv1.ParamTypeString,
+		Default:     &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "workflows"},
+	}, params["team"])
+	// the Quay params are optional when the image path does not reference them
+	assert.Equal(t, "", params[quayOrgNameParam].Default.StringVal)
+	assert.Equal(t, "", params[quayRepoNameParam].Default.StringVal)
+}
+
+func TestHandleTektonPipelineAndTasks(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(tektonv1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: pipelineCRDName}},
+		&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: tektonCRDName}},
+	).Build()
+
+	// the pipeline is created with the defaults
+	_, err := HandleTekton(fakeClient, ctx, testGitOpsNamespace, testInstance, orchestratorv1alpha3.TektonPipeline{})
+	assert.NoError(t, err)
+	task := &tektonv1.Task{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: testGitOpsNamespace, Name: flattenerTask}, task))
+	assert.Equal(t, defaultBaseImage, task.Spec.Steps[0].Image)
+	err = fakeClient.Get(ctx, types.NamespacedName{Namespace: testGitOpsNamespace, Name: kanikoTask}, task)
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// changes of the configuration are rendered into the existing pipeline and tasks
+	pipelineConfig := orchestratorv1alpha3.TektonPipeline{
+		RegistryHost: "registry.example.com",
+		Builder:      orchestratorv1alpha3.BuilderKaniko,
+		Images:       orchestratorv1alpha3.TektonTaskImages{Git: "registry.example.com/git:2.45", Base: "registry.example.com/ubi9"},
+	}
+	_, err = HandleTekton(fakeClient, ctx, testGitOpsNamespace, testInstance, pipelineConfig)
+	assert.NoError(t, err)
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: testGitOpsNamespace, Name: flattenerTask}, task))
+	assert.Equal(t, "registry.example.com/ubi9", task.Spec.Steps[0].Image)
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: testGitOpsNamespace, Name: gitCLITask}, task))
+	assert.Equal(t, "registry.example.com/git:2.45", task.Spec.Params[0].Default.StringVal)
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: testGitOpsNamespace, Name: kanikoTask}, task))
+	assert.Equal(t, defaultKanikoImage, task.Spec.Steps[1].Image)
+
+	pipeline := &tektonv1.Pipeline{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: testGitOpsNamespace, Name: pipelineName}, pipeline))
+	buildTask := getPipelineTask(pipeline, buildAndPushImagePipelineTask)
+	assert.Equal(t, kanikoTask, buildTask.TaskRef.Name)
+	assert.Equal(t, "registry.example.com/$(params.quayOrgName)/$(params.quayRepoName):$(tasks.fetch-workflow.results.commit)",
+		getParam(buildTask.Params, "IMAGE"))
+
+	// the kaniko task is deleted when the pipeline builds with buildah again
+	_, err = HandleTekton(fakeClient, ctx, testGitOpsNamespace, testInstance, orchestratorv1alpha3.TektonPipeline{})
+	assert.NoError(t, err)
+	err = fakeClient.Get(ctx, client.ObjectKey{Namespace: testGitOpsNamespace, Name: kanikoTask}, task)
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestHandleTektonWithoutPipelineCRD(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(tektonv1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: tektonCRDName}},
+	).Build()
+
+	// no Task is created until the Pipeline CRD is installed as well
+	_, err := HandleTekton(fakeClient, ctx, testGitOpsNamespace, testInstance, orchestratorv1alpha3.TektonPipeline{})
+	assert.True(t, apierrors.IsNotFound(err))
+	tasks := &tektonv1.TaskList{}
+	assert.NoError(t, fakeClient.List(ctx, tasks))
+	assert.Empty(t, tasks.Items)
+}
+
+func TestHandleTektonPipelineTasksVersions(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(tektonv1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+
+	defer func(operatorVersion string) { version.Version = operatorVersion }(version.Version)
+	version.Version = "1.6.0"
+
+	// a Task created by a previous version of the operator, without annotations, and with an outdated script
+	outdatedTask := getTaskObject(testGitOpsNamespace, testInstance, flattenerTask, withPipelineDefaults(orchestratorv1alpha3.TektonPipeline{}).Images)
+	outdatedTask.Spec.Steps[0].Script = "echo outdated"
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: pipelineCRDName}},
+		&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: tektonCRDName}},
+		outdatedTask,
+	).Build()
+
+	statuses, err := HandleTekton(fakeClient, ctx, testGitOpsNamespace, testInstance, orchestratorv1alpha3.TektonPipeline{})
+	assert.NoError(t, err)
+	assert.Len(t, statuses, len(tektonTaskList)+1)
+	for _, status := range statuses {
+		assert.Equal(t, "1.6.0", status.OperatorVersion, status.Name)
+		assert.NotEmpty(t, status.ContentHash, status.Name)
+	}
+	assert.Equal(t, orchestratorv1alpha3.TektonResourceStatus{
+		Kind: pipelineKind, Name: pipelineName, OperatorVersion: "1.6.0", ContentHash: statuses[len(statuses)-1].ContentHash,
+	}, statuses[len(statuses)-1])
+
+	task := &tektonv1.Task{}
+	assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(outdatedTask), task))
+	assert.Equal(t, flattenerTaskScript, task.Spec.Steps[0].Script)
+	assert.Equal(t, "1.6.0", task.Annotations[kube.OperatorVersionAnnotation])
+
+	// the resources whose content did not change keep the version that rendered them after an upgrade
+	version.Version = "1.6.1"
+	resourceVersion := task.ResourceVersion
+	upgradedStatuses, err := HandleTekton(fakeClient, ctx, testGitOpsNamespace, testInstance, orchestratorv1alpha3.TektonPipeline{})
+	assert.NoError(t, err)
+	assert.Equal(t, statuses, upgradedStatuses)
+	assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(outdatedTask), task))
+	assert.Equal(t, resourceVersion, task.ResourceVersion)
+
+	// the resources whose content changed are stamped with the new version
+	upgradedStatuses, err = HandleTekton(fakeClient, ctx, testGitOpsNamespace, testInstance,
+		orchestratorv1alpha3.TektonPipeline{Images: orchestratorv1alpha3.TektonTaskImages{Git: "registry.example.com/git:2.45"}})
+	assert.NoError(t, err)
+	for _, status := range upgradedStatuses {
+		if status.Name == gitCLITask {
+			assert.Equal(t, "1.6.1", status.OperatorVersion)
+		} else {
+			assert.Equal(t, "1.6.0", status.OperatorVersion, status.Name)
+		}
+	}
+}
@@ -97,6 +97,9 @@ cd kustomize
 ./updater.sh $(params.workflowId) $(params.imageTag)
 `
 
+const kanikoDockerConfigScript = `cp $(workspaces.dockerconfig.path)/.dockerconfigjson /kaniko/.docker/config.json
+`
+
 const gitScript = `WORKFLOW_COMMIT=$(tasks.fetch-workflow.results.commit)
 eval "$(ssh-agent -s)"
 ssh-add "${PARAM_USER_HOME}"/.ssh/id_rsa
@@ -18,7 +18,11 @@ package gitops
 
 import (
 	"context"
+
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
 	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/metrics"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/version"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -35,6 +39,7 @@ const (
 	flattenerTask        = "flattener"
 	buildManifestTask    = "build-manifests"
 	buildGitOpsTask      = "build-gitops"
+	kanikoTask           = "kaniko"
 	tektonCRDName        = "tasks.tekton.dev"
 )
 
@@ -45,52 +50,142 @@ var tektonTaskList = []string{
 	buildGitOpsTask,
 }
 
-func HandleTektonTasks(client client.Client, ctx context.Context, gitOpsNamespace string) error {
+// HandleTektonTasks creates the Tekton Tasks of the workflow-deployment Pipeline, or updates them when their content
+// changed, i.e. after an operator upgrade, and returns their versions.
+func HandleTektonTasks(client client.Client, ctx context.Context, gitOpsNamespace, instance string,
+	pipelineConfig orchestratorv1alpha3.TektonPipeline) ([]orchestratorv1alpha3.TektonResourceStatus, error) {
 	taskLogger := log.FromContext(ctx)
 	taskLogger.Info("Handling Tekton Tasks...")
 
 	if err := kube.CheckCRDExists(ctx, client, tektonCRDName); err != nil {
 		taskLogger.Error(err, "Tekton Task CRD does not exist. Install RedHat Openshift Pipelines Operator")
-		return err
+		return nil, err
+	}
+
+	pipelineConfig = withPipelineDefaults(pipelineConfig)
+	taskNames := tektonTaskList
+	if pipelineConfig.Builder == orchestratorv1alpha3.BuilderKaniko {
+		taskNames = append(taskNames[:len(taskNames):len(taskNames)], kanikoTask)
+	}
+
+	var taskStatuses []orchestratorv1alpha3.TektonResourceStatus
+	for _, taskName := range taskNames {
+		taskStatus, err := handleTektonTask(client, ctx, getTaskObject(gitOpsNamespace, instance, taskName, pipelineConfig.Images))
+		if err != nil {
+			return taskStatuses, err
+		}
+		taskStatuses = append(taskStatuses, taskStatus)
+	}
+
+	if pipelineConfig.Builder != orchestratorv1alpha3.BuilderKaniko {
+		return taskStatuses, deleteKanikoTask(client, ctx, gitOpsNamespace, instance)
+	}
+	return taskStatuses, nil
+}
+
+// handleTektonTask creates the Tekton Task, or updates it when the hash of the desired spec differs from the hash
+// the operator last rendered into it, and returns its version.
+func handleTektonTask(client client.Client, ctx context.Context, desiredTask *tektonv1.Task) (orchestratorv1alpha3.TektonResourceStatus, error) {
+	taskLogger := log.FromContext(ctx)
+
+	specHash, err := kube.ComputeConfigHash(desiredTask.Spec)
+	if err != nil {
+		taskLogger.Error(err, "Error occurred when computing hash of Tekton Task spec", "Task", desiredTask.Name)
+		return orchestratorv1alpha3.TektonResourceStatus{}, err
 	}
+	setTektonResourceVersion(desiredTask, specHash)
 
-	for _, taskName := range tektonTaskList {
-		if err := client.Get(ctx, types.NamespacedName{
-			Namespace: gitOpsNamespace, Name: taskName}, &tektonv1.Task{}); err != nil {
-			if apierrors.IsNotFound(err) {
-				tektonTask := getTaskObject(gitOpsNamespace, taskName)
-				if tektonTask != nil {
-					if err := client.Create(ctx, tektonTask); err != nil {
-						taskLogger.Error(err, "Error occurred when creating Tekton Task", "Task", taskName)
-						return err
-					}
-					taskLogger.Info("Successfully created Tekton Task", "Task", taskName)
-					continue
-				}
+	existingTask := &tektonv1.Task{}
+	if err := client.Get(ctx, types.NamespacedName{
+		Namespace: desiredTask.Namespace, Name: desiredTask.Name}, existingTask); err != nil {
+		if apierrors.IsNotFound(err) {
+			if err := client.Create(ctx, desiredTask); err != nil {
+				taskLogger.Error(err, "Error occurred when creating Tekton Task", "Task", desiredTask.Name)
+				return orchestratorv1alpha3.TektonResourceStatus{}, err
 			}
-			taskLogger.Error(err, "Error occurred when checking task exist", "Task", taskName)
-			continue
+			taskLogger.Info("Successfully created Tekton Task", "Task", desiredTask.Name)
+			return getTektonResourceStatus(tektonKind, desiredTask), nil
 		}
+		taskLogger.Error(err, "Error occurred when checking task exist", "Task", desiredTask.Name)
+		return orchestratorv1alpha3.TektonResourceStatus{}, err
 	}
+
+	adopted := adoptLabels(existingTask, desiredTask)
+	if adopted || existingTask.Annotations[kube.ConfigHashAnnotation] != specHash {
+		existingTask.Spec = desiredTask.Spec
+		setTektonResourceVersion(existingTask, specHash)
+		if err := client.Update(ctx, existingTask); err != nil {
+			taskLogger.Error(err, "Error occurred when updating Tekton Task", "Task", desiredTask.Name)
+			return orchestratorv1alpha3.TektonResourceStatus{}, err
+		}
+		taskLogger.Info("Successfully updated Tekton Task", "Task", desiredTask.Name, "Version", version.Version)
+		metrics.RecordDriftCorrection(tektonKind)
+	}
+	return getTektonResourceStatus(tektonKind, existingTask), nil
+}
+
+// setTektonResourceVersion records the hash of the spec rendered into a Tekton resource and the version of the
+// operator rendering it. The hash, rather than the spec, is compared with the desired one, since the Tekton
+// webhooks set the defaults of the spec.
+func setTektonResourceVersion(obj metav1.Object, specHash string) {
+	kube.SetConfigHash(obj, specHash)
+	obj.GetAnnotations()[kube.OperatorVersionAnnotation] = version.Version
+}
+
+// getTektonResourceStatus returns the version of a Tekton resource created by the operator.
+func getTektonResourceStatus(kind string, obj metav1.Object) orchestratorv1alpha3.TektonResourceStatus {
+	return orchestratorv1alpha3.TektonResourceStatus{
+		Kind:            kind,
+		Name:            obj.GetName(),
+		OperatorVersion: obj.GetAnnotations()[kube.OperatorVersionAnnotation],
+		ContentHash:     obj.GetAnnotations()[kube.ConfigHashAnnotation],
+	}
+}
+
+// deleteKanikoTask deletes the kaniko Task created by the operator for the instance Orchestrator once the pipeline
+// builds with buildah.
+func deleteKanikoTask(client client.Client, ctx context.Context, gitOpsNamespace, instance string) error {
+	taskLogger := log.FromContext(ctx)
+
+	task := &tektonv1.Task{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: gitOpsNamespace, Name: kanikoTask}, task); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !(kube.Owner{Instance: instance}).Owns(task) {
+		return nil
+	}
+	if err := client.Delete(ctx, task); err != nil && !apierrors.IsNotFound(err) {
+		taskLogger.Error(err, "Error occurred when deleting Tekton Task", "Task", kanikoTask)
+		return err
+	}
+	taskLogger.Info("Successfully deleted Tekton Task", "Task", kanikoTask)
 	return nil
 }
 
-func getTaskObject(gitOpsNamespace, taskName string) *tektonv1.Task {
+func getTaskObject(gitOpsNamespace, instance, taskName string, images orchestratorv1alpha3.TektonTaskImages) *tektonv1.Task {
+	var task *tektonv1.Task
 	switch taskName {
 	case gitCLITask:
-		return createGitCLITaskObject(gitOpsNamespace)
+		task = createGitCLITaskObject(gitOpsNamespace, images.Git)
 	case flattenerTask:
-		return createFlattenerTaskObject(gitOpsNamespace)
+		task = createFlattenerTaskObject(gitOpsNamespace, images.Base)
 	case buildManifestTask:
-		return createBuildManifestTaskObject(gitOpsNamespace)
+		task = createBuildManifestTaskObject(gitOpsNamespace, images.Base)
 	case buildGitOpsTask:
-		return createBuildGitOpsTaskObject(gitOpsNamespace)
+		task = createBuildGitOpsTaskObject(gitOpsNamespace, images.Base)
+	case kanikoTask:
+		task = createKanikoTaskObject(gitOpsNamespace, images.Base, images.Kaniko)
 	default:
 		return nil
 	}
+	task.Labels = kube.GetInstanceLabels(instance)
+	return task
 }
 
-func createGitCLITaskObject(gitOpsNamespace string) *tektonv1.Task {
+func createGitCLITaskObject(gitOpsNamespace, gitImage string) *tektonv1.Task {
 	return &tektonv1.Task{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: tektonTaskAPIVersion,
@@ -99,7 +194,6 @@ func createGitCLITaskObject(gitOpsNamespace string) *tektonv1.Task {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      gitCLITask,
 			Namespace: gitOpsNamespace,
-			Labels:    kube.GetOrchestratorLabel(),
 			Annotations: map[string]string{
 				"tekton.dev/pipelines.minVersion": "0.21.0",
 				"tekton.dev/categories":           "Git",
@@ -138,7 +232,7 @@ func createGitCLITaskObject(gitOpsNamespace string) *tektonv1.Task {
 					Type:        tektonv1.ParamTypeString,
 					Default: &tektonv1.ParamValue{
 						Type:      tektonv1.ParamTypeString,
-						StringVal: "cgr.dev/chainguard/git:root-2.39@sha256:7759f87050dd8bacabe61354d75ccd7f864d6b6f8ec42697db7159eccd491139"},
+						StringVal: gitImage},
 				},
 				{
 					Name:        "GIT_USER_NAME",
@@ -214,7 +308,7 @@ func createGitCLITaskObject(gitOpsNamespace string) *tektonv1.Task {
 	}
 }
 
-func createFlattenerTaskObject(gitOpsNamespace string) *tektonv1.Task {
+func createFlattenerTaskObject(gitOpsNamespace, baseImage string) *tektonv1.Task {
 	return &tektonv1.Task{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: tektonTaskAPIVersion,
@@ -223,7 +317,6 @@ func createFlattenerTaskObject(gitOpsNamespace string) *tektonv1.Task {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      flattenerTask,
 			Namespace: gitOpsNamespace,
-			Labels:    kube.GetOrchestratorLabel(),
 		},
 		Spec: tektonv1.TaskSpec{
 			Workspaces: []tektonv1.WorkspaceDeclaration{
@@ -250,7 +343,7 @@ func createFlattenerTaskObject(gitOpsNamespace string) *tektonv1.Task {
 			Steps: []tektonv1.Step{
 				{
 					Name:       "flatten",
-					Image:      "registry.access.redhat.com/ubi9-minimal",
+					Image:      baseImage,
 					WorkingDir: "$(workspaces.workflow-source.path)",
 					Script:     flattenerTaskScript,
 				},
@@ -259,7 +352,7 @@ func createFlattenerTaskObject(gitOpsNamespace string) *tektonv1.Task {
 	}
 }
 
-func createBuildManifestTaskObject(gitOpsNamespace string) *tektonv1.Task {
+func createBuildManifestTaskObject(gitOpsNamespace, baseImage string) *tektonv1.Task {
 	return &tektonv1.Task{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: tektonTaskAPIVersion,
@@ -268,7 +361,6 @@ func createBuildManifestTaskObject(gitOpsNamespace string) *tektonv1.Task {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      buildManifestTask,
 			Namespace: gitOpsNamespace,
-			Labels:    kube.GetOrchestratorLabel(),
 		},
 		Spec: tektonv1.TaskSpec{
 			Workspaces: []tektonv1.WorkspaceDeclaration{
@@ -286,7 +378,7 @@ func createBuildManifestTaskObject(gitOpsNamespace string) *tektonv1.Task {
 			Steps: []tektonv1.Step{
 				{
 					Name:       buildManifestTask,
-					Image:      "registry.access.redhat.com/ubi9-minimal",
+					Image:      baseImage,
 					WorkingDir: "$(workspaces.workflow-source.path)/flat/$(params.workflowId)",
 					Script:     buildManifestTaskScript,
 				},
@@ -295,7 +387,7 @@ func createBuildManifestTaskObject(gitOpsNamespace string) *tektonv1.Task {
 	}
 }
 
-func createBuildGitOpsTaskObject(gitOpsNamespace string) *tektonv1.Task {
+func createBuildGitOpsTaskObject(gitOpsNamespace, baseImage string) *tektonv1.Task {
 	return &tektonv1.Task{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: tektonTaskAPIVersion,
@@ -304,7 +396,6 @@ func createBuildGitOpsTaskObject(gitOpsNamespace string) *tektonv1.Task {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      buildGitOpsTask,
 			Namespace: gitOpsNamespace,
-			Labels:    kube.GetOrchestratorLabel(),
 		},
 		Spec: tektonv1.TaskSpec{
 			Workspaces: []tektonv1.WorkspaceDeclaration{
@@ -325,7 +416,7 @@ func createBuildGitOpsTaskObject(gitOpsNamespace string) *tektonv1.Task {
 			Steps: []tektonv1.Step{
 				{
 					Name:       buildGitOpsTask,
-					Image:      "registry.access.redhat.com/ubi9-minimal",
+					Image:      baseImage,
 					WorkingDir: "$(workspaces.workflow-gitops.path)/workflow-gitops",
 					Script:     buildGitOpsTaskScript,
 				},
@@ -334,51 +425,77 @@ func createBuildGitOpsTaskObject(gitOpsNamespace string) *tektonv1.Task {
 	}
 }
 
-func handleTektonTaskCleanUp(client client.Client, ctx context.Context, gitOpsNamespace string) error {
-	taskLogger := log.FromContext(ctx)
-	taskLogger.Info("Handling Tekton Tasks cleanup...")
-
-	namespaceExist, _ := kube.CheckNamespaceExist(ctx, client, gitOpsNamespace)
-	if namespaceExist {
-		tektonTaskList, err := listTektonTaskCR(ctx, client, gitOpsNamespace)
-
-		if err != nil || len(tektonTaskList) == 0 {
-			taskLogger.Info("Failed to list or have no Tekton Task created by Orchestrator Operator and cannot perform clean up process")
-			return nil
-		}
-
-		for _, tektonTask := range tektonTaskList {
-			taskLogger.Info("Deleting Tekton Task ", "Task", tektonTask.Name)
-			// remove tekton task
-			err := client.Delete(ctx, &tektonTask)
-			if err != nil {
-				taskLogger.Error(err, "Error occurred when deleting tekton task", "Tekton Task", tektonTask.Name)
-				continue
-			}
-			taskLogger.Info("Successfully deleted Tekton Task CR created by orchestrator", "Tekton Task", tektonTask.Name)
-			continue
-		}
+func createKanikoTaskObject(gitOpsNamespace, baseImage, kanikoImage string) *tektonv1.Task {
+	return &tektonv1.Task{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: tektonTaskAPIVersion,
+			Kind:       tektonKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kanikoTask,
+			Namespace: gitOpsNamespace,
+		},
+		Spec: tektonv1.TaskSpec{
+			Description: "This task builds a container image from a Dockerfile with Kaniko and pushes it to a registry.",
+			Workspaces: []tektonv1.WorkspaceDeclaration{
+				{Name: "source"},
+				{Name: "dockerconfig", Description: "A workspace containing the .dockerconfigjson file to push the image."},
+			},
+			Params: []tektonv1.ParamSpec{
+				{
+					Name:        "IMAGE",
+					Description: "Name (reference) of the image to build.",
+					Type:        tektonv1.ParamTypeString,
+				},
+				{
+					Name:        "DOCKERFILE",
+					Description: "Path to the Dockerfile to build.",
+					Type:        tektonv1.ParamTypeString,
+					Default:     &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "./Dockerfile"},
+				},
+				{
+					Name:        "CONTEXT",
+					Description: "The build context used by Kaniko.",
+					Type:        tektonv1.ParamTypeString,
+					Default:     &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "./"},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{Name: "docker-config", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+			Steps: []tektonv1.Step{
+				{
+					Name:         "docker-config",
+					Image:        baseImage,
+					VolumeMounts: []corev1.VolumeMount{{Name: "docker-config", MountPath: "/kaniko/.docker"}},
+					Script:       kanikoDockerConfigScript,
+				},
+				{
+					Name:         "build-and-push",
+					Image:        kanikoImage,
+					WorkingDir:   "$(workspaces.source.path)",
+					VolumeMounts: []corev1.VolumeMount{{Name: "docker-config", MountPath: "/kaniko/.docker"}},
+					Env:          []corev1.EnvVar{{Name: "DOCKER_CONFIG", Value: "/kaniko/.docker"}},
+					Args: []string{
+						"--dockerfile=$(params.DOCKERFILE)",
+						"--context=$(workspaces.source.path)/$(params.CONTEXT)",
+						"--destination=$(params.IMAGE)",
+						"--build-arg=WF_RESOURCES=.",
+					},
+				},
+			},
+		},
 	}
-
-	return nil
 }
 
-func listTektonTaskCR(ctx context.Context, k8client client.Client, namespace string) ([]tektonv1.Task, error) {
-	taskLogger := log.FromContext(ctx)
-
-	crList := &tektonv1.TaskList{}
-
-	listOptions := []client.ListOption{
-		client.InNamespace(namespace),
-		client.MatchingLabels{kube.CreatedByLabelKey: kube.CreatedByLabelValue},
-	}
-
-	// List the CRs
-	if err := k8client.List(ctx, crList, listOptions...); err != nil {
-		taskLogger.Error(err, "Error occurred when listing Tekton Task CRs")
-		return nil, err
-	}
-
-	taskLogger.Info("Successfully listed Tekton Task CRs", "Total", len(crList.Items))
-	return crList.Items, nil
+// listTektonTaskCleanUpObjects returns the Tasks owned by owner.
+func listTektonTaskCleanUpObjects(ctx context.Context, k8Client client.Client, gitOpsNamespace string, owner kube.Owner) ([]client.Object, error) {
+	return kube.ListOwnedCustomResources(ctx, k8Client, &tektonv1.TaskList{},
+		func(list *tektonv1.TaskList) []*tektonv1.Task {
+			objs := make([]*tektonv1.Task, len(list.Items))
+			for i := range list.Items {
+				objs[i] = &list.Items[i]
+			}
+			return objs
+		}, gitOpsNamespace, owner)
 }
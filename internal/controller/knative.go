@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	olmclientset "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
 	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -63,7 +64,8 @@ func handleKNativeOperatorInstallation(ctx context.Context, client client.Client
 		knativeSubscriptionName,
 		knativeOperatorNamespace,
 		knativeSubscriptionChannel,
-		knativeSubscriptionStartingCSV)
+		knativeSubscriptionStartingCSV,
+		orchestratorv1alpha3.Subscription{})
 
 	// check if subscription exists
 	subscriptionExists, existingSubscription, err := kube.CheckSubscriptionExists(ctx, olmClientSet, serverlessSubscription)
@@ -229,20 +231,20 @@ func handleKnativeServingCR(ctx context.Context, client client.Client) error {
 	return nil
 }
 
-func handleKnativeCleanUp(ctx context.Context, client client.Client) error {
+func handleKnativeCleanUp(ctx context.Context, client client.Client, owner kube.Owner) error {
 	logger := log.FromContext(ctx)
 	// remove all namespace
-	if err := kube.CleanUpNamespace(ctx, knativeEventingNamespacedName, client); err != nil {
+	if err := kube.CleanUpNamespace(ctx, knativeEventingNamespacedName, client, owner); err != nil {
 		logger.Error(err, "Error occurred when deleting namespace", "NS", knativeEventingNamespacedName)
 		return err
 	}
-	if err := kube.CleanUpNamespace(ctx, knativeServingNamespacedName, client); err != nil {
+	if err := kube.CleanUpNamespace(ctx, knativeServingNamespacedName, client, owner); err != nil {
 		logger.Error(err, "Error occurred when deleting namespace", "NS", knativeServingNamespacedName)
 		return err
 	}
 
 	// remove operator namespace
-	if err := kube.CleanUpNamespace(ctx, knativeOperatorNamespace, client); err != nil {
+	if err := kube.CleanUpNamespace(ctx, knativeOperatorNamespace, client, owner); err != nil {
 		logger.Error(err, "Error occurred when deleting namespace", "NS", knativeOperatorNamespace)
 		return err
 	}
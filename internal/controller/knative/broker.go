@@ -0,0 +1,277 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knative
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	BrokerAPIVersion              = "eventing.knative.dev/v1"
+	BrokerKind                    = "Broker"
+	BrokerClassAnnotation         = "eventing.knative.dev/broker.class"
+	InMemoryBrokerClass           = "MTChannelBasedBroker"
+	KafkaBrokerClass              = "Kafka"
+	InMemoryChannelConfigMap      = "config-br-default-channel"
+	KafkaBrokerConfigMapSuffix    = "-kafka-config"
+	KafkaBootstrapServersKey      = "bootstrap.servers"
+	KafkaPartitionsKey            = "default.topic.partitions"
+	KafkaReplicationFactorKey     = "default.topic.replication.factor"
+	defaultKafkaPartitions        = 10
+	defaultKafkaReplicationFactor = 1
+)
+
+// BrokerNotReadyError is returned when the Broker created by the operator does not have a Ready condition
+// set to True yet, so it cannot be referenced by the SonataFlowPlatform.
+type BrokerNotReadyError struct {
+	Name      string
+	Namespace string
+	Reason    string
+	Message   string
+}
+
+func (e *BrokerNotReadyError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("broker %s/%s is not ready yet", e.Namespace, e.Name)
+	}
+	return fmt.Sprintf("broker %s/%s is not ready: %s %s", e.Namespace, e.Name, e.Reason, e.Message)
+}
+
+// IsBrokerNotReady returns whether err reports a Broker that is not ready yet.
+func IsBrokerNotReady(err error) bool {
+	var brokerErr *BrokerNotReadyError
+	return goerrors.As(err, &brokerErr)
+}
+
+// HandleBrokerCR creates or updates the Broker configured in the Orchestrator with server-side apply in the
+// workflow namespace. A BrokerNotReadyError is returned until the Broker is ready.
+func HandleBrokerCR(ctx context.Context, client client.Client, broker orchestratorv1alpha3.Broker, namespace, instance string) error {
+	logger := log.FromContext(ctx)
+	logger.Info("Applying Broker CR...", "CR-Name", broker.Name, "NS", namespace)
+
+	brokerClass := getBrokerClass(broker)
+	// the class of a Broker cannot be changed, so the Broker is deleted and created again on the next reconciliation
+	if deleted, err := deleteBrokerOfOtherClass(ctx, client, broker.Name, namespace, instance, brokerClass); err != nil || deleted {
+		if err != nil {
+			return err
+		}
+		return &BrokerNotReadyError{
+			Name:      broker.Name,
+			Namespace: namespace,
+			Reason:    "BrokerClassChanged",
+			Message:   fmt.Sprintf("the broker is created again with the %s class", brokerClass),
+		}
+	}
+
+	var brokerConfig map[string]any
+	if broker.Type == orchestratorv1alpha3.BrokerTypeKafka {
+		configMap := getKafkaBrokerConfigMap(broker, namespace, instance)
+		if err := kube.ApplyObject(ctx, client, configMap); err != nil {
+			logger.Error(err, "Error occurred when applying ConfigMap", "CM", configMap.Name, "NS", namespace)
+			return err
+		}
+		brokerConfig = getConfigMapReference(configMap.Name, namespace)
+	} else {
+		// the default channel of the MTChannelBasedBroker is InMemoryChannel
+		brokerConfig = getConfigMapReference(InMemoryChannelConfigMap, KnativeEventingNamespacedName)
+	}
+
+	brokerCR := newBroker()
+	brokerCR.SetName(broker.Name)
+	brokerCR.SetNamespace(namespace)
+	brokerCR.SetLabels(kube.GetInstanceLabels(instance))
+	brokerCR.SetAnnotations(map[string]string{BrokerClassAnnotation: brokerClass})
+	brokerCR.Object["spec"] = map[string]any{"config": brokerConfig}
+	if err := kube.ApplyObject(ctx, client, brokerCR); err != nil {
+		logger.Error(err, "Error occurred when applying Broker CR", "CR-Name", broker.Name, "NS", namespace)
+		return err
+	}
+	logger.Info("Successfully applied Broker CR", "CR-Name", broker.Name, "NS", namespace)
+
+	return checkBrokerReady(brokerCR)
+}
+
+func getBrokerClass(broker orchestratorv1alpha3.Broker) string {
+	if broker.Type == orchestratorv1alpha3.BrokerTypeKafka {
+		return KafkaBrokerClass
+	}
+	return InMemoryBrokerClass
+}
+
+// deleteBrokerOfOtherClass deletes the Broker of instance with the given name when its class is not brokerClass,
+// and returns whether it was deleted.
+func deleteBrokerOfOtherClass(ctx context.Context, k8Client client.Client, name, namespace, instance, brokerClass string) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	existingBroker := newBroker()
+	if err := k8Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, existingBroker); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		logger.Error(err, "Error occurred when retrieving Broker CR", "CR-Name", name, "NS", namespace)
+		return false, err
+	}
+	owner := kube.Owner{Instance: instance}
+	if !owner.Owns(existingBroker) || existingBroker.GetAnnotations()[BrokerClassAnnotation] == brokerClass {
+		return false, nil
+	}
+	logger.Info("Deleting Broker CR of another class", "CR-Name", name, "NS", namespace,
+		"Class", existingBroker.GetAnnotations()[BrokerClassAnnotation])
+	if err := k8Client.Delete(ctx, existingBroker); err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "Error occurred when deleting Broker CR", "CR-Name", name, "NS", namespace)
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteStaleBrokers deletes the Brokers and the Kafka ConfigMaps of instance that are not desired anymore, i.e. all
+// of them once the creation of the broker is disabled, or the ones of a previous name, namespace or type.
+func DeleteStaleBrokers(ctx context.Context, k8Client client.Client, broker orchestratorv1alpha3.Broker, namespace, instance string) error {
+	logger := log.FromContext(ctx)
+
+	// the desired objects are identified by their description
+	desired := map[string]bool{}
+	if broker.Create {
+		desired[fmt.Sprintf("%s %s/%s", BrokerKind, namespace, broker.Name)] = true
+		if broker.Type == orchestratorv1alpha3.BrokerTypeKafka {
+			desired[fmt.Sprintf("ConfigMap %s/%s%s", namespace, broker.Name, KafkaBrokerConfigMapSuffix)] = true
+		}
+	}
+
+	// the Brokers and their ConfigMaps are always created with the instance label
+	objects, err := ListBrokerCleanUpObjects(ctx, k8Client, kube.Owner{Instance: instance})
+	if err != nil {
+		return err
+	}
+	var errorList []error
+	for _, obj := range objects {
+		description := kube.DescribeObject(k8Client, obj)
+		if desired[description] {
+			continue
+		}
+		logger.Info("Deleting stale resource", "Resource", description)
+		if err := k8Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, "Error occurred when deleting resource", "Resource", description)
+			errorList = append(errorList, fmt.Errorf("failed to delete %s: %w", description, err))
+		}
+	}
+	return errors.NewAggregate(errorList)
+}
+
+// ListBrokerCleanUpObjects returns the Brokers owned by owner in all the namespaces, followed by their Kafka
+// ConfigMaps.
+func ListBrokerCleanUpObjects(ctx context.Context, k8Client client.Client, owner kube.Owner) ([]client.Object, error) {
+	brokerList := &unstructured.UnstructuredList{}
+	brokerList.SetAPIVersion(BrokerAPIVersion)
+	brokerList.SetKind(BrokerKind + "List")
+	objects, err := kube.ListOwnedCustomResources(ctx, k8Client, brokerList,
+		func(list *unstructured.UnstructuredList) []client.Object {
+			items := make([]client.Object, len(list.Items))
+			for i := range list.Items {
+				items[i] = &list.Items[i]
+			}
+			return items
+		}, metav1.NamespaceAll, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Broker CRs: %w", err)
+	}
+
+	configMapList := &corev1.ConfigMapList{}
+	if err := k8Client.List(ctx, configMapList, client.MatchingLabels(kube.GetOrchestratorLabel())); err != nil {
+		return nil, fmt.Errorf("failed to list ConfigMaps: %w", err)
+	}
+	for i := range configMapList.Items {
+		configMap := &configMapList.Items[i]
+		if strings.HasSuffix(configMap.Name, KafkaBrokerConfigMapSuffix) && owner.Owns(configMap) {
+			objects = append(objects, configMap)
+		}
+	}
+	return objects, nil
+}
+
+func newBroker() *unstructured.Unstructured {
+	brokerCR := &unstructured.Unstructured{}
+	brokerCR.SetAPIVersion(BrokerAPIVersion)
+	brokerCR.SetKind(BrokerKind)
+	return brokerCR
+}
+
+func getKafkaBrokerConfigMap(broker orchestratorv1alpha3.Broker, namespace, instance string) *corev1.ConfigMap {
+	partitions := broker.Kafka.Partitions
+	if partitions == 0 {
+		partitions = defaultKafkaPartitions
+	}
+	replicationFactor := broker.Kafka.ReplicationFactor
+	if replicationFactor == 0 {
+		replicationFactor = defaultKafkaReplicationFactor
+	}
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      broker.Name + KafkaBrokerConfigMapSuffix,
+			Namespace: namespace,
+			Labels:    kube.GetInstanceLabels(instance),
+		},
+		Data: map[string]string{
+			KafkaBootstrapServersKey:  broker.Kafka.BootstrapServers,
+			KafkaPartitionsKey:        strconv.Itoa(int(partitions)),
+			KafkaReplicationFactorKey: strconv.Itoa(int(replicationFactor)),
+		},
+	}
+}
+
+func getConfigMapReference(name, namespace string) map[string]any {
+	return map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"name":       name,
+		"namespace":  namespace,
+	}
+}
+
+// checkBrokerReady returns a BrokerNotReadyError unless the Ready condition of the Broker is True.
+func checkBrokerReady(brokerCR *unstructured.Unstructured) error {
+	notReadyErr := &BrokerNotReadyError{Name: brokerCR.GetName(), Namespace: brokerCR.GetNamespace()}
+	conditions, _, _ := unstructured.NestedSlice(brokerCR.Object, "status", "conditions")
+	for _, condition := range conditions {
+		conditionFields, ok := condition.(map[string]any)
+		if !ok || conditionFields["type"] != "Ready" {
+			continue
+		}
+		if conditionFields["status"] == string(metav1.ConditionTrue) {
+			return nil
+		}
+		notReadyErr.Reason, _ = conditionFields["reason"].(string)
+		notReadyErr.Message, _ = conditionFields["message"].(string)
+	}
+	return notReadyErr
+}
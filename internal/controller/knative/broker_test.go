@@ -0,0 +1,187 @@
+package knative
+
+import (
+	"context"
+	"testing"
+
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const workflowNamespace = "sonataflow-infra"
+
+// newBrokerClient returns a fake client with objects that records the objects applied with server-side apply, which
+// the fake client does not support, and returns the applied Broker with the given status conditions.
+func newBrokerClient(applied map[string]client.Object, brokerConditions []any, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	brokerGVK := schema.FromAPIVersionAndKind(BrokerAPIVersion, BrokerKind)
+	scheme.AddKnownTypeWithName(brokerGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(brokerGVK.GroupVersion().WithKind(BrokerKind+"List"), &unstructured.UnstructuredList{})
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			applied[obj.GetObjectKind().GroupVersionKind().Kind] = obj.DeepCopyObject().(client.Object)
+			if brokerObject, ok := obj.(*unstructured.Unstructured); ok && brokerObject.GetKind() == BrokerKind {
+				return unstructured.SetNestedSlice(brokerObject.Object, brokerConditions, "status", "conditions")
+			}
+			return nil
+		},
+	}).Build()
+}
+
+func TestHandleBrokerCRInMemory(t *testing.T) {
+	ctx := context.TODO()
+	applied := map[string]client.Object{}
+	fakeClient := newBrokerClient(applied, []any{
+		map[string]any{"type": "Ready", "status": "True"},
+	})
+
+	broker := orchestratorv1alpha3.Broker{Name: "orchestrator-broker", Create: true}
+	assert.NoError(t, HandleBrokerCR(ctx, fakeClient, broker, workflowNamespace, "orchestrator"))
+
+	brokerObject := applied[BrokerKind].(*unstructured.Unstructured)
+	assert.Equal(t, BrokerAPIVersion, brokerObject.GetAPIVersion())
+	assert.Equal(t, types.NamespacedName{Name: "orchestrator-broker", Namespace: workflowNamespace},
+		types.NamespacedName{Name: brokerObject.GetName(), Namespace: brokerObject.GetNamespace()})
+	assert.Equal(t, kube.GetInstanceLabels("orchestrator"), brokerObject.GetLabels())
+	assert.Equal(t, InMemoryBrokerClass, brokerObject.GetAnnotations()[BrokerClassAnnotation])
+	configName, _, _ := unstructured.NestedString(brokerObject.Object, "spec", "config", "name")
+	assert.Equal(t, InMemoryChannelConfigMap, configName)
+	assert.NotContains(t, applied, "ConfigMap")
+}
+
+func TestHandleBrokerCRKafka(t *testing.T) {
+	ctx := context.TODO()
+	applied := map[string]client.Object{}
+	fakeClient := newBrokerClient(applied, []any{
+		map[string]any{"type": "Ready", "status": "False", "reason": "TopicNotPresentOrInvalid", "message": "topic not created"},
+	})
+
+	broker := orchestratorv1alpha3.Broker{
+		Name:   "kafka-broker",
+		Create: true,
+		Type:   orchestratorv1alpha3.BrokerTypeKafka,
+		Kafka:  orchestratorv1alpha3.KafkaBroker{BootstrapServers: "my-cluster-kafka-bootstrap.kafka:9092", ReplicationFactor: 3},
+	}
+	err := HandleBrokerCR(ctx, fakeClient, broker, workflowNamespace, "orchestrator")
+	assert.True(t, IsBrokerNotReady(err))
+	assert.Equal(t, "broker sonataflow-infra/kafka-broker is not ready: TopicNotPresentOrInvalid topic not created", err.Error())
+
+	configMap := applied["ConfigMap"].(*corev1.ConfigMap)
+	assert.Equal(t, "kafka-broker"+KafkaBrokerConfigMapSuffix, configMap.Name)
+	assert.Equal(t, map[string]string{
+		KafkaBootstrapServersKey:  "my-cluster-kafka-bootstrap.kafka:9092",
+		KafkaPartitionsKey:        "10",
+		KafkaReplicationFactorKey: "3",
+	}, configMap.Data)
+
+	brokerObject := applied[BrokerKind].(*unstructured.Unstructured)
+	assert.Equal(t, KafkaBrokerClass, brokerObject.GetAnnotations()[BrokerClassAnnotation])
+	config, _, _ := unstructured.NestedStringMap(brokerObject.Object, "spec", "config")
+	assert.Equal(t, map[string]string{
+		"apiVersion": "v1", "kind": "ConfigMap", "name": configMap.Name, "namespace": workflowNamespace,
+	}, config)
+}
+
+func TestHandleBrokerCRWithoutStatus(t *testing.T) {
+	err := HandleBrokerCR(context.TODO(), newBrokerClient(map[string]client.Object{}, nil),
+		orchestratorv1alpha3.Broker{Name: "orchestrator-broker", Create: true}, workflowNamespace, "orchestrator")
+	assert.True(t, IsBrokerNotReady(err))
+	assert.Equal(t, "broker sonataflow-infra/orchestrator-broker is not ready yet", err.Error())
+}
+
+func newTestBroker(name, instance, brokerClass string) *unstructured.Unstructured {
+	brokerObject := newBroker()
+	brokerObject.SetName(name)
+	brokerObject.SetNamespace(workflowNamespace)
+	brokerObject.SetLabels(kube.GetInstanceLabels(instance))
+	brokerObject.SetAnnotations(map[string]string{BrokerClassAnnotation: brokerClass})
+	return brokerObject
+}
+
+func TestHandleBrokerCRClassChanged(t *testing.T) {
+	ctx := context.TODO()
+	applied := map[string]client.Object{}
+	fakeClient := newBrokerClient(applied, nil, newTestBroker("orchestrator-broker", "orchestrator", InMemoryBrokerClass))
+
+	broker := orchestratorv1alpha3.Broker{
+		Name:   "orchestrator-broker",
+		Create: true,
+		Type:   orchestratorv1alpha3.BrokerTypeKafka,
+		Kafka:  orchestratorv1alpha3.KafkaBroker{BootstrapServers: "my-cluster-kafka-bootstrap.kafka:9092"},
+	}
+	err := HandleBrokerCR(ctx, fakeClient, broker, workflowNamespace, "orchestrator")
+	assert.True(t, IsBrokerNotReady(err))
+	assert.Equal(t, "broker sonataflow-infra/orchestrator-broker is not ready: BrokerClassChanged "+
+		"the broker is created again with the Kafka class", err.Error())
+	assert.Empty(t, applied)
+	assert.True(t, apierrors.IsNotFound(fakeClient.Get(ctx,
+		types.NamespacedName{Name: "orchestrator-broker", Namespace: workflowNamespace}, newBroker())))
+
+	// the Broker is created again with the new class once deleted
+	err = HandleBrokerCR(ctx, fakeClient, broker, workflowNamespace, "orchestrator")
+	assert.True(t, IsBrokerNotReady(err))
+	assert.Equal(t, KafkaBrokerClass, applied[BrokerKind].GetAnnotations()[BrokerClassAnnotation])
+}
+
+func TestDeleteStaleBrokers(t *testing.T) {
+	ctx := context.TODO()
+	newConfigMap := func(name, instance string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: workflowNamespace, Labels: kube.GetInstanceLabels(instance),
+		}}
+	}
+	fakeClient := newBrokerClient(map[string]client.Object{}, nil,
+		newTestBroker("kafka-broker", "orchestrator", KafkaBrokerClass),
+		newTestBroker("previous-broker", "orchestrator", InMemoryBrokerClass),
+		newTestBroker("other-broker", "other", InMemoryBrokerClass),
+		newConfigMap("kafka-broker"+KafkaBrokerConfigMapSuffix, "orchestrator"),
+		newConfigMap("previous-broker"+KafkaBrokerConfigMapSuffix, "orchestrator"),
+		newConfigMap("other-broker"+KafkaBrokerConfigMapSuffix, "other"),
+		newConfigMap("orchestrator-config", "orchestrator"),
+	)
+	brokerExists := func(name string) bool {
+		return fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: workflowNamespace}, newBroker()) == nil
+	}
+	configMapExists := func(name string) bool {
+		return fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: workflowNamespace}, &corev1.ConfigMap{}) == nil
+	}
+
+	broker := orchestratorv1alpha3.Broker{Name: "kafka-broker", Create: true, Type: orchestratorv1alpha3.BrokerTypeKafka}
+	assert.NoError(t, DeleteStaleBrokers(ctx, fakeClient, broker, workflowNamespace, "orchestrator"))
+	assert.True(t, brokerExists("kafka-broker"))
+	assert.True(t, configMapExists("kafka-broker"+KafkaBrokerConfigMapSuffix))
+	assert.False(t, brokerExists("previous-broker"))
+	assert.False(t, configMapExists("previous-broker"+KafkaBrokerConfigMapSuffix))
+
+	// the Kafka ConfigMap is not desired for an in-memory broker
+	broker.Type = orchestratorv1alpha3.BrokerTypeInMemory
+	assert.NoError(t, DeleteStaleBrokers(ctx, fakeClient, broker, workflowNamespace, "orchestrator"))
+	assert.True(t, brokerExists("kafka-broker"))
+	assert.False(t, configMapExists("kafka-broker"+KafkaBrokerConfigMapSuffix))
+
+	broker.Create = false
+	assert.NoError(t, DeleteStaleBrokers(ctx, fakeClient, broker, workflowNamespace, "orchestrator"))
+	assert.False(t, brokerExists("kafka-broker"))
+
+	// the resources of other Orchestrators and the other ConfigMaps are kept
+	assert.True(t, brokerExists("other-broker"))
+	assert.True(t, configMapExists("other-broker"+KafkaBrokerConfigMapSuffix))
+	assert.True(t, configMapExists("orchestrator-config"))
+}
@@ -18,14 +18,17 @@ package knative
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"reflect"
 
 	olmclientset "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
 	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/metrics"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
-	Knative "knative.dev/operator/pkg/apis/operator/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -45,7 +48,11 @@ const (
 	KnativeSubscriptionStartingCSV = "serverless-operator.v1.36.0"
 )
 
-func HandleKNativeOperatorInstallation(ctx context.Context, client client.Client, olmClientSet olmclientset.Interface) error {
+// HandleKNativeOperatorInstallation performs operator installation for the Serverless operand
+// and returns the InstallPlan of the operator left waiting for approval, if any
+func HandleKNativeOperatorInstallation(
+	ctx context.Context, client client.Client, olmClientSet olmclientset.Interface,
+	subscriptionConfig orchestratorv1alpha3.Subscription, approvedCSV string) (*orchestratorv1alpha3.PendingInstallPlan, error) {
 	KnativeLogger := log.FromContext(ctx)
 
 	if _, err := kube.CheckNamespaceExist(ctx, client, KnativeOperatorNamespace); err != nil {
@@ -53,7 +60,7 @@ func HandleKNativeOperatorInstallation(ctx context.Context, client client.Client
 			KnativeLogger.Info("Creating namespace", "NS", KnativeOperatorNamespace)
 			if err := kube.CreateNamespace(ctx, client, KnativeOperatorNamespace); err != nil {
 				KnativeLogger.Error(err, "Error occurred when creating namespace", "NS", KnativeOperatorNamespace)
-				return err
+				return nil, err
 			}
 		}
 	}
@@ -62,20 +69,21 @@ func HandleKNativeOperatorInstallation(ctx context.Context, client client.Client
 		KnativeSubscriptionName,
 		KnativeOperatorNamespace,
 		KnativeSubscriptionChannel,
-		KnativeSubscriptionStartingCSV)
+		KnativeSubscriptionStartingCSV,
+		subscriptionConfig)
 
 	// check if subscription exists
 	subscriptionExists, existingSubscription, err := kube.CheckSubscriptionExists(ctx, olmClientSet, serverlessSubscription)
 	if err != nil {
 		KnativeLogger.Error(err, "Error occurred when checking subscription exists", "SubscriptionName", KnativeSubscriptionName)
-		return err
+		return nil, err
 	}
 	if !subscriptionExists {
 		if err := kube.InstallSubscriptionAndOperatorGroup(
 			ctx, client, olmClientSet,
 			KnativeOperatorGroupName, serverlessSubscription); err != nil {
 			KnativeLogger.Error(err, "Error occurred when installing operator", "SubscriptionName", KnativeSubscriptionName)
-			return err
+			return nil, err
 		}
 		KnativeLogger.Info("Operator successfully installed", "SubscriptionName", KnativeSubscriptionName)
 	} else {
@@ -85,25 +93,23 @@ func HandleKNativeOperatorInstallation(ctx context.Context, client client.Client
 			existingSubscription.Spec = serverlessSubscription.Spec
 			if err := client.Update(ctx, existingSubscription); err != nil {
 				KnativeLogger.Error(err, "Error occurred when updating subscription spec", "SubscriptionName", KnativeSubscriptionName)
-				return err
+				return nil, err
 			}
 			KnativeLogger.Info("Successfully updated updating subscription spec", "SubscriptionName", KnativeSubscriptionName)
+			metrics.RecordDriftCorrection("Subscription")
 		}
 	}
 
 	// approve install plan
-	if existingSubscription.Status.InstallPlanRef != nil && existingSubscription.Status.CurrentCSV == KnativeSubscriptionStartingCSV {
-		installPlanName := existingSubscription.Status.InstallPlanRef.Name
-		if err := kube.ApproveInstallPlan(client, ctx, installPlanName, existingSubscription.Namespace); err != nil {
-			KnativeLogger.Error(err, "Error occurred while approving install plan for subscription", "SubscriptionName", installPlanName)
-			return err
-		}
+	pendingInstallPlan, err := kube.HandleInstallPlanApproval(ctx, client, existingSubscription, subscriptionConfig, approvedCSV)
+	if err != nil {
+		KnativeLogger.Error(err, "Error occurred while approving install plan for subscription", "SubscriptionName", KnativeSubscriptionName)
+		return nil, err
 	}
-
-	return nil
+	return pendingInstallPlan, nil
 }
 
-func HandleKnativeCR(ctx context.Context, client client.Client) error {
+func HandleKnativeCR(ctx context.Context, client client.Client, knativeConfig orchestratorv1alpha3.Knative) error {
 	KnativeLogger := log.FromContext(ctx)
 	KnativeLogger.Info("Handling Serverless Custom Resources...")
 
@@ -117,7 +123,7 @@ func HandleKnativeCR(ctx context.Context, client client.Client) error {
 		return err
 	}
 	// CRD exists; check and handle Knative eventing CR
-	if err := HandleKnativeEventingCR(ctx, client); err != nil {
+	if err := HandleKnativeEventingCR(ctx, client, knativeConfig.Eventing); err != nil {
 		KnativeLogger.Error(err, "Error occurred when creating Knative EventingCR", "CR-Name", KnativeEventingNamespacedName)
 		return err
 	}
@@ -132,14 +138,15 @@ func HandleKnativeCR(ctx context.Context, client client.Client) error {
 		return err
 	}
 	// CRD exist; check and handle Knative serving CR
-	if err := HandleKnativeServingCR(ctx, client); err != nil {
+	if err := HandleKnativeServingCR(ctx, client, knativeConfig.Serving); err != nil {
 		KnativeLogger.Error(err, "Error occurred when creating Knative ServingCR", "CR-Name", KnativeServingNamespacedName)
 		return err
 	}
 	return nil
 }
 
-func HandleKnativeEventingCR(ctx context.Context, client client.Client) error {
+// HandleKnativeEventingCR applies the KnativeEventing CR with the spec configured in the Orchestrator
+func HandleKnativeEventingCR(ctx context.Context, client client.Client, spec *apiextensionsv1.JSON) error {
 	logger := log.FromContext(ctx)
 	logger.Info("Handling K-Native Eventing CR")
 
@@ -152,39 +159,21 @@ func HandleKnativeEventingCR(ctx context.Context, client client.Client) error {
 		}
 	}
 
-	desiredKnEventingCR := &Knative.KnativeEventing{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: KnativeAPIVersion,
-			Kind:       KnativeEventingKind,
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      KnativeEventingNamespacedName,
-			Namespace: KnativeEventingNamespacedName,
-			Labels:    kube.GetOrchestratorLabel(),
-		},
-		Spec: Knative.KnativeEventingSpec{},
-	}
-	currentKnEventingCR := &Knative.KnativeEventing{}
-
-	// check CR exists
-	err := client.Get(ctx, types.NamespacedName{Name: KnativeEventingNamespacedName, Namespace: KnativeEventingNamespacedName}, currentKnEventingCR)
-
+	desiredKnEventingCR, err := getKnativeObject(KnativeEventingKind, KnativeEventingNamespacedName, spec)
 	if err != nil {
-		// CR does not exist. Create CR
-		if apierrors.IsNotFound(err) {
-			if err = client.Create(ctx, desiredKnEventingCR); err != nil {
-				logger.Error(err, "Error occurred when creating CR resource", "CR-Name", desiredKnEventingCR.Name)
-			}
-			logger.Info("Successfully created Knative Eventing resource", "CR-Name", desiredKnEventingCR.Name)
-			return nil
-		}
-		logger.Error(err, "Error occurred when checking CR resource exist", "CR-Name", desiredKnEventingCR.Name)
+		logger.Error(err, "Error occurred when parsing the Knative Eventing spec", "CR-Name", KnativeEventingNamespacedName)
 		return err
 	}
+	if err := kube.ApplyObject(ctx, client, desiredKnEventingCR); err != nil {
+		logger.Error(err, "Error occurred when applying CR resource", "CR-Name", KnativeEventingNamespacedName)
+		return err
+	}
+	logger.Info("Successfully applied Knative Eventing resource", "CR-Name", KnativeEventingNamespacedName)
 	return nil
 }
 
-func HandleKnativeServingCR(ctx context.Context, client client.Client) error {
+// HandleKnativeServingCR applies the KnativeServing CR with the spec configured in the Orchestrator
+func HandleKnativeServingCR(ctx context.Context, client client.Client, spec *apiextensionsv1.JSON) error {
 	logger := log.FromContext(ctx)
 	logger.Info("Handling K-Native Serving CR")
 
@@ -192,60 +181,60 @@ func HandleKnativeServingCR(ctx context.Context, client client.Client) error {
 	namespaceExist, _ := kube.CheckNamespaceExist(ctx, client, KnativeServingNamespacedName)
 	if !namespaceExist {
 		if err := kube.CreateNamespace(ctx, client, KnativeServingNamespacedName); err != nil {
-			logger.Error(err, "Error occurred when creating namespace", "NS", KnativeEventingNamespacedName)
+			logger.Error(err, "Error occurred when creating namespace", "NS", KnativeServingNamespacedName)
 			return err
 		}
 	}
 
-	desiredKnServingCR := &Knative.KnativeServing{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: KnativeAPIVersion,
-			Kind:       KnativeServingKind,
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      KnativeServingNamespacedName,
-			Namespace: KnativeServingNamespacedName,
-			Labels:    kube.GetOrchestratorLabel(),
-		},
-		Spec: Knative.KnativeServingSpec{},
-	}
-	currentKnServingCR := &Knative.KnativeServing{}
-
-	// check CR exists
-	err := client.Get(ctx, types.NamespacedName{Name: KnativeServingNamespacedName, Namespace: KnativeServingNamespacedName}, currentKnServingCR)
-
+	desiredKnServingCR, err := getKnativeObject(KnativeServingKind, KnativeServingNamespacedName, spec)
 	if err != nil {
-		// CR does not exist. Create CR
-		if apierrors.IsNotFound(err) {
-			if err = client.Create(ctx, desiredKnServingCR); err != nil {
-				logger.Error(err, "Error occurred when creating CR resource", "CR-Name", desiredKnServingCR.Name)
-			}
-			logger.Info("Successfully created Knative Eventing resource", "CR-Name", desiredKnServingCR.Name)
-			return nil
-		}
-		logger.Error(err, "Error occurred when checking CR resource exist", "CR-Name", desiredKnServingCR.Name)
+		logger.Error(err, "Error occurred when parsing the Knative Serving spec", "CR-Name", KnativeServingNamespacedName)
 		return err
 	}
+	if err := kube.ApplyObject(ctx, client, desiredKnServingCR); err != nil {
+		logger.Error(err, "Error occurred when applying CR resource", "CR-Name", KnativeServingNamespacedName)
+		return err
+	}
+	logger.Info("Successfully applied Knative Serving resource", "CR-Name", KnativeServingNamespacedName)
 	return nil
 }
 
-func HandleKnativeCleanUp(ctx context.Context, client client.Client) error {
-	logger := log.FromContext(ctx)
-	// remove all namespace
-	if err := kube.CleanUpNamespace(ctx, KnativeEventingNamespacedName, client); err != nil {
-		logger.Error(err, "Error occurred when deleting namespace", "NS", KnativeEventingNamespacedName)
-		return err
+// getKnativeObject returns the Knative CR to apply with the given spec. The CR is unstructured so that only the
+// fields set in the spec are owned by the operator: the fields removed from the spec are removed from the CR and
+// the fields set by others are kept.
+func getKnativeObject(kind, name string, spec *apiextensionsv1.JSON) (*unstructured.Unstructured, error) {
+	knativeObject := &unstructured.Unstructured{}
+	knativeObject.SetAPIVersion(KnativeAPIVersion)
+	knativeObject.SetKind(kind)
+	knativeObject.SetName(name)
+	knativeObject.SetNamespace(name)
+	knativeObject.SetLabels(kube.GetOrchestratorLabel())
+
+	if spec == nil {
+		return knativeObject, nil
 	}
-	if err := kube.CleanUpNamespace(ctx, KnativeServingNamespacedName, client); err != nil {
-		logger.Error(err, "Error occurred when deleting namespace", "NS", KnativeServingNamespacedName)
-		return err
+	specFields := map[string]any{}
+	if err := json.Unmarshal(spec.Raw, &specFields); err != nil {
+		return nil, fmt.Errorf("failed to parse the %s spec: %w", kind, err)
 	}
-
-	// remove operator namespace
-	if err := kube.CleanUpNamespace(ctx, KnativeOperatorNamespace, client); err != nil {
-		logger.Error(err, "Error occurred when deleting namespace", "NS", KnativeOperatorNamespace)
-		return err
+	if len(specFields) > 0 {
+		knativeObject.Object["spec"] = specFields
 	}
+	return knativeObject, nil
+}
 
-	return nil
+// ListKnativeCleanUpObjects returns the Knative namespaces owned by owner, which are removed according to the
+// serverless deletion policy. They are shared by all the Orchestrators, so they are only owned by the last one.
+func ListKnativeCleanUpObjects(ctx context.Context, k8Client client.Client, owner kube.Owner) ([]client.Object, error) {
+	var objects []client.Object
+	for _, namespaceName := range []string{KnativeEventingNamespacedName, KnativeServingNamespacedName, KnativeOperatorNamespace} {
+		namespace, err := kube.GetOwnedNamespace(ctx, k8Client, namespaceName, owner)
+		if err != nil {
+			return nil, err
+		}
+		if namespace != nil {
+			objects = append(objects, namespace)
+		}
+	}
+	return objects, nil
 }
@@ -2,23 +2,27 @@ package knative
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	olmclientsetfake "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/fake"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
 	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrros "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	Knative "knative.dev/operator/pkg/apis/operator/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 const (
@@ -32,6 +36,42 @@ const (
 
 var objects []client.Object
 
+// applyInterceptor emulates the server-side apply of the Knative CRs, which is not supported by the fake client,
+// by replacing the labels and the spec of the CRs with the applied ones.
+func applyInterceptor(scheme *runtime.Scheme) interceptor.Funcs {
+	return interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			patchOptions := &client.PatchOptions{}
+			patchOptions.ApplyOptions(opts)
+			if patchOptions.FieldManager != kube.FieldManager {
+				return fmt.Errorf("unexpected field manager %s", patchOptions.FieldManager)
+			}
+
+			applied, err := scheme.New(obj.GetObjectKind().GroupVersionKind())
+			if err != nil {
+				return err
+			}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.(*unstructured.Unstructured).Object, applied); err != nil {
+				return err
+			}
+			appliedObject := applied.(client.Object)
+
+			existing := appliedObject.DeepCopyObject().(client.Object)
+			if err := c.Get(ctx, client.ObjectKeyFromObject(appliedObject), existing); err != nil {
+				if apierrros.IsNotFound(err) {
+					return c.Create(ctx, appliedObject)
+				}
+				return err
+			}
+			appliedObject.SetResourceVersion(existing.GetResourceVersion())
+			return c.Update(ctx, appliedObject)
+		},
+	}
+}
+
 func TestHandleKNativeOperatorInstallation(t *testing.T) {
 	ctx := context.TODO()
 	// Create a fake client scheme
@@ -66,7 +106,8 @@ func TestHandleKNativeOperatorInstallation(t *testing.T) {
 				KnativeSubscriptionName,
 				KnativeOperatorNamespace,
 				testChannel,
-				KnativeSubscriptionStartingCSV)
+				KnativeSubscriptionStartingCSV,
+				orchestratorv1alpha3.Subscription{})
 
 			desiredSubscription.Status = v1alpha1.SubscriptionStatus{
 				InstallPlanRef: &corev1.ObjectReference{Name: "test-plan"},
@@ -87,7 +128,7 @@ func TestHandleKNativeOperatorInstallation(t *testing.T) {
 				desiredSubscription)
 			assert.Equal(t, nil, err)
 
-			err = HandleKNativeOperatorInstallation(ctx, fakeClient, fakeOLMClientSet)
+			_, err = HandleKNativeOperatorInstallation(ctx, fakeClient, fakeOLMClientSet, orchestratorv1alpha3.Subscription{}, "")
 			if tc.subExists {
 				assert.NoError(t, err)
 			} else {
@@ -159,7 +200,7 @@ func TestHandleKnativeCR(t *testing.T) {
 				},
 				Spec: apiextensionsv1.CustomResourceDefinitionSpec{},
 			}
-			builder := fake.NewClientBuilder().WithScheme(scheme)
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(applyInterceptor(scheme))
 			if tc.eventingCRDExists {
 				builder.WithObjects(eventingCRD)
 			}
@@ -168,7 +209,7 @@ func TestHandleKnativeCR(t *testing.T) {
 			}
 			fakeClient := builder.Build()
 
-			err := HandleKnativeCR(ctx, fakeClient)
+			err := HandleKnativeCR(ctx, fakeClient, orchestratorv1alpha3.Knative{})
 			if err != nil {
 				assert.Equal(t, tc.expectedErrorMessage, err.Error())
 			}
@@ -220,8 +261,8 @@ func TestHandleKnativeEventingCR(t *testing.T) {
 
 		if !tc.eventingExists {
 			t.Run(tc.name, func(t *testing.T) {
-				fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects().Build()
-				err := HandleKnativeEventingCR(ctx, fakeClient)
+				fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(applyInterceptor(scheme)).Build()
+				err := HandleKnativeEventingCR(ctx, fakeClient, nil)
 				assert.Equal(t, tc.expectedError, err)
 				err = fakeClient.Get(ctx, types.NamespacedName{Name: KnativeEventingNamespacedName, Namespace: KnativeEventingNamespacedName}, existingEventing)
 				assert.NoError(t, err)
@@ -230,9 +271,9 @@ func TestHandleKnativeEventingCR(t *testing.T) {
 		} else {
 
 			t.Run(tc.name, func(t *testing.T) {
-				fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.eventingObject).Build()
+				fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.eventingObject).WithInterceptorFuncs(applyInterceptor(scheme)).Build()
 
-				err := HandleKnativeEventingCR(ctx, fakeClient)
+				err := HandleKnativeEventingCR(ctx, fakeClient, nil)
 				assert.Equal(t, tc.expectedError, err)
 				err = fakeClient.Get(ctx, types.NamespacedName{Name: testEventingName, Namespace: KnativeEventingNamespacedName}, existingEventing)
 				assert.NoError(t, err)
@@ -286,8 +327,8 @@ func TestHandleKnativeServingCR(t *testing.T) {
 		if !tc.servingExists {
 			t.Run(tc.name, func(t *testing.T) {
 
-				fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects().Build()
-				err := HandleKnativeServingCR(ctx, fakeClient)
+				fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(applyInterceptor(scheme)).Build()
+				err := HandleKnativeServingCR(ctx, fakeClient, nil)
 				assert.Equal(t, tc.expectedError, err)
 
 				err = fakeClient.Get(ctx, types.NamespacedName{Name: KnativeServingNamespacedName, Namespace: KnativeServingNamespacedName}, existingServing)
@@ -296,8 +337,8 @@ func TestHandleKnativeServingCR(t *testing.T) {
 
 		} else {
 			t.Run(tc.name, func(t *testing.T) {
-				fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.servingObject).Build()
-				err := HandleKnativeServingCR(ctx, fakeClient)
+				fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.servingObject).WithInterceptorFuncs(applyInterceptor(scheme)).Build()
+				err := HandleKnativeServingCR(ctx, fakeClient, nil)
 				assert.Equal(t, tc.expectedError, err)
 
 				err = fakeClient.Get(ctx, types.NamespacedName{Name: testServingName, Namespace: KnativeServingNamespacedName}, existingServing)
@@ -309,64 +350,73 @@ func TestHandleKnativeServingCR(t *testing.T) {
 
 }
 
-func TestHandleKnativeCleanUp(t *testing.T) {
+func TestHandleKnativeCRAppliesSpecs(t *testing.T) {
 	ctx := context.TODO()
-	// Create a fake client scheme
 	scheme := runtime.NewScheme()
 	utilruntime.Must(Knative.AddToScheme(scheme))
 	utilruntime.Must(corev1.AddToScheme(scheme))
-	utilruntime.Must(metav1.AddMetaToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 
-	testCases := []struct {
-		name       string
-		namespaces []*corev1.Namespace
-	}{
-		{
-			name: "Validate namespaces were deleted",
-			namespaces: []*corev1.Namespace{
-				{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      KnativeEventingNamespacedName,
-						Namespace: KnativeEventingNamespacedName,
-					},
-					Spec: corev1.NamespaceSpec{},
-				},
-				{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      KnativeOperatorNamespace,
-						Namespace: KnativeOperatorNamespace,
-					},
-					Spec: corev1.NamespaceSpec{},
-				}, {
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      KnativeServingNamespacedName,
-						Namespace: KnativeServingNamespacedName,
-					},
-					Spec: corev1.NamespaceSpec{},
-				},
-			},
-		},
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: KnativeEventingCRDName}},
+		&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: KnativeServingCRDName}},
+	).WithInterceptorFuncs(applyInterceptor(scheme)).Build()
+
+	knativeConfig := orchestratorv1alpha3.Knative{
+		Serving: &apiextensionsv1.JSON{Raw: []byte(`{"high-availability":{"replicas":2},"config":{"autoscaler":{"min-scale":"1"}},` +
+			`"ingress":{"kourier":{"enabled":true}}}`)},
+		Eventing: &apiextensionsv1.JSON{Raw: []byte(`{"defaultBrokerClass":"Kafka","workloads":[{"name":"eventing-controller","replicas":3}]}`)},
 	}
-	tc := testCases[0]
-	t.Run(tc.name, func(t *testing.T) {
-		for _, ns := range tc.namespaces {
-			objects = append(objects, ns)
-		}
-		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	assert.NoError(t, HandleKnativeCR(ctx, fakeClient, knativeConfig))
+
+	serving := &Knative.KnativeServing{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: KnativeServingNamespacedName, Namespace: KnativeServingNamespacedName}, serving))
+	assert.Equal(t, kube.GetOrchestratorLabel(), serving.Labels)
+	assert.Equal(t, int32(2), *serving.Spec.HighAvailability.Replicas)
+	assert.Equal(t, "1", serving.Spec.Config["autoscaler"]["min-scale"])
+	assert.True(t, serving.Spec.Ingress.Kourier.Enabled)
+
+	eventing := &Knative.KnativeEventing{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: KnativeEventingNamespacedName, Namespace: KnativeEventingNamespacedName}, eventing))
+	assert.Equal(t, "Kafka", eventing.Spec.DefaultBrokerClass)
+	assert.Equal(t, int32(3), *eventing.Spec.Workloads[0].Replicas)
+
+	// a change of the specs is applied to the CRs
+	knativeConfig.Serving = nil
+	knativeConfig.Eventing = &apiextensionsv1.JSON{Raw: []byte(`{"defaultBrokerClass":"MTChannelBasedBroker"}`)}
+	assert.NoError(t, HandleKnativeCR(ctx, fakeClient, knativeConfig))
+
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: KnativeServingNamespacedName, Namespace: KnativeServingNamespacedName}, serving))
+	assert.Equal(t, Knative.KnativeServingSpec{}, serving.Spec)
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: KnativeEventingNamespacedName, Namespace: KnativeEventingNamespacedName}, eventing))
+	assert.Equal(t, "MTChannelBasedBroker", eventing.Spec.DefaultBrokerClass)
+	assert.Empty(t, eventing.Spec.Workloads)
+}
 
-		for _, ns := range tc.namespaces {
-			namespace := &corev1.Namespace{}
-			err := fakeClient.Get(ctx, types.NamespacedName{Name: ns.Name}, namespace)
-			assert.True(t, apierrros.IsNotFound(err))
-		}
+func TestListKnativeCleanUpObjects(t *testing.T) {
+	ctx := context.TODO()
+	// Create a fake client scheme
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
 
-		err := HandleKnativeCleanUp(ctx, fakeClient)
-		assert.NoError(t, err)
+	var namespaces []client.Object
+	for _, name := range []string{KnativeEventingNamespacedName, KnativeServingNamespacedName, KnativeOperatorNamespace} {
+		namespaces = append(namespaces, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: kube.GetOrchestratorLabel()}})
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespaces...).Build()
 
-		for _, ns := range tc.namespaces {
-			namespace := &corev1.Namespace{}
-			err = fakeClient.Get(ctx, types.NamespacedName{Name: ns.Name}, namespace)
-			assert.True(t, apierrros.IsNotFound(err))
+	t.Run("Shared namespaces are kept while other Orchestrators exist", func(t *testing.T) {
+		objects, err := ListKnativeCleanUpObjects(ctx, fakeClient, kube.Owner{Instance: "orchestrator"})
+		assert.NoError(t, err)
+		assert.Empty(t, objects)
+	})
+	t.Run("Shared namespaces are removed with the last Orchestrator", func(t *testing.T) {
+		objects, err := ListKnativeCleanUpObjects(ctx, fakeClient, kube.Owner{Instance: "orchestrator", Last: true})
+		assert.NoError(t, err)
+		var names []string
+		for _, obj := range objects {
+			names = append(names, obj.GetName())
 		}
+		assert.Equal(t, []string{KnativeEventingNamespacedName, KnativeServingNamespacedName, KnativeOperatorNamespace}, names)
 	})
 }
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConfigHashAnnotation holds the hash of the configuration the operator last rendered into an object.
+	ConfigHashAnnotation = "rhdh.redhat.com/config-hash"
+	// OperatorVersionAnnotation holds the version of the operator that last rendered the configuration of an object.
+	OperatorVersionAnnotation = "rhdh.redhat.com/operator-version"
+)
+
+// ComputeConfigHash returns the hash of the JSON representation of config. The JSON is normalized first, so that
+// the hash of a configuration read back from the cluster matches the hash of the configuration that was rendered.
+func ComputeConfigHash(config any) (string, error) {
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	var normalized any
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return "", err
+	}
+	if raw, err = json.Marshal(normalized); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// IsModifiedOutsideOperator returns whether the configuration of obj, whose hash is liveHash, was changed since the
+// operator last rendered it. Objects without the ConfigHashAnnotation were created by previous versions of the
+// operator when they carry the orchestrator label, and by someone else otherwise.
+func IsModifiedOutsideOperator(obj metav1.Object, liveHash string) bool {
+	lastHash, found := obj.GetAnnotations()[ConfigHashAnnotation]
+	if !found {
+		return !CheckLabelExist(obj.GetLabels())
+	}
+	return lastHash != liveHash
+}
+
+// SetConfigHash records hash as the configuration the operator rendered into obj.
+func SetConfigHash(obj metav1.Object, hash string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ConfigHashAnnotation] = hash
+	obj.SetAnnotations(annotations)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestComputeConfigHash(t *testing.T) {
+	rendered, err := ComputeConfigHash(&apiextensionsv1.JSON{Raw: []byte(`{"name":"install-dynamic-plugins","env":[]}`)})
+	assert.NoError(t, err)
+
+	// the API server returns the keys of unstructured fields sorted
+	live, err := ComputeConfigHash(&apiextensionsv1.JSON{Raw: []byte(`{"env":[],"name":"install-dynamic-plugins"}`)})
+	assert.NoError(t, err)
+	assert.Equal(t, rendered, live)
+
+	modified, err := ComputeConfigHash(&apiextensionsv1.JSON{Raw: []byte(`{"env":[],"name":"custom-init"}`)})
+	assert.NoError(t, err)
+	assert.NotEqual(t, rendered, modified)
+}
+
+func TestIsModifiedOutsideOperator(t *testing.T) {
+	testCases := []struct {
+		name             string
+		objectMeta       metav1.ObjectMeta
+		expectedModified bool
+	}{
+		{
+			name:             "Object still has the rendered configuration",
+			objectMeta:       metav1.ObjectMeta{Annotations: map[string]string{ConfigHashAnnotation: "live"}},
+			expectedModified: false,
+		},
+		{
+			name:             "Object configuration was edited",
+			objectMeta:       metav1.ObjectMeta{Annotations: map[string]string{ConfigHashAnnotation: "rendered"}},
+			expectedModified: true,
+		},
+		{
+			name:             "Object created by a previous version of the operator",
+			objectMeta:       metav1.ObjectMeta{Labels: GetOrchestratorLabel()},
+			expectedModified: false,
+		},
+		{
+			name:             "Object not created by the operator",
+			objectMeta:       metav1.ObjectMeta{},
+			expectedModified: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedModified, IsModifiedOutsideOperator(&tc.objectMeta, "live"))
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// GetOwnedNamespace returns the namespace when it exists and is owned by owner, nil otherwise.
+func GetOwnedNamespace(ctx context.Context, k8Client client.Client, namespaceName string, owner Owner) (*corev1.Namespace, error) {
+	namespace := &corev1.Namespace{}
+	if err := k8Client.Get(ctx, types.NamespacedName{Name: namespaceName}, namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !owner.Owns(namespace) {
+		return nil, nil
+	}
+	return namespace, nil
+}
+
+// ListOwnedCustomResources returns the orchestrator labelled CRs owned by owner in a given namespace, or cluster
+// wide for cluster-scoped CRs. No CR is returned when their CRD is not installed.
+func ListOwnedCustomResources[T client.ObjectList, I client.Object](ctx context.Context,
+	k8Client client.Client,
+	objList T, getItems func(T) []I,
+	namespace string, owner Owner) ([]client.Object, error) {
+
+	listOptions := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels(GetOrchestratorLabel())}
+
+	if err := k8Client.List(ctx, objList, listOptions...); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var objects []client.Object
+	for _, item := range getItems(objList) {
+		if owner.Owns(item) {
+			objects = append(objects, item)
+		}
+	}
+	return objects, nil
+}
+
+// ApplyDeletionPolicy deletes or orphans objects in order according to policy. Orphaned objects lose the labels
+// of the operator, so that no Orchestrator manages them anymore. Retained objects are left untouched.
+func ApplyDeletionPolicy(ctx context.Context, k8Client client.Client, policy orchestratorv1alpha3.DeletionPolicy,
+	objects []client.Object) error {
+	logger := log.FromContext(ctx)
+
+	var errorList []error
+	for _, obj := range objects {
+		description := DescribeObject(k8Client, obj)
+		switch policy {
+		case orchestratorv1alpha3.DeletionPolicyDelete:
+			if err := k8Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+				logger.Error(err, "Error occurred when deleting resource", "Resource", description)
+				errorList = append(errorList, fmt.Errorf("failed to delete %s: %w", description, err))
+				continue
+			}
+			logger.Info("Successfully deleted resource", "Resource", description)
+		case orchestratorv1alpha3.DeletionPolicyOrphan:
+			patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+			labels := obj.GetLabels()
+			delete(labels, CreatedByLabelKey)
+			delete(labels, InstanceLabelKey)
+			obj.SetLabels(labels)
+			if err := k8Client.Patch(ctx, obj, patch); err != nil && !apierrors.IsNotFound(err) {
+				logger.Error(err, "Error occurred when orphaning resource", "Resource", description)
+				errorList = append(errorList, fmt.Errorf("failed to orphan %s: %w", description, err))
+				continue
+			}
+			logger.Info("Successfully orphaned resource", "Resource", description)
+		}
+	}
+	return errors.NewAggregate(errorList)
+}
+
+// DescribeObject returns the kind and the name of obj, i.e. Namespace knative-eventing or Backstage rhdh/backstage.
+func DescribeObject(k8Client client.Client, obj client.Object) string {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := apiutil.GVKForObject(obj, k8Client.Scheme()); err == nil {
+		kind = gvk.Kind
+	}
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", kind, obj.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", kind, obj.GetNamespace(), obj.GetName())
+}
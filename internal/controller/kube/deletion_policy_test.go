@@ -0,0 +1,136 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	sonataapi "github.com/apache/incubator-kie-tools/packages/sonataflow-operator/api/v1alpha08"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func getSonataFlowPlatforms(list *sonataapi.SonataFlowPlatformList) []*sonataapi.SonataFlowPlatform {
+	objs := make([]*sonataapi.SonataFlowPlatform, len(list.Items))
+	for i := range list.Items {
+		objs[i] = &list.Items[i]
+	}
+	return objs
+}
+
+func TestListOwnedCustomResources(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(sonataapi.AddToScheme(scheme))
+
+	owned := &sonataapi.SonataFlowPlatform{
+		ObjectMeta: metav1.ObjectMeta{Name: "sonataflow-platform", Namespace: orchestratorNamespace, Labels: GetInstanceLabels("orchestrator")},
+	}
+	other := &sonataapi.SonataFlowPlatform{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: orchestratorNamespace, Labels: GetInstanceLabels("other")},
+	}
+	unlabelled := &sonataapi.SonataFlowPlatform{
+		ObjectMeta: metav1.ObjectMeta{Name: "unlabelled", Namespace: orchestratorNamespace},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(owned, other, unlabelled).Build()
+
+	objects, err := ListOwnedCustomResources(ctx, fakeClient, &sonataapi.SonataFlowPlatformList{}, getSonataFlowPlatforms,
+		orchestratorNamespace, Owner{Instance: "orchestrator", Last: true})
+	assert.NoError(t, err)
+	assert.Len(t, objects, 1)
+	assert.Equal(t, owned.Name, objects[0].GetName())
+
+	// no CR is listed when the CRD is not installed
+	fakeClientWithoutCRD := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		List: func(_ context.Context, _ client.WithWatch, _ client.ObjectList, _ ...client.ListOption) error {
+			return &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "sonataflow.org", Kind: "SonataFlowPlatform"}}
+		},
+	}).Build()
+	objects, err = ListOwnedCustomResources(ctx, fakeClientWithoutCRD, &sonataapi.SonataFlowPlatformList{},
+		getSonataFlowPlatforms, orchestratorNamespace, Owner{Instance: "orchestrator", Last: true})
+	assert.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func TestApplyDeletionPolicy(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+
+	labels := map[string]string{"app": "workflows", CreatedByLabelKey: CreatedByLabelValue, InstanceLabelKey: "orchestrator"}
+	testCases := []struct {
+		name           string
+		policy         orchestratorv1alpha3.DeletionPolicy
+		expectedLabels map[string]string
+		expectDeleted  bool
+	}{
+		{
+			name:           "Retain keeps the resources",
+			policy:         orchestratorv1alpha3.DeletionPolicyRetain,
+			expectedLabels: labels,
+		},
+		{
+			name:          "Delete deletes the resources",
+			policy:        orchestratorv1alpha3.DeletionPolicyDelete,
+			expectDeleted: true,
+		},
+		{
+			name:           "Orphan removes the labels of the operator",
+			policy:         orchestratorv1alpha3.DeletionPolicyOrphan,
+			expectedLabels: map[string]string{"app": "workflows"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: orchestratorNamespace, Labels: maps.Clone(labels)}}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespace).Build()
+
+			assert.NoError(t, ApplyDeletionPolicy(ctx, fakeClient, tc.policy, []client.Object{namespace}))
+
+			live := &corev1.Namespace{}
+			err := fakeClient.Get(ctx, client.ObjectKeyFromObject(namespace), live)
+			if tc.expectDeleted {
+				assert.True(t, apierrors.IsNotFound(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedLabels, live.Labels)
+		})
+	}
+}
+
+func TestDescribeObject(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	assert.Equal(t, "Namespace rhdh", DescribeObject(fakeClient, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "rhdh"}}))
+	assert.Equal(t, "ConfigMap rhdh/app-config-rhdh",
+		DescribeObject(fakeClient, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-config-rhdh", Namespace: "rhdh"}}))
+}
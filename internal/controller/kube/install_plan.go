@@ -0,0 +1,100 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"slices"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// HandleInstallPlanApproval approves the current InstallPlan of the subscription when the approval policy of
+// subscriptionConfig allows it, or when it installs the approvedCSV. It returns the InstallPlan when it is left
+// waiting for approval, or nil when there is no InstallPlan to approve.
+func HandleInstallPlanApproval(
+	ctx context.Context, client client.Client,
+	subscription *v1alpha1.Subscription,
+	subscriptionConfig orchestratorv1alpha3.Subscription,
+	approvedCSV string) (*orchestratorv1alpha3.PendingInstallPlan, error) {
+
+	logger := log.FromContext(ctx)
+
+	if subscription.Status.InstallPlanRef == nil {
+		return nil, nil
+	}
+	installPlanName := subscription.Status.InstallPlanRef.Name
+	namespace := subscription.Namespace
+
+	installPlan := &v1alpha1.InstallPlan{}
+	if err := client.Get(ctx, types.NamespacedName{Name: installPlanName, Namespace: namespace}, installPlan); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the InstallPlan was already garbage collected by OLM
+			return nil, nil
+		}
+		logger.Error(err, "Error occurred when retrieving InstallPlan", "InstallPlan", installPlanName, "NS", namespace)
+		return nil, err
+	}
+	if installPlan.Spec.Approved {
+		return nil, nil
+	}
+
+	csv := subscription.Status.CurrentCSV
+	if !isInstallPlanApproved(subscription, subscriptionConfig, approvedCSV) {
+		logger.Info("InstallPlan is waiting for approval", "InstallPlan", installPlanName, "CSV", csv, "SubscriptionName", subscription.Name)
+		return &orchestratorv1alpha3.PendingInstallPlan{
+			Name:         installPlanName,
+			Namespace:    namespace,
+			Subscription: subscription.Name,
+			CSV:          csv,
+		}, nil
+	}
+
+	if err := ApproveInstallPlan(client, ctx, installPlanName, namespace); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// isInstallPlanApproved returns whether the CSV the subscription is installing may be approved.
+func isInstallPlanApproved(subscription *v1alpha1.Subscription, subscriptionConfig orchestratorv1alpha3.Subscription, approvedCSV string) bool {
+	csv := subscription.Status.CurrentCSV
+	if csv == "" {
+		return false
+	}
+	if csv == approvedCSV {
+		return true
+	}
+
+	switch subscriptionConfig.ApprovalPolicy {
+	case orchestratorv1alpha3.ApprovalPolicyAutomaticWithinChannel:
+		return true
+	case orchestratorv1alpha3.ApprovalPolicyManual:
+		return false
+	default:
+		startingCSV := ""
+		if subscription.Spec != nil {
+			startingCSV = subscription.Spec.StartingCSV
+		}
+		return csv == startingCSV || slices.Contains(subscriptionConfig.AllowedCSVs, csv)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHandleInstallPlanApproval(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	installPlanName := "install-abcde"
+	upgradeCSV := "starting-csv.v1"
+
+	testCases := []struct {
+		name               string
+		currentCSV         string
+		subscriptionConfig orchestratorv1alpha3.Subscription
+		approvedCSV        string
+		expectedApproved   bool
+	}{
+		{
+			name:             "Pinned policy approves the starting CSV",
+			currentCSV:       subscription.Spec.StartingCSV,
+			expectedApproved: true,
+		},
+		{
+			name:             "Pinned policy does not approve an upgrade",
+			currentCSV:       upgradeCSV,
+			expectedApproved: false,
+		},
+		{
+			name:       "Pinned policy approves an allow-listed CSV",
+			currentCSV: upgradeCSV,
+			subscriptionConfig: orchestratorv1alpha3.Subscription{
+				ApprovalPolicy: orchestratorv1alpha3.ApprovalPolicyPinned,
+				AllowedCSVs:    []string{upgradeCSV},
+			},
+			expectedApproved: true,
+		},
+		{
+			name:       "Automatic within channel policy approves an upgrade",
+			currentCSV: upgradeCSV,
+			subscriptionConfig: orchestratorv1alpha3.Subscription{
+				ApprovalPolicy: orchestratorv1alpha3.ApprovalPolicyAutomaticWithinChannel,
+			},
+			expectedApproved: true,
+		},
+		{
+			name:       "Manual policy does not approve the starting CSV",
+			currentCSV: subscription.Spec.StartingCSV,
+			subscriptionConfig: orchestratorv1alpha3.Subscription{
+				ApprovalPolicy: orchestratorv1alpha3.ApprovalPolicyManual,
+			},
+			expectedApproved: false,
+		},
+		{
+			name:       "Manual policy approves the annotated CSV",
+			currentCSV: upgradeCSV,
+			subscriptionConfig: orchestratorv1alpha3.Subscription{
+				ApprovalPolicy: orchestratorv1alpha3.ApprovalPolicyManual,
+			},
+			approvedCSV:      upgradeCSV,
+			expectedApproved: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			installPlan := &v1alpha1.InstallPlan{
+				ObjectMeta: metav1.ObjectMeta{Name: installPlanName, Namespace: orchestratorNamespace},
+				Spec: v1alpha1.InstallPlanSpec{
+					ClusterServiceVersionNames: []string{tc.currentCSV},
+					Approval:                   v1alpha1.ApprovalManual,
+				},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(installPlan).Build()
+
+			existingSubscription := subscription.DeepCopy()
+			existingSubscription.Status = v1alpha1.SubscriptionStatus{
+				CurrentCSV:     tc.currentCSV,
+				InstallPlanRef: &corev1.ObjectReference{Name: installPlanName, Namespace: orchestratorNamespace},
+			}
+
+			pendingInstallPlan, err := HandleInstallPlanApproval(ctx, fakeClient, existingSubscription, tc.subscriptionConfig, tc.approvedCSV)
+			assert.NoError(t, err)
+
+			updatedInstallPlan := &v1alpha1.InstallPlan{}
+			assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: installPlanName, Namespace: orchestratorNamespace}, updatedInstallPlan))
+			assert.Equal(t, tc.expectedApproved, updatedInstallPlan.Spec.Approved)
+
+			if tc.expectedApproved {
+				assert.Nil(t, pendingInstallPlan)
+				return
+			}
+			assert.Equal(t, &orchestratorv1alpha3.PendingInstallPlan{
+				Name:         installPlanName,
+				Namespace:    orchestratorNamespace,
+				Subscription: subscriptionName,
+				CSV:          tc.currentCSV,
+			}, pendingInstallPlan)
+		})
+	}
+}
+
+func TestHandleInstallPlanApprovalWithoutInstallPlan(t *testing.T) {
+	fakeClient := fake.NewClientBuilder().Build()
+
+	pendingInstallPlan, err := HandleInstallPlanApproval(context.TODO(), fakeClient, subscription, orchestratorv1alpha3.Subscription{}, "")
+	assert.NoError(t, err)
+	assert.Nil(t, pendingInstallPlan)
+}
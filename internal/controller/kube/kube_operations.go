@@ -23,12 +23,12 @@ import (
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	olmclientset "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -38,6 +38,11 @@ const (
 	CatalogSourceName      = "redhat-operators"
 	CreatedByLabelKey      = "rhdh.redhat.com/created-by"
 	CreatedByLabelValue    = "orchestrator"
+	// InstanceLabelKey identifies, by its UID, the Orchestrator an object was created for. Most objects are created
+	// outside the namespace of the Orchestrator, so they cannot reference it as their owner.
+	InstanceLabelKey = "orchestrator.rhdh.redhat.com/instance"
+	// FieldManager is the field manager the operator uses for server-side apply
+	FieldManager = "orchestrator-operator"
 )
 
 func CheckNamespaceExist(ctx context.Context, client client.Client, namespace string) (bool, error) {
@@ -60,10 +65,20 @@ func CheckNamespaceExist(ctx context.Context, client client.Client, namespace st
 }
 
 func CreateNamespace(ctx context.Context, client client.Client, namespace string) error {
+	return createNamespace(ctx, client, namespace, GetOrchestratorLabel())
+}
+
+// CreateInstanceNamespace creates the namespace for the instance Orchestrator, so that it is only cleaned up
+// with that Orchestrator.
+func CreateInstanceNamespace(ctx context.Context, client client.Client, namespace, instance string) error {
+	return createNamespace(ctx, client, namespace, GetInstanceLabels(instance))
+}
+
+func createNamespace(ctx context.Context, client client.Client, namespace string, labels map[string]string) error {
 	nsLogger := log.FromContext(ctx)
 	nsLogger.Info("Creating namespace", "Namespace", namespace)
 	// create new namespace
-	newNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace, Labels: GetOrchestratorLabel()}}
+	newNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace, Labels: labels}}
 	err := client.Create(ctx, newNamespace)
 	if err != nil {
 		nsLogger.Error(err, "Error occurred when creating namespace", "Namespace", namespace)
@@ -152,22 +167,27 @@ func CheckCSVExists(ctx context.Context, client client.Client, installedSubscrip
 
 }
 
-func getOperatorGroup(ctx context.Context, client client.Client,
+// getOperatorGroup creates the operatorGroupName OperatorGroup unless the namespace already has an OperatorGroup,
+// whatever its name, since OLM does not install operators in namespaces with several OperatorGroups.
+func getOperatorGroup(ctx context.Context, k8Client client.Client,
 	namespace, operatorGroupName string) error {
 	logger := log.FromContext(ctx)
 
 	// check if operator group exists
-	operatorGroup := &operatorsv1.OperatorGroup{}
-	err := client.Get(ctx, types.NamespacedName{Name: operatorGroupName, Namespace: namespace}, operatorGroup)
-	if err == nil {
-		logger.Info("Operator Group already exists", "Operator Group", operatorGroupName)
+	operatorGroups := &operatorsv1.OperatorGroupList{}
+	if err := k8Client.List(ctx, operatorGroups, client.InNamespace(namespace)); err != nil {
+		logger.Error(err, "Error occurred when listing OperatorGroups", "Namespace", namespace)
+		return err
+	}
+	if len(operatorGroups.Items) > 0 {
+		logger.Info("Operator Group already exists", "Operator Group", operatorGroups.Items[0].Name)
 		return nil
 	}
 	// create operator group
 	sfog := &operatorsv1.OperatorGroup{
 		ObjectMeta: metav1.ObjectMeta{Name: operatorGroupName, Namespace: namespace},
 	}
-	if err = client.Create(ctx, sfog); err != nil {
+	if err := k8Client.Create(ctx, sfog); err != nil {
 		logger.Error(err, "Error occurred when creating OperatorGroup resource", "Namespace", namespace)
 		return err
 	}
@@ -175,12 +195,15 @@ func getOperatorGroup(ctx context.Context, client client.Client,
 	return nil
 }
 
-func CreateSubscriptionObject(subscriptionName, namespace, channel, startingCSV string) *v1alpha1.Subscription {
+// CreateSubscriptionObject builds the Subscription of the subscriptionName operator package.
+// The channel and startingCSV are the defaults supported by the Orchestrator operator; they, as well as
+// the catalog source and the install plan approval, are overridden by the fields set in subscriptionConfig.
+func CreateSubscriptionObject(
+	subscriptionName, namespace, channel, startingCSV string,
+	subscriptionConfig orchestratorv1alpha3.Subscription) *v1alpha1.Subscription {
 	logger := log.Log.WithName("subscriptionObject")
 	logger.Info("Creating subscription object")
 
-	SourceName := CatalogSourceName
-
 	subscriptionObject := &v1alpha1.Subscription{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: namespace,
@@ -188,17 +211,24 @@ func CreateSubscriptionObject(subscriptionName, namespace, channel, startingCSV
 			Labels:    GetOrchestratorLabel(),
 		},
 		Spec: &v1alpha1.SubscriptionSpec{
-			Channel:                channel,
-			InstallPlanApproval:    v1alpha1.ApprovalManual,
-			CatalogSource:          SourceName,
-			StartingCSV:            startingCSV,
-			CatalogSourceNamespace: CatalogSourceNamespace,
+			Channel:                valueOrDefault(subscriptionConfig.Channel, channel),
+			InstallPlanApproval:    v1alpha1.Approval(valueOrDefault(subscriptionConfig.InstallPlanApproval, string(v1alpha1.ApprovalManual))),
+			CatalogSource:          valueOrDefault(subscriptionConfig.Source, CatalogSourceName),
+			StartingCSV:            valueOrDefault(subscriptionConfig.StartingCSV, startingCSV),
+			CatalogSourceNamespace: valueOrDefault(subscriptionConfig.SourceNamespace, CatalogSourceNamespace),
 			Package:                subscriptionName,
 		},
 	}
 	return subscriptionObject
 }
 
+func valueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
 func CheckSubscriptionExists(
 	ctx context.Context, olmClientSet olmclientset.Interface,
 	existingSubscription *v1alpha1.Subscription) (bool, *v1alpha1.Subscription, error) {
@@ -229,30 +259,25 @@ func CheckCRDExists(ctx context.Context, client client.Client, name string) erro
 	return nil
 }
 
-func CleanUpNamespace(ctx context.Context, namespaceName string, client client.Client) error {
+// ApplyObject creates or updates the object with server-side apply, taking ownership of the fields set in it from
+// any other field manager. The object must have its apiVersion and kind set.
+func ApplyObject(ctx context.Context, k8Client client.Client, obj client.Object) error {
+	return k8Client.Patch(ctx, obj, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// CleanUpNamespace deletes the namespace when it is owned by owner.
+func CleanUpNamespace(ctx context.Context, namespaceName string, client client.Client, owner Owner) error {
 	logger := log.FromContext(ctx)
 
-	// check namespace exist
-	logger.Info("Checking namespace exist", "Namespace", namespaceName)
-	namespaceObj := &corev1.Namespace{}
-	if err := client.Get(ctx, types.NamespacedName{Name: namespaceName}, namespaceObj); err != nil {
-		if apierrors.IsNotFound(err) {
-			logger.Info("Namespace does not exist", "Namespace", namespaceName)
-			return nil
-		}
+	namespaceObj, err := GetOwnedNamespace(ctx, client, namespaceName, owner)
+	if err != nil || namespaceObj == nil {
 		return err
 	}
-
-	// check label exist
-	labelExist := CheckLabelExist(namespaceObj.Labels)
-
-	if labelExist {
-		// delete namespace
-		if err := client.Delete(ctx, namespaceObj); err != nil && !apierrors.IsNotFound(err) {
-			return err
-		}
-		logger.Info("Successfully deleted Namespace", "Namespace", namespaceName)
+	// delete namespace
+	if err := client.Delete(ctx, namespaceObj); err != nil && !apierrors.IsNotFound(err) {
+		return err
 	}
+	logger.Info("Successfully deleted Namespace", "Namespace", namespaceName)
 	return nil
 }
 
@@ -300,12 +325,108 @@ func CleanUpSubscriptionAndCSV(ctx context.Context, olmClientSet olmclientset.In
 	return err
 }
 
+// GetManagedSubscription returns the subscriptionName Subscription when it was created by the Orchestrator operator,
+// nil when it does not exist or was created outside of the Orchestrator operator.
+func GetManagedSubscription(
+	ctx context.Context, olmClientSet olmclientset.Interface,
+	subscriptionName, namespace string) (*v1alpha1.Subscription, error) {
+
+	subscription, err := olmClientSet.OperatorsV1alpha1().Subscriptions(namespace).Get(ctx, subscriptionName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		log.FromContext(ctx).Error(err, "Error occurred when retrieving Subscription", "SubscriptionName", subscriptionName, "NS", namespace)
+		return nil, err
+	}
+	if !CheckLabelExist(subscription.Labels) {
+		return nil, nil
+	}
+	return subscription, nil
+}
+
+// UninstallOperator deletes subscription, its CSV and, once no other Subscription is left in the namespace, the
+// operatorGroupName OperatorGroup. It returns the descriptions of the deleted objects.
+func UninstallOperator(
+	ctx context.Context, k8Client client.Client, olmClientSet olmclientset.Interface,
+	subscription *v1alpha1.Subscription, operatorGroupName string) ([]string, error) {
+
+	logger := log.FromContext(ctx)
+	namespace := subscription.Namespace
+
+	if err := CleanUpSubscriptionAndCSV(ctx, olmClientSet, subscription); err != nil {
+		return nil, err
+	}
+	removed := []string{fmt.Sprintf("Subscription %s/%s", namespace, subscription.Name)}
+	if subscription.Status.InstalledCSV != "" {
+		removed = append(removed, fmt.Sprintf("ClusterServiceVersion %s/%s", namespace, subscription.Status.InstalledCSV))
+	}
+
+	// the OperatorGroup is shared by the operators installed in the namespace
+	subscriptions, err := olmClientSet.OperatorsV1alpha1().Subscriptions(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		logger.Error(err, "Error occurred when listing Subscriptions", "NS", namespace)
+		return removed, err
+	}
+	if len(subscriptions.Items) > 0 {
+		logger.Info("Keeping OperatorGroup used by other Subscriptions", "OperatorGroup", operatorGroupName, "NS", namespace)
+		return removed, nil
+	}
+	operatorGroup := &operatorsv1.OperatorGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: operatorGroupName, Namespace: namespace},
+	}
+	if err := k8Client.Delete(ctx, operatorGroup); err != nil {
+		if apierrors.IsNotFound(err) {
+			return removed, nil
+		}
+		logger.Error(err, "Error occurred when deleting OperatorGroup", "OperatorGroup", operatorGroupName, "NS", namespace)
+		return removed, err
+	}
+	logger.Info("Successfully deleted OperatorGroup", "OperatorGroup", operatorGroupName)
+	return append(removed, fmt.Sprintf("OperatorGroup %s/%s", namespace, operatorGroupName)), nil
+}
+
 func GetOrchestratorLabel() map[string]string {
 	return map[string]string{
 		CreatedByLabelKey: CreatedByLabelValue,
 	}
 }
 
+// GetInstance returns the instance of orchestrator, i.e. its UID. The name is not used, as Orchestrators in different
+// namespaces can have the same name.
+func GetInstance(orchestrator metav1.Object) string {
+	return string(orchestrator.GetUID())
+}
+
+// GetInstanceLabels returns the labels of the objects created for the instance Orchestrator.
+func GetInstanceLabels(instance string) map[string]string {
+	labels := GetOrchestratorLabel()
+	labels[InstanceLabelKey] = instance
+	return labels
+}
+
+// Owner identifies the Orchestrator whose objects are cleaned up.
+type Owner struct {
+	// Instance of the Orchestrator, as returned by GetInstance
+	Instance string
+	// Whether no other Orchestrator exists. The last Orchestrator also owns the objects without instance label,
+	// which are either shared by all the Orchestrators, such as the operator namespaces, or created by previous
+	// versions of the operator.
+	Last bool
+}
+
+// Owns returns whether obj was created by the operator for the Orchestrator.
+func (o Owner) Owns(obj metav1.Object) bool {
+	if !CheckLabelExist(obj.GetLabels()) {
+		return false
+	}
+	instance, found := obj.GetLabels()[InstanceLabelKey]
+	if !found {
+		return o.Last
+	}
+	return instance == o.Instance
+}
+
 func CheckLabelExist(labels map[string]string) bool {
 	labelValue, labelExist := labels[CreatedByLabelKey]
 	if !labelExist {
@@ -322,6 +443,9 @@ func updateNamespaceLabel(namespace *corev1.Namespace, ctx context.Context, clie
 	nsLogger.Info("Updating namespace with new label", "NS", namespaceName)
 
 	// add new label to namespace label map
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
 	namespace.Labels[CreatedByLabelKey] = CreatedByLabelValue
 	if err := client.Update(ctx, namespace); err != nil {
 		nsLogger.Info("Error occurred when updating namespace with new label", "NS", namespaceName)
@@ -330,39 +454,3 @@ func updateNamespaceLabel(namespace *corev1.Namespace, ctx context.Context, clie
 	nsLogger.Info("Successfully updated namespace with new label", "NS", namespaceName)
 	return nil
 }
-
-// RemoveCustomResourcesInNamespace removes orchestrator labelled CR in a given namespace
-// returns error
-func RemoveCustomResourcesInNamespace[T client.ObjectList, I client.Object](ctx context.Context,
-	k8client client.Client,
-	objList T, getItems func(T) []I,
-	namespace string) error {
-
-	logger := log.FromContext(ctx)
-	logger.Info("Removing custom resources in namespace", "NS", namespace)
-
-	listOptions := []client.ListOption{
-		client.InNamespace(namespace),
-		client.MatchingLabels(GetOrchestratorLabel())}
-
-	// List the CRs
-	if err := k8client.List(ctx, objList, listOptions...); err != nil {
-		if apierrors.IsNotFound(err) {
-			logger.Info("Custom resource not found in namespace", "NS", namespace)
-			return nil
-		}
-		logger.Error(err, "Error occurred when listing resources")
-		return err
-	}
-	var errorList []error
-	for _, item := range getItems(objList) {
-		if err := k8client.Delete(ctx, item); err != nil {
-			logger.Error(err, "Error occurred when deleting custom resource", "CR-Name", item.GetName())
-			errorList = append(errorList, err)
-		}
-	}
-	if len(errorList) > 0 {
-		return errors.NewAggregate(errorList)
-	}
-	return nil
-}
@@ -18,10 +18,10 @@ package kube
 
 import (
 	"context"
-	sonataapi "github.com/apache/incubator-kie-tools/packages/sonataflow-operator/api/v1alpha08"
 	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	olmclientsetfake "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/fake"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -31,7 +31,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
 )
@@ -129,6 +128,19 @@ func TestCreateNamespace(t *testing.T) {
 	}
 }
 
+func TestCreateInstanceNamespace(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	assert.NoError(t, CreateInstanceNamespace(ctx, fakeClient, orchestratorNamespace, "orchestrator"))
+
+	namespace := &corev1.Namespace{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: orchestratorNamespace}, namespace))
+	assert.Equal(t, GetInstanceLabels("orchestrator"), namespace.Labels)
+}
+
 func TestInstallSubscriptionAndOperatorGroup(t *testing.T) {
 	ctx := context.TODO()
 	scheme := runtime.NewScheme()
@@ -220,6 +232,20 @@ func TestGetOperatorGroup(t *testing.T) {
 		assert.NoError(t, err, "Expected no error")
 	})
 
+	// Test reuse of an operator group with another name, a namespace cannot have several operator groups
+	t.Run("Reuse existing operator group", func(t *testing.T) {
+		existingOperatorGroup := operatorGroup.DeepCopy()
+		existingOperatorGroup.Name = "global-operators"
+		fakeClientWithOperatorGroup := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existingOperatorGroup).Build()
+		err := getOperatorGroup(ctx, fakeClientWithOperatorGroup, orchestratorNamespace, orchestratorOperatorGroup)
+		assert.NoError(t, err, "Expected no error")
+
+		operatorGroups := &operatorsv1.OperatorGroupList{}
+		assert.NoError(t, fakeClientWithOperatorGroup.List(ctx, operatorGroups))
+		assert.Len(t, operatorGroups.Items, 1)
+		assert.Equal(t, "global-operators", operatorGroups.Items[0].Name)
+	})
+
 	// Test create operator group
 	t.Run("Create operator group", func(t *testing.T) {
 		fakeClientWithoutOperatorGroup := fake.NewClientBuilder().WithScheme(scheme).Build()
@@ -242,10 +268,48 @@ func TestCreateSubscriptionObject(t *testing.T) {
 		orchestratorNamespace,
 		subscription.Spec.Channel,
 		subscription.Spec.StartingCSV,
+		orchestratorv1alpha3.Subscription{},
 	)
 	assert.Equal(t, subscription, actualSubscription)
 }
 
+func TestCreateSubscriptionObjectWithConfig(t *testing.T) {
+	subscriptionConfig := orchestratorv1alpha3.Subscription{
+		Channel:             "stable-1.7",
+		StartingCSV:         "starting-csv.v1.7.0",
+		Source:              "mirrored-operators",
+		SourceNamespace:     "openshift-mirror",
+		InstallPlanApproval: string(v1alpha1.ApprovalAutomatic),
+	}
+	actualSubscription := CreateSubscriptionObject(
+		subscriptionName,
+		orchestratorNamespace,
+		subscription.Spec.Channel,
+		subscription.Spec.StartingCSV,
+		subscriptionConfig,
+	)
+	assert.Equal(t, &v1alpha1.SubscriptionSpec{
+		Channel:                "stable-1.7",
+		StartingCSV:            "starting-csv.v1.7.0",
+		InstallPlanApproval:    v1alpha1.ApprovalAutomatic,
+		CatalogSource:          "mirrored-operators",
+		CatalogSourceNamespace: "openshift-mirror",
+		Package:                subscriptionName,
+	}, actualSubscription.Spec)
+
+	actualSubscription = CreateSubscriptionObject(
+		subscriptionName,
+		orchestratorNamespace,
+		subscription.Spec.Channel,
+		subscription.Spec.StartingCSV,
+		orchestratorv1alpha3.Subscription{Channel: "stable-1.7"},
+	)
+	assert.Equal(t, "stable-1.7", actualSubscription.Spec.Channel)
+	assert.Equal(t, subscription.Spec.StartingCSV, actualSubscription.Spec.StartingCSV)
+	assert.Equal(t, CatalogSourceName, actualSubscription.Spec.CatalogSource)
+	assert.Equal(t, v1alpha1.ApprovalManual, actualSubscription.Spec.InstallPlanApproval)
+}
+
 func TestCheckSubscriptionExists(t *testing.T) {
 	ctx := context.TODO()
 	scheme := runtime.NewScheme()
@@ -265,6 +329,77 @@ func TestCheckSubscriptionExists(t *testing.T) {
 	})
 }
 
+func TestGetManagedSubscription(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("Subscription created by the orchestrator", func(t *testing.T) {
+		fakeOLMClientSet := olmclientsetfake.NewSimpleClientset(subscription)
+		managedSubscription, err := GetManagedSubscription(ctx, fakeOLMClientSet, subscriptionName, orchestratorNamespace)
+		assert.NoError(t, err)
+		assert.NotNil(t, managedSubscription)
+	})
+	t.Run("Subscription created outside of the orchestrator", func(t *testing.T) {
+		unmanagedSubscription := subscription.DeepCopy()
+		unmanagedSubscription.Labels = nil
+		fakeOLMClientSet := olmclientsetfake.NewSimpleClientset(unmanagedSubscription)
+		managedSubscription, err := GetManagedSubscription(ctx, fakeOLMClientSet, subscriptionName, orchestratorNamespace)
+		assert.NoError(t, err)
+		assert.Nil(t, managedSubscription)
+	})
+	t.Run("Subscription does not exist", func(t *testing.T) {
+		fakeOLMClientSet := olmclientsetfake.NewSimpleClientset()
+		managedSubscription, err := GetManagedSubscription(ctx, fakeOLMClientSet, subscriptionName, orchestratorNamespace)
+		assert.NoError(t, err)
+		assert.Nil(t, managedSubscription)
+	})
+}
+
+func TestUninstallOperator(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(operatorsv1.AddToScheme(scheme))
+
+	installedSubscription := subscription.DeepCopy()
+	installedSubscription.Status.InstalledCSV = "orchestrator-operator.v1.0.0"
+	csv := &v1alpha1.ClusterServiceVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: installedSubscription.Status.InstalledCSV, Namespace: orchestratorNamespace},
+	}
+	operatorGroup := &operatorsv1.OperatorGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: orchestratorOperatorGroup, Namespace: orchestratorNamespace},
+	}
+
+	t.Run("Uninstall the only operator of the namespace", func(t *testing.T) {
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(operatorGroup.DeepCopy()).Build()
+		fakeOLMClientSet := olmclientsetfake.NewSimpleClientset(installedSubscription, csv)
+		removed, err := UninstallOperator(ctx, fakeClient, fakeOLMClientSet, installedSubscription, orchestratorOperatorGroup)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"Subscription orchestrator-namespace/orchestrator-subscription",
+			"ClusterServiceVersion orchestrator-namespace/orchestrator-operator.v1.0.0",
+			"OperatorGroup orchestrator-namespace/orchestrator-operator-group",
+		}, removed)
+
+		_, err = fakeOLMClientSet.OperatorsV1alpha1().Subscriptions(orchestratorNamespace).Get(ctx, subscriptionName, metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err), "Expected Subscription deleted")
+		_, err = fakeOLMClientSet.OperatorsV1alpha1().ClusterServiceVersions(orchestratorNamespace).Get(ctx, csv.Name, metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err), "Expected CSV deleted")
+		err = fakeClient.Get(ctx, types.NamespacedName{Name: orchestratorOperatorGroup, Namespace: orchestratorNamespace}, &operatorsv1.OperatorGroup{})
+		assert.True(t, apierrors.IsNotFound(err), "Expected OperatorGroup deleted")
+	})
+	t.Run("Keep the OperatorGroup of another operator", func(t *testing.T) {
+		otherSubscription := &v1alpha1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-subscription", Namespace: orchestratorNamespace},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(operatorGroup.DeepCopy()).Build()
+		fakeOLMClientSet := olmclientsetfake.NewSimpleClientset(installedSubscription, csv, otherSubscription)
+		removed, err := UninstallOperator(ctx, fakeClient, fakeOLMClientSet, installedSubscription, orchestratorOperatorGroup)
+		assert.NoError(t, err)
+		assert.Len(t, removed, 2)
+		err = fakeClient.Get(ctx, types.NamespacedName{Name: orchestratorOperatorGroup, Namespace: orchestratorNamespace}, &operatorsv1.OperatorGroup{})
+		assert.NoError(t, err, "Expected OperatorGroup kept")
+	})
+}
+
 func TestCheckCRDExists(t *testing.T) {
 	ctx := context.TODO()
 	scheme := runtime.NewScheme()
@@ -299,11 +434,84 @@ func TestCleanUpNamespace(t *testing.T) {
 	}
 	t.Run("Clean up namespace with no error", func(t *testing.T) {
 		fakeClientWithoutNS := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ns).Build()
-		err := CleanUpNamespace(ctx, orchestratorNamespace, fakeClientWithoutNS)
+		err := CleanUpNamespace(ctx, orchestratorNamespace, fakeClientWithoutNS, Owner{Instance: "orchestrator", Last: true})
 		assert.NoError(t, err, "Expected no error")
+		err = fakeClientWithoutNS.Get(ctx, types.NamespacedName{Name: orchestratorNamespace}, &corev1.Namespace{})
+		assert.True(t, apierrors.IsNotFound(err), "Expected namespace deleted")
+	})
+	t.Run("Keep namespace of another Orchestrator", func(t *testing.T) {
+		otherNS := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: orchestratorNamespace, Labels: GetInstanceLabels("other")},
+		}
+		fakeClientWithNS := fake.NewClientBuilder().WithScheme(scheme).WithObjects(otherNS).Build()
+		err := CleanUpNamespace(ctx, orchestratorNamespace, fakeClientWithNS, Owner{Instance: "orchestrator", Last: true})
+		assert.NoError(t, err, "Expected no error")
+		err = fakeClientWithNS.Get(ctx, types.NamespacedName{Name: orchestratorNamespace}, &corev1.Namespace{})
+		assert.NoError(t, err, "Expected namespace kept")
 	})
 }
 
+func TestOwnerOwns(t *testing.T) {
+	testCases := []struct {
+		name          string
+		owner         Owner
+		labels        map[string]string
+		expectedOwned bool
+	}{
+		{
+			name:          "Object of the Orchestrator",
+			owner:         Owner{Instance: "orchestrator"},
+			labels:        GetInstanceLabels("orchestrator"),
+			expectedOwned: true,
+		},
+		{
+			name:          "Object of another Orchestrator",
+			owner:         Owner{Instance: "orchestrator", Last: true},
+			labels:        GetInstanceLabels("other"),
+			expectedOwned: false,
+		},
+		{
+			name:          "Shared object with other Orchestrators",
+			owner:         Owner{Instance: "orchestrator"},
+			labels:        GetOrchestratorLabel(),
+			expectedOwned: false,
+		},
+		{
+			name:          "Shared object of the last Orchestrator",
+			owner:         Owner{Instance: "orchestrator", Last: true},
+			labels:        GetOrchestratorLabel(),
+			expectedOwned: true,
+		},
+		{
+			name:          "Object not created by the operator",
+			owner:         Owner{Instance: "orchestrator", Last: true},
+			labels:        map[string]string{InstanceLabelKey: "orchestrator"},
+			expectedOwned: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: tc.labels}}
+			assert.Equal(t, tc.expectedOwned, tc.owner.Owns(obj))
+		})
+	}
+}
+
+func TestGetInstance(t *testing.T) {
+	orchestrator := &orchestratorv1alpha3.Orchestrator{
+		ObjectMeta: metav1.ObjectMeta{Name: "orchestrator", Namespace: "team-a", UID: "4b8d9b4c-1e4a-4f5e-9d4a-0c1e2b3a4d5e"},
+	}
+	// an Orchestrator with the same name in another namespace does not own the objects of orchestrator
+	other := &orchestratorv1alpha3.Orchestrator{
+		ObjectMeta: metav1.ObjectMeta{Name: "orchestrator", Namespace: "team-b", UID: "9f1c2d3e-4b5a-6c7d-8e9f-0a1b2c3d4e5f"},
+	}
+	obj := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: GetInstanceLabels(GetInstance(orchestrator))}}
+
+	assert.Equal(t, "4b8d9b4c-1e4a-4f5e-9d4a-0c1e2b3a4d5e", GetInstance(orchestrator))
+	assert.True(t, Owner{Instance: GetInstance(orchestrator)}.Owns(obj))
+	assert.False(t, Owner{Instance: GetInstance(other), Last: true}.Owns(obj))
+}
+
 func TestGetOrchestratorLabel(t *testing.T) {
 	expectedLabels := map[string]string{
 		CreatedByLabelKey: CreatedByLabelValue,
@@ -369,80 +577,3 @@ func TestUpdateNamespaceLabel(t *testing.T) {
 		assert.Error(t, err, "Expected error")
 	})
 }
-
-func TestRemoveCustomResourcesInNamespace(t *testing.T) {
-	ctx := context.TODO()
-	scheme := runtime.NewScheme()
-	utilruntime.Must(sonataapi.AddToScheme(scheme))
-
-	testCases := []struct {
-		name          string
-		namespace     string
-		crObj         client.Object
-		crObjList     client.ObjectList
-		getItems      func(list client.ObjectList) []client.Object
-		expectedError error
-	}{
-		{
-			name:      "Remove SonataFlowPlatform custom resources",
-			namespace: orchestratorNamespace,
-			crObj: &sonataapi.SonataFlowPlatform{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "sonataflow-platform",
-					Namespace: orchestratorNamespace,
-					Labels:    existingLabelMap,
-				},
-			},
-			crObjList: &sonataapi.SonataFlowPlatformList{},
-			getItems: func(list client.ObjectList) []client.Object {
-				typedList := list.(*sonataapi.SonataFlowPlatformList)
-				objs := make([]client.Object, len(typedList.Items))
-				for i := range typedList.Items {
-					objs[i] = &typedList.Items[i]
-				}
-				return objs
-			},
-			expectedError: nil,
-		},
-		{
-			name:      "Remove SonataFlowClusterPlatform custom resources",
-			namespace: orchestratorNamespace,
-			crObj: &sonataapi.SonataFlowClusterPlatform{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "cluster-platform",
-					Namespace: orchestratorNamespace,
-					Labels:    existingLabelMap,
-				},
-			},
-			crObjList: &sonataapi.SonataFlowClusterPlatformList{},
-			getItems: func(list client.ObjectList) []client.Object {
-				typedList := list.(*sonataapi.SonataFlowClusterPlatformList)
-				objs := make([]client.Object, len(typedList.Items))
-				for i := range typedList.Items {
-					objs[i] = &typedList.Items[i]
-				}
-				return objs
-			},
-			expectedError: nil,
-		},
-		{
-			name:      "Remove non existent custom resources",
-			namespace: orchestratorNamespace,
-			crObj:     &sonataapi.SonataFlowClusterPlatform{},
-			crObjList: &sonataapi.SonataFlowClusterPlatformList{},
-			getItems: func(list client.ObjectList) []client.Object {
-				return []client.Object{}
-			},
-			expectedError: nil,
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.crObj).Build()
-			err := RemoveCustomResourcesInNamespace(ctx, fakeClient, tc.crObjList, tc.getItems, tc.namespace)
-			assert.Equal(t, tc.expectedError, err)
-		})
-	}
-
-}
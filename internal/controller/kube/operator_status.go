@@ -0,0 +1,214 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	olmclientset "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// operatorLabelPrefix prefixes the operators.coreos.com/<package>.<namespace> label set by OLM on the components
+// of an operator, including its ClusterServiceVersion.
+const operatorLabelPrefix = "operators.coreos.com/"
+
+// CSVNotSucceededError is returned when the ClusterServiceVersion installed by the Subscription of an operator
+// has not reached the Succeeded phase, so the operands of the operator cannot be created yet.
+type CSVNotSucceededError struct {
+	OperatorStatus orchestratorv1alpha3.OperatorStatus
+}
+
+func (e *CSVNotSucceededError) Error() string {
+	status := e.OperatorStatus
+	if status.InstalledCSV == "" {
+		return fmt.Sprintf("subscription %s/%s has no installed CSV yet", status.Namespace, status.Subscription)
+	}
+	return fmt.Sprintf("CSV %s of subscription %s/%s is in phase %s: %s %s",
+		status.InstalledCSV, status.Namespace, status.Subscription, status.Phase, status.Reason, status.Message)
+}
+
+// IsCSVNotSucceeded returns whether err reports a ClusterServiceVersion that has not reached the Succeeded phase.
+func IsCSVNotSucceeded(err error) bool {
+	var csvErr *CSVNotSucceededError
+	return errors.As(err, &csvErr)
+}
+
+// IsCSVFailed returns whether err reports a ClusterServiceVersion in the Failed phase.
+func IsCSVFailed(err error) bool {
+	var csvErr *CSVNotSucceededError
+	return errors.As(err, &csvErr) && csvErr.OperatorStatus.Phase == string(v1alpha1.CSVPhaseFailed)
+}
+
+// GetOperatorStatus resolves the ClusterServiceVersion installed by a Subscription and returns its phase and reason.
+// A CSVNotSucceededError is returned along with the status when the CSV has not reached the Succeeded phase.
+func GetOperatorStatus(
+	ctx context.Context, olmClientSet olmclientset.Interface,
+	subscriptionName, namespace string) (orchestratorv1alpha3.OperatorStatus, error) {
+
+	logger := log.FromContext(ctx)
+	operatorStatus := orchestratorv1alpha3.OperatorStatus{Subscription: subscriptionName, Namespace: namespace}
+
+	subscription, err := olmClientSet.OperatorsV1alpha1().Subscriptions(namespace).Get(ctx, subscriptionName, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(err, "Error occurred when retrieving Subscription", "SubscriptionName", subscriptionName, "NS", namespace)
+		return operatorStatus, err
+	}
+	operatorStatus.InstalledCSV = subscription.Status.InstalledCSV
+	return getCSVStatus(ctx, olmClientSet, operatorStatus)
+}
+
+// OperatorInstallations detects the installations of operators made outside of the Orchestrator operator. The
+// Subscriptions, read from the cache of the manager, and the ClusterServiceVersions of the cluster are listed once,
+// on the first detection that needs them, and shared by the detections of all the operators of a reconciliation.
+type OperatorInstallations struct {
+	reader        client.Reader
+	olmClientSet  olmclientset.Interface
+	subscriptions *v1alpha1.SubscriptionList
+	csvs          *v1alpha1.ClusterServiceVersionList
+}
+
+// NewOperatorInstallations returns the OperatorInstallations of a reconciliation. The Subscriptions are listed with
+// reader, usually the cached client of the manager, and the ClusterServiceVersions with olmClientSet.
+func NewOperatorInstallations(reader client.Reader, olmClientSet olmclientset.Interface) *OperatorInstallations {
+	return &OperatorInstallations{reader: reader, olmClientSet: olmClientSet}
+}
+
+// Detect looks for an installation of the packageName operator made outside of the Orchestrator operator, either by
+// a Subscription in any namespace or by a ClusterServiceVersion installed without Subscription.
+// It returns nil when the operator is not installed or when the subscriptionName Subscription of the Orchestrator
+// operator exists. The status of a detected installation is returned along with a CSVNotSucceededError when its
+// CSV has not reached the Succeeded phase.
+func (i *OperatorInstallations) Detect(
+	ctx context.Context, packageName, subscriptionName, namespace string) (*orchestratorv1alpha3.OperatorStatus, error) {
+
+	logger := log.FromContext(ctx)
+
+	if i.subscriptions == nil {
+		subscriptions := &v1alpha1.SubscriptionList{}
+		if err := i.reader.List(ctx, subscriptions); err != nil {
+			logger.Error(err, "Error occurred when listing Subscriptions", "Package", packageName)
+			return nil, err
+		}
+		i.subscriptions = subscriptions
+	}
+	if slices.ContainsFunc(i.subscriptions.Items, func(subscription v1alpha1.Subscription) bool {
+		return subscription.Name == subscriptionName && subscription.Namespace == namespace
+	}) {
+		return nil, nil
+	}
+	for _, subscription := range i.subscriptions.Items {
+		if subscription.Spec == nil || subscription.Spec.Package != packageName {
+			continue
+		}
+		logger.Info("Detected operator installed by another Subscription", "Package", packageName,
+			"SubscriptionName", subscription.Name, "NS", subscription.Namespace)
+		operatorStatus, err := getCSVStatus(ctx, i.olmClientSet, orchestratorv1alpha3.OperatorStatus{
+			Subscription: subscription.Name,
+			Namespace:    subscription.Namespace,
+			Ownership:    orchestratorv1alpha3.OperatorDetected,
+			InstalledCSV: subscription.Status.InstalledCSV,
+		})
+		return &operatorStatus, err
+	}
+
+	if i.csvs == nil {
+		// the CSVs of the operators installed in all namespaces are copied to every namespace, the copies are
+		// filtered out by the API server. The package label of a CSV is suffixed with its namespace, which is not
+		// known here, so the CSVs of a package are matched in isCSVOfPackage.
+		csvs, err := i.olmClientSet.OperatorsV1alpha1().ClusterServiceVersions(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+			LabelSelector: "!" + v1alpha1.CopiedLabelKey,
+		})
+		if err != nil {
+			logger.Error(err, "Error occurred when listing ClusterServiceVersions", "Package", packageName)
+			return nil, err
+		}
+		i.csvs = csvs
+	}
+	for _, csv := range i.csvs.Items {
+		if csv.IsCopied() || !isCSVOfPackage(&csv, packageName) {
+			continue
+		}
+		logger.Info("Detected operator installed without Subscription", "Package", packageName,
+			"ClusterServiceVersion", csv.Name, "NS", csv.Namespace)
+		operatorStatus := orchestratorv1alpha3.OperatorStatus{
+			Namespace:    csv.Namespace,
+			Ownership:    orchestratorv1alpha3.OperatorDetected,
+			InstalledCSV: csv.Name,
+		}
+		operatorStatus, err := csvStatus(&csv, operatorStatus)
+		return &operatorStatus, err
+	}
+	return nil, nil
+}
+
+// isCSVOfPackage returns whether csv belongs to the packageName operator, either through the label set by OLM
+// on the components of the operator or through its name.
+func isCSVOfPackage(csv *v1alpha1.ClusterServiceVersion, packageName string) bool {
+	for label := range csv.Labels {
+		if strings.HasPrefix(label, operatorLabelPrefix+packageName+".") {
+			return true
+		}
+	}
+	return strings.HasPrefix(csv.Name, packageName+".v")
+}
+
+// getCSVStatus fills the phase and reason of the CSV installed for operatorStatus.
+func getCSVStatus(
+	ctx context.Context, olmClientSet olmclientset.Interface,
+	operatorStatus orchestratorv1alpha3.OperatorStatus) (orchestratorv1alpha3.OperatorStatus, error) {
+
+	logger := log.FromContext(ctx)
+	if operatorStatus.InstalledCSV == "" {
+		logger.Info("Subscription has no installed CSV yet", "SubscriptionName", operatorStatus.Subscription, "NS", operatorStatus.Namespace)
+		return operatorStatus, &CSVNotSucceededError{OperatorStatus: operatorStatus}
+	}
+
+	csv, err := olmClientSet.OperatorsV1alpha1().ClusterServiceVersions(operatorStatus.Namespace).Get(ctx, operatorStatus.InstalledCSV, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(err, "Error occurred when retrieving CSV", "ClusterServiceVersion", operatorStatus.InstalledCSV, "NS", operatorStatus.Namespace)
+		return operatorStatus, err
+	}
+	operatorStatus, err = csvStatus(csv, operatorStatus)
+	if err != nil {
+		logger.Info("CSV has not succeeded yet", "ClusterServiceVersion", csv.Name, "Phase", csv.Status.Phase, "Reason", csv.Status.Reason)
+	}
+	return operatorStatus, err
+}
+
+// csvStatus fills the phase and reason of csv in operatorStatus, and returns a CSVNotSucceededError when the CSV
+// has not reached the Succeeded phase.
+func csvStatus(
+	csv *v1alpha1.ClusterServiceVersion,
+	operatorStatus orchestratorv1alpha3.OperatorStatus) (orchestratorv1alpha3.OperatorStatus, error) {
+
+	operatorStatus.Phase = string(csv.Status.Phase)
+	operatorStatus.Reason = string(csv.Status.Reason)
+	operatorStatus.Message = csv.Status.Message
+	if csv.Status.Phase != v1alpha1.CSVPhaseSucceeded {
+		return operatorStatus, &CSVNotSucceededError{OperatorStatus: operatorStatus}
+	}
+	return operatorStatus, nil
+}
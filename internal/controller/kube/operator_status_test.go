@@ -0,0 +1,226 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	olmclientsetfake "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/fake"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestGetOperatorStatus(t *testing.T) {
+	ctx := context.TODO()
+	installedCSV := "starting-csv"
+
+	installedSubscription := subscription.DeepCopy()
+	installedSubscription.Status.InstalledCSV = installedCSV
+
+	newCSV := func(phase v1alpha1.ClusterServiceVersionPhase, reason v1alpha1.ConditionReason) *v1alpha1.ClusterServiceVersion {
+		return &v1alpha1.ClusterServiceVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: installedCSV, Namespace: orchestratorNamespace},
+			Status:     v1alpha1.ClusterServiceVersionStatus{Phase: phase, Reason: reason, Message: "install strategy"},
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		objects          []runtime.Object
+		expectedPhase    string
+		expectedReason   string
+		expectNotFound   bool
+		expectNotSucceed bool
+		expectFailed     bool
+	}{
+		{
+			name:           "Subscription does not exist",
+			objects:        []runtime.Object{},
+			expectNotFound: true,
+		},
+		{
+			name:             "Subscription has no installed CSV",
+			objects:          []runtime.Object{subscription.DeepCopy()},
+			expectNotSucceed: true,
+		},
+		{
+			name:             "CSV is installing",
+			objects:          []runtime.Object{installedSubscription, newCSV(v1alpha1.CSVPhaseInstalling, v1alpha1.CSVReasonWaiting)},
+			expectedPhase:    string(v1alpha1.CSVPhaseInstalling),
+			expectedReason:   string(v1alpha1.CSVReasonWaiting),
+			expectNotSucceed: true,
+		},
+		{
+			name:             "CSV failed",
+			objects:          []runtime.Object{installedSubscription, newCSV(v1alpha1.CSVPhaseFailed, v1alpha1.CSVReasonComponentFailed)},
+			expectedPhase:    string(v1alpha1.CSVPhaseFailed),
+			expectedReason:   string(v1alpha1.CSVReasonComponentFailed),
+			expectNotSucceed: true,
+			expectFailed:     true,
+		},
+		{
+			name:           "CSV succeeded",
+			objects:        []runtime.Object{installedSubscription, newCSV(v1alpha1.CSVPhaseSucceeded, v1alpha1.CSVReasonInstallSuccessful)},
+			expectedPhase:  string(v1alpha1.CSVPhaseSucceeded),
+			expectedReason: string(v1alpha1.CSVReasonInstallSuccessful),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeOLMClientSet := olmclientsetfake.NewSimpleClientset(tc.objects...)
+
+			operatorStatus, err := GetOperatorStatus(ctx, fakeOLMClientSet, subscriptionName, orchestratorNamespace)
+			assert.Equal(t, tc.expectNotFound, apierrors.IsNotFound(err))
+			assert.Equal(t, tc.expectNotSucceed, IsCSVNotSucceeded(err))
+			assert.Equal(t, tc.expectFailed, IsCSVFailed(err))
+			if !tc.expectNotFound && !tc.expectNotSucceed {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, subscriptionName, operatorStatus.Subscription)
+			assert.Equal(t, orchestratorNamespace, operatorStatus.Namespace)
+			assert.Equal(t, tc.expectedPhase, operatorStatus.Phase)
+			assert.Equal(t, tc.expectedReason, operatorStatus.Reason)
+		})
+	}
+}
+
+func TestDetectOperatorInstallation(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	otherNamespace := "openshift-operators"
+	detectedCSV := subscriptionName + ".v1.0.0"
+
+	otherSubscription := subscription.DeepCopy()
+	otherSubscription.Name = "my-subscription"
+	otherSubscription.Namespace = otherNamespace
+	otherSubscription.Labels = nil
+	otherSubscription.Status.InstalledCSV = detectedCSV
+
+	newCSV := func(name, namespace string, phase v1alpha1.ClusterServiceVersionPhase, labels map[string]string) *v1alpha1.ClusterServiceVersion {
+		return &v1alpha1.ClusterServiceVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Status:     v1alpha1.ClusterServiceVersionStatus{Phase: phase},
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		objects          []runtime.Object
+		expectedStatus   *orchestratorv1alpha3.OperatorStatus
+		expectNotSucceed bool
+	}{
+		{
+			name:    "Operator not installed",
+			objects: []runtime.Object{},
+		},
+		{
+			name:    "Operator installed by the orchestrator",
+			objects: []runtime.Object{subscription.DeepCopy(), otherSubscription},
+		},
+		{
+			name:    "Operator installed by another subscription",
+			objects: []runtime.Object{otherSubscription, newCSV(detectedCSV, otherNamespace, v1alpha1.CSVPhaseSucceeded, nil)},
+			expectedStatus: &orchestratorv1alpha3.OperatorStatus{
+				Subscription: "my-subscription",
+				Namespace:    otherNamespace,
+				Ownership:    orchestratorv1alpha3.OperatorDetected,
+				InstalledCSV: detectedCSV,
+				Phase:        string(v1alpha1.CSVPhaseSucceeded),
+			},
+		},
+		{
+			name:    "Operator installed by another subscription is installing",
+			objects: []runtime.Object{otherSubscription, newCSV(detectedCSV, otherNamespace, v1alpha1.CSVPhaseInstalling, nil)},
+			expectedStatus: &orchestratorv1alpha3.OperatorStatus{
+				Subscription: "my-subscription",
+				Namespace:    otherNamespace,
+				Ownership:    orchestratorv1alpha3.OperatorDetected,
+				InstalledCSV: detectedCSV,
+				Phase:        string(v1alpha1.CSVPhaseInstalling),
+			},
+			expectNotSucceed: true,
+		},
+		{
+			name: "Operator installed without subscription",
+			objects: []runtime.Object{
+				// copies of the CSV of an operator installed in all namespaces are ignored
+				newCSV("custom-name", orchestratorNamespace, v1alpha1.CSVPhaseSucceeded,
+					map[string]string{v1alpha1.CopiedLabelKey: otherNamespace, operatorLabelPrefix + subscriptionName + "." + otherNamespace: ""}),
+				newCSV("custom-name", otherNamespace, v1alpha1.CSVPhaseSucceeded,
+					map[string]string{operatorLabelPrefix + subscriptionName + "." + otherNamespace: ""}),
+				newCSV("other-operator.v1.0.0", otherNamespace, v1alpha1.CSVPhaseSucceeded, nil),
+			},
+			expectedStatus: &orchestratorv1alpha3.OperatorStatus{
+				Namespace:    otherNamespace,
+				Ownership:    orchestratorv1alpha3.OperatorDetected,
+				InstalledCSV: "custom-name",
+				Phase:        string(v1alpha1.CSVPhaseSucceeded),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tc.objects...).Build()
+			fakeOLMClientSet := olmclientsetfake.NewSimpleClientset(tc.objects...)
+			installations := NewOperatorInstallations(fakeClient, fakeOLMClientSet)
+
+			operatorStatus, err := installations.Detect(ctx, subscriptionName, subscriptionName, orchestratorNamespace)
+			assert.Equal(t, tc.expectNotSucceed, IsCSVNotSucceeded(err))
+			if !tc.expectNotSucceed {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedStatus, operatorStatus)
+		})
+	}
+}
+
+func TestDetectOperatorInstallationListsOnce(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	listCalls := 0
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		List: func(ctx context.Context, client client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			listCalls++
+			return client.List(ctx, list, opts...)
+		},
+	}).Build()
+	fakeOLMClientSet := olmclientsetfake.NewSimpleClientset()
+	installations := NewOperatorInstallations(fakeClient, fakeOLMClientSet)
+
+	for _, packageName := range []string{"logic-operator-rhel8", "serverless-operator", "rhdh"} {
+		operatorStatus, err := installations.Detect(ctx, packageName, packageName, orchestratorNamespace)
+		assert.NoError(t, err)
+		assert.Nil(t, operatorStatus)
+	}
+	assert.Equal(t, 1, listCalls)
+	assert.Len(t, fakeOLMClientSet.Actions(), 1)
+}
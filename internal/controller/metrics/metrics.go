@@ -0,0 +1,111 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics holds the Prometheus collectors of the operator, registered on the controller-runtime metrics
+// registry served on the /metrics endpoint of the manager.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespace = "orchestrator"
+
+	namespaceLabel    = "orchestrator_namespace"
+	orchestratorLabel = "orchestrator"
+	subsystemLabel    = "subsystem"
+	kindLabel         = "kind"
+)
+
+var (
+	// SubsystemReady reports whether the condition of a subsystem of an Orchestrator is true.
+	SubsystemReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "subsystem_ready",
+		Help:      "Whether a subsystem of the Orchestrator is ready (1) or not (0).",
+	}, []string{namespaceLabel, orchestratorLabel, subsystemLabel})
+
+	// ReconcileDuration observes the duration of the reconciliation of each subsystem.
+	ReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "subsystem_reconcile_duration_seconds",
+		Help:      "Duration of the reconciliation of a subsystem of the Orchestrator, in seconds.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{subsystemLabel})
+
+	// PendingInstallPlans reports the InstallPlans of the managed operators waiting for approval.
+	PendingInstallPlans = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "pending_install_plans",
+		Help:      "Number of InstallPlans of the operators managed by the Orchestrator waiting for approval.",
+	}, []string{namespaceLabel, orchestratorLabel})
+
+	// DriftCorrections counts the resources restored to the state rendered by the operator.
+	DriftCorrections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "drift_corrections_total",
+		Help:      "Number of resources updated because they drifted from the state rendered by the operator.",
+	}, []string{kindLabel})
+
+	// LastSuccessfulReconcile records when an Orchestrator was last reconciled without errors.
+	LastSuccessfulReconcile = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_successful_reconcile_timestamp_seconds",
+		Help:      "Unix time of the last reconciliation of the Orchestrator that completed without errors.",
+	}, []string{namespaceLabel, orchestratorLabel})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		SubsystemReady,
+		ReconcileDuration,
+		PendingInstallPlans,
+		DriftCorrections,
+		LastSuccessfulReconcile,
+	)
+}
+
+// SetSubsystemReady records whether the subsystem of the orchestrator of namespace is ready.
+func SetSubsystemReady(namespace, orchestrator, subsystem string, ready bool) {
+	value := 0.0
+	if ready {
+		value = 1
+	}
+	SubsystemReady.WithLabelValues(namespace, orchestrator, subsystem).Set(value)
+}
+
+// ObserveReconcileDuration records the time elapsed since start to reconcile the subsystem.
+func ObserveReconcileDuration(subsystem string, start time.Time) {
+	ReconcileDuration.WithLabelValues(subsystem).Observe(time.Since(start).Seconds())
+}
+
+// RecordDriftCorrection counts a resource of kind updated back to the state rendered by the operator.
+func RecordDriftCorrection(kind string) {
+	DriftCorrections.WithLabelValues(kind).Inc()
+}
+
+// DeleteOrchestratorMetrics removes the series of a deleted orchestrator of namespace, so that it no longer triggers
+// alerts. The series of the Orchestrators with the same name in other namespaces are kept.
+func DeleteOrchestratorMetrics(namespace, orchestrator string) {
+	labels := prometheus.Labels{namespaceLabel: namespace, orchestratorLabel: orchestrator}
+	SubsystemReady.DeletePartialMatch(labels)
+	PendingInstallPlans.DeletePartialMatch(labels)
+	LastSuccessfulReconcile.DeletePartialMatch(labels)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetSubsystemReady(t *testing.T) {
+	SetSubsystemReady("default", "orchestrator-sample", "RHDH", true)
+	assert.Equal(t, 1.0, testutil.ToFloat64(SubsystemReady.WithLabelValues("default", "orchestrator-sample", "RHDH")))
+
+	SetSubsystemReady("default", "orchestrator-sample", "RHDH", false)
+	assert.Equal(t, 0.0, testutil.ToFloat64(SubsystemReady.WithLabelValues("default", "orchestrator-sample", "RHDH")))
+}
+
+func TestObserveReconcileDuration(t *testing.T) {
+	ObserveReconcileDuration("Tekton", time.Now())
+	assert.Equal(t, 1, testutil.CollectAndCount(ReconcileDuration))
+}
+
+func TestRecordDriftCorrection(t *testing.T) {
+	before := testutil.ToFloat64(DriftCorrections.WithLabelValues("Task"))
+	RecordDriftCorrection("Task")
+	assert.Equal(t, before+1, testutil.ToFloat64(DriftCorrections.WithLabelValues("Task")))
+}
+
+func TestDeleteOrchestratorMetrics(t *testing.T) {
+	SetSubsystemReady("team-a", "deleted", "RHDH", true)
+	SetSubsystemReady("team-a", "kept", "RHDH", true)
+	// an Orchestrator with the same name in another namespace
+	SetSubsystemReady("team-b", "deleted", "RHDH", true)
+	PendingInstallPlans.WithLabelValues("team-a", "deleted").Set(1)
+	LastSuccessfulReconcile.WithLabelValues("team-a", "deleted").SetToCurrentTime()
+
+	DeleteOrchestratorMetrics("team-a", "deleted")
+	assert.False(t, SubsystemReady.DeleteLabelValues("team-a", "deleted", "RHDH"))
+	assert.False(t, PendingInstallPlans.DeleteLabelValues("team-a", "deleted"))
+	assert.False(t, LastSuccessfulReconcile.DeleteLabelValues("team-a", "deleted"))
+	assert.True(t, SubsystemReady.DeleteLabelValues("team-a", "kept", "RHDH"))
+	assert.True(t, SubsystemReady.DeleteLabelValues("team-b", "deleted", "RHDH"))
+}
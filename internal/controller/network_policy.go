@@ -2,15 +2,22 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"slices"
 
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
 	knative "github.com/rhdhorchestrator/orchestrator-operator/internal/controller/knative"
 	kubeoperations "github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	orchestratormetrics "github.com/rhdhorchestrator/orchestrator-operator/internal/controller/metrics"
 
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	apierrros "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -22,6 +29,9 @@ const (
 	allowIntraNamespace                       = "allow-intra-namespace"
 	allowMonitoringToSonataflowWorkflows      = "allow-monitoring-to-sonataflow-and-workflows"
 	allowServerlessLogicToSonataFlowWorkflows = "allow-openshift-serverless-logic-to-sonataflow-and-workflows"
+	allowAdditionalIngressToSonataflow        = "allow-additional-ingress-to-sonataflow-and-workflows"
+	allowEgressFromSonataflowWorkflows        = "allow-egress-from-sonataflow-and-workflows"
+	dnsNamespace                              = "openshift-dns"
 )
 
 var (
@@ -31,38 +41,21 @@ var (
 		allowMonitoringToSonataflowWorkflows,
 		allowServerlessLogicToSonataFlowWorkflows,
 	}
-	allErrors = make(map[string]error)
 )
 
-// handleNetworkPolicy performs the retrieval, creation and reconciling of network policy.
-// It returns an error if any occurs during retrieval, creation or reconciliation.
+// handleNetworkPolicy performs the retrieval, creation and reconciling of network policy, and deletes the network
+// policies of owner that are not desired anymore from the workflow namespace and the previously applied namespaces.
+// It returns an aggregate of the errors that occur during retrieval, creation, reconciliation or deletion.
 func handleNetworkPolicy(client client.Client, ctx context.Context,
-	networkAndServerlessWorkflowNamespace, rhdhNamespace, databaseNamespace string, monitoringFlag bool) map[string]error {
+	networkAndServerlessWorkflowNamespace, rhdhNamespace, databaseNamespace string, monitoringFlag bool,
+	networkPolicies orchestratorv1alpha3.NetworkPolicies, appliedNamespaces []string, owner kubeoperations.Owner) error {
 	npLogger := log.FromContext(ctx)
 
-	for _, NetworkPolicyName := range NetworkPoliciesList {
-
-		if !monitoringFlag && (NetworkPolicyName == allowMonitoringToSonataflowWorkflows) {
-			continue
-		}
-
-		// Define the desired NetworkPolicy
-		desiredNP := &networkingv1.NetworkPolicy{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      NetworkPolicyName,
-				Namespace: networkAndServerlessWorkflowNamespace,
-				Labels:    kubeoperations.GetOrchestratorLabel(),
-			},
-			Spec: networkingv1.NetworkPolicySpec{
-				// This policy applies to all pods within the namespace where the policy is defined
-				PodSelector: metav1.LabelSelector{},
-				PolicyTypes: []networkingv1.PolicyType{
-					// This policy concerns traffic coming into the pods
-					networkingv1.PolicyTypeIngress,
-				},
-				Ingress: createIngress(NetworkPolicyName, networkAndServerlessWorkflowNamespace, rhdhNamespace, databaseNamespace),
-			},
-		}
+	var errorList []error
+	desiredNPs := getDesiredNetworkPolicies(networkAndServerlessWorkflowNamespace, rhdhNamespace, databaseNamespace, monitoringFlag,
+		networkPolicies, owner.Instance)
+	for _, desiredNP := range desiredNPs {
+		NetworkPolicyName := desiredNP.Name
 
 		existingNP := &networkingv1.NetworkPolicy{}
 		// get existing the networkPolicy
@@ -72,27 +65,135 @@ func handleNetworkPolicy(client client.Client, ctx context.Context,
 				// create network policy
 				if err := client.Create(ctx, desiredNP); err != nil {
 					npLogger.Error(err, "Error occurred when creating NetworkPolicy", "NP", NetworkPolicyName)
-					allErrors[NetworkPolicyName] = err
+					errorList = append(errorList, fmt.Errorf("failed to create network policy %s: %w", NetworkPolicyName, err))
 				}
 			} else {
 				// Pass along only actual errors
-				allErrors[NetworkPolicyName] = err
+				errorList = append(errorList, fmt.Errorf("failed to get network policy %s: %w", NetworkPolicyName, err))
 			}
 
 			continue
 		}
 
-		// Compare the current and desired state
-		if !reflect.DeepEqual(desiredNP.Spec, existingNP.Spec) {
+		// Compare the current and desired state; the policies created by previous versions are labelled with the
+		// instance
+		drifted := !reflect.DeepEqual(desiredNP.Spec, existingNP.Spec)
+		_, labelled := existingNP.Labels[kubeoperations.InstanceLabelKey]
+		if drifted || !labelled {
 			existingNP.Spec = desiredNP.Spec
+			existingNP.Labels = labels.Merge(desiredNP.Labels, existingNP.Labels)
 			if err := client.Update(ctx, existingNP); err != nil {
 				npLogger.Error(err, "Error occurred when updating NetworkPolicy", "NP", NetworkPolicyName)
-				allErrors[NetworkPolicyName] = err
+				errorList = append(errorList, fmt.Errorf("failed to update network policy %s: %w", NetworkPolicyName, err))
+				continue
 			}
+			if drifted {
+				orchestratormetrics.RecordDriftCorrection("NetworkPolicy")
+			}
+		}
+	}
+
+	namespaces := appendNamespace(appliedNamespaces, networkAndServerlessWorkflowNamespace)
+	errorList = append(errorList, deleteStaleNetworkPolicies(client, ctx, namespaces, desiredNPs, owner)...)
+
+	return errors.NewAggregate(errorList)
+}
+
+// deleteStaleNetworkPolicies deletes the network policies of owner in namespaces that are not desired, i.e. the
+// monitoring policy once monitoring is disabled or the policies of a previous workflow namespace. The policies of
+// other Orchestrators sharing the namespaces are kept.
+// It returns the errors that occur during listing or deletion.
+func deleteStaleNetworkPolicies(k8Client client.Client, ctx context.Context, namespaces []string, desiredNPs []*networkingv1.NetworkPolicy,
+	owner kubeoperations.Owner) []error {
+	npLogger := log.FromContext(ctx)
+
+	var errorList []error
+	desired := make(map[types.NamespacedName]bool, len(desiredNPs))
+	for _, desiredNP := range desiredNPs {
+		desired[types.NamespacedName{Name: desiredNP.Name, Namespace: desiredNP.Namespace}] = true
+	}
+
+	for _, namespace := range namespaces {
+		existingNPs := &networkingv1.NetworkPolicyList{}
+		err := k8Client.List(ctx, existingNPs, client.InNamespace(namespace), client.MatchingLabels(kubeoperations.GetOrchestratorLabel()))
+		if err != nil {
+			npLogger.Error(err, "Error occurred when listing NetworkPolicies", "NS", namespace)
+			errorList = append(errorList, fmt.Errorf("failed to list network policies in namespace %s: %w", namespace, err))
+			continue
+		}
+		for i := range existingNPs.Items {
+			existingNP := &existingNPs.Items[i]
+			if desired[types.NamespacedName{Name: existingNP.Name, Namespace: existingNP.Namespace}] || !owner.Owns(existingNP) {
+				continue
+			}
+			npLogger.Info("Deleting stale NetworkPolicy", "NP", existingNP.Name, "NS", existingNP.Namespace)
+			if err := k8Client.Delete(ctx, existingNP); err != nil && !apierrros.IsNotFound(err) {
+				npLogger.Error(err, "Error occurred when deleting NetworkPolicy", "NP", existingNP.Name, "NS", existingNP.Namespace)
+				errorList = append(errorList, fmt.Errorf("failed to delete network policy %s/%s: %w", existingNP.Namespace, existingNP.Name, err))
+			}
+		}
+	}
+	return errorList
+}
+
+// appendNamespace returns namespaces with namespace appended, unless it is already part of them.
+func appendNamespace(namespaces []string, namespace string) []string {
+	if slices.Contains(namespaces, namespace) {
+		return namespaces
+	}
+	return append(slices.Clone(namespaces), namespace)
+}
+
+// getDesiredNetworkPolicies returns the network policies of the workflow namespace: the built-in ingress policies,
+// the additional ingress policy and the egress policy when configured. No policy is returned when the network
+// policies are disabled in the Orchestrator. The policies are labelled with instance.
+func getDesiredNetworkPolicies(networkAndServerlessWorkflowNamespace, rhdhNamespace, databaseNamespace string, monitoringFlag bool,
+	networkPolicies orchestratorv1alpha3.NetworkPolicies, instance string) []*networkingv1.NetworkPolicy {
+	if networkPolicies.Disabled {
+		return nil
+	}
+
+	var desiredNPs []*networkingv1.NetworkPolicy
+	for _, NetworkPolicyName := range NetworkPoliciesList {
+
+		if !monitoringFlag && (NetworkPolicyName == allowMonitoringToSonataflowWorkflows) {
+			continue
 		}
+
+		desiredNPs = append(desiredNPs, newNetworkPolicy(NetworkPolicyName, networkAndServerlessWorkflowNamespace, instance,
+			networkingv1.PolicyTypeIngress,
+			createIngress(NetworkPolicyName, networkAndServerlessWorkflowNamespace, rhdhNamespace, databaseNamespace), nil))
+	}
+
+	if len(networkPolicies.AdditionalIngress) > 0 {
+		desiredNPs = append(desiredNPs, newNetworkPolicy(allowAdditionalIngressToSonataflow, networkAndServerlessWorkflowNamespace, instance,
+			networkingv1.PolicyTypeIngress, createIngressAdditionalPeers(networkPolicies.AdditionalIngress), nil))
 	}
 
-	return allErrors
+	if len(networkPolicies.Egress) > 0 {
+		desiredNPs = append(desiredNPs, newNetworkPolicy(allowEgressFromSonataflowWorkflows, networkAndServerlessWorkflowNamespace, instance,
+			networkingv1.PolicyTypeEgress, nil,
+			createEgressSonataflowWorkflows(networkAndServerlessWorkflowNamespace, rhdhNamespace, databaseNamespace, networkPolicies.Egress)))
+	}
+	return desiredNPs
+}
+
+func newNetworkPolicy(name, namespace, instance string, policyType networkingv1.PolicyType,
+	ingress []networkingv1.NetworkPolicyIngressRule, egress []networkingv1.NetworkPolicyEgressRule) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    kubeoperations.GetInstanceLabels(instance),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			// This policy applies to all pods within the namespace where the policy is defined
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: []networkingv1.PolicyType{policyType},
+			Ingress:     ingress,
+			Egress:      egress,
+		},
+	}
 }
 
 // A switch to create an Ingress for each network policy.
@@ -210,3 +311,45 @@ func createIngressServerlessLogicSonataFlowWorkflows() []networkingv1.NetworkPol
 	}
 	return Ingress
 }
+
+func createIngressAdditionalPeers(peers []networkingv1.NetworkPolicyPeer) []networkingv1.NetworkPolicyIngressRule {
+	Ingress := []networkingv1.NetworkPolicyIngressRule{
+		{
+			// Allows traffic from the peers configured in the Orchestrator
+			From: peers,
+		},
+	}
+	return Ingress
+}
+
+// createEgressSonataflowWorkflows returns the egress rules configured in the Orchestrator, preceded by a rule
+// keeping the traffic the platform depends on: DNS, the Knative, workflow, RHDH and database namespaces.
+func createEgressSonataflowWorkflows(networkAndServerlessWorkflowNamespace, rhdhNamespace, databaseNamespace string,
+	rules []orchestratorv1alpha3.NetworkPolicyEgressRule) []networkingv1.NetworkPolicyEgressRule {
+	platformPeers := createIngressRHDHSonataflowWorkflows(networkAndServerlessWorkflowNamespace, rhdhNamespace, databaseNamespace)[0].From
+	platformPeers = append(platformPeers, networkingv1.NetworkPolicyPeer{
+		// Allows name resolution through the cluster DNS
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				metaDataNameLabel: dnsNamespace,
+			},
+		},
+	})
+	Egress := []networkingv1.NetworkPolicyEgressRule{{To: platformPeers}}
+	for _, rule := range rules {
+		egressRule := networkingv1.NetworkPolicyEgressRule{To: rule.To}
+		for _, port := range rule.Ports {
+			protocol := corev1.Protocol(port.Protocol)
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+			egressRule.Ports = append(egressRule.Ports, networkingv1.NetworkPolicyPort{
+				Protocol: &protocol,
+				Port:     port.Port,
+				EndPort:  port.EndPort,
+			})
+		}
+		Egress = append(Egress, egressRule)
+	}
+	return Egress
+}
@@ -2,25 +2,35 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
 	kubeoperations "github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 const (
 	testNamespace         = "test-namespace"
 	testRHDHNamespace     = "test-rhdh-namespace"
 	testDatabaseNamespace = "test-db-namespace"
+	testInstance          = "test-instance"
 )
 
+var testOwner = kubeoperations.Owner{Instance: testInstance}
+
 var objects []client.Object
 
 func TestHandleNetworkPolicy(t *testing.T) {
@@ -36,7 +46,6 @@ func TestHandleNetworkPolicy(t *testing.T) {
 		monitoringFlag   bool
 		expectCreate     bool
 		expectUpdate     bool
-		errorMap         map[string]error
 	}{
 		{
 			name:             "Creates new policies when they don't exist",
@@ -44,7 +53,6 @@ func TestHandleNetworkPolicy(t *testing.T) {
 			monitoringFlag:   false,
 			expectCreate:     true,
 			expectUpdate:     false,
-			errorMap:         map[string]error{},
 		},
 		{
 			name:             "Creates new policies when they don't exist, with monitoring",
@@ -52,7 +60,6 @@ func TestHandleNetworkPolicy(t *testing.T) {
 			monitoringFlag:   true,
 			expectCreate:     true,
 			expectUpdate:     false,
-			errorMap:         map[string]error{},
 		},
 		{
 			name: "Updates existing policies",
@@ -89,7 +96,6 @@ func TestHandleNetworkPolicy(t *testing.T) {
 			monitoringFlag: false,
 			expectCreate:   false,
 			expectUpdate:   true,
-			errorMap:       map[string]error{},
 		},
 	}
 
@@ -104,11 +110,14 @@ func TestHandleNetworkPolicy(t *testing.T) {
 				fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
 
 				// Call handler to Create the Network Policies
-				errors := handleNetworkPolicy(fakeClient, ctx, testNamespace, testRHDHNamespace, testDatabaseNamespace, tc.monitoringFlag)
+				err := handleNetworkPolicy(fakeClient, ctx, testNamespace, testRHDHNamespace, testDatabaseNamespace, tc.monitoringFlag, orchestratorv1alpha3.NetworkPolicies{}, nil, testOwner)
+
+				assert.NoError(t, err)
 
 				// Verify that the fake client is populated with policies after calling the handler
-				err := fakeClient.Get(ctx, types.NamespacedName{Name: allowRHDHToSonataflowWorkflows, Namespace: testNamespace}, existingNP)
+				err = fakeClient.Get(ctx, types.NamespacedName{Name: allowRHDHToSonataflowWorkflows, Namespace: testNamespace}, existingNP)
 				assert.NoError(t, err)
+				assert.Equal(t, kubeoperations.GetInstanceLabels(testInstance), existingNP.Labels)
 				err = fakeClient.Get(ctx, types.NamespacedName{Name: allowIntraNamespace, Namespace: testNamespace}, existingNP)
 				assert.NoError(t, err)
 				err = fakeClient.Get(ctx, types.NamespacedName{Name: allowServerlessLogicToSonataFlowWorkflows, Namespace: testNamespace}, existingNP)
@@ -118,7 +127,6 @@ func TestHandleNetworkPolicy(t *testing.T) {
 					err = fakeClient.Get(ctx, types.NamespacedName{Name: allowMonitoringToSonataflowWorkflows, Namespace: testNamespace}, existingNP)
 					assert.NoError(t, err)
 				}
-				assert.Equal(t, tc.errorMap, errors)
 
 				// Flow for test cases that expect updating existing Policies
 			} else if tc.expectUpdate {
@@ -136,16 +144,199 @@ func TestHandleNetworkPolicy(t *testing.T) {
 				assert.NoError(t, err)
 
 				// Call handler to update the Ingress
-				errors := handleNetworkPolicy(fakeClient, ctx, testNamespace, testRHDHNamespace, testDatabaseNamespace, tc.monitoringFlag)
-				assert.Equal(t, tc.errorMap, errors)
+				err = handleNetworkPolicy(fakeClient, ctx, testNamespace, testRHDHNamespace, testDatabaseNamespace, tc.monitoringFlag, orchestratorv1alpha3.NetworkPolicies{}, nil, testOwner)
+				assert.NoError(t, err)
 				err = fakeClient.Get(ctx, types.NamespacedName{Name: allowRHDHToSonataflowWorkflows, Namespace: testNamespace}, existingNP)
 				assert.NoError(t, err)
 				assert.NotEqual(t, tc.existingPolicies[len(tc.existingPolicies)-1].Spec.Ingress, existingNP)
+				// the policies created by previous versions are labelled with the instance
+				assert.Equal(t, kubeoperations.GetInstanceLabels(testInstance), existingNP.Labels)
 			}
 		})
 	}
 }
 
+func TestHandleNetworkPolicyWithCustomRules(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(networkingv1.AddToScheme(scheme))
+
+	gatewayPeer := networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{metaDataNameLabel: "gateway"}},
+	}
+	httpsPort := intstr.FromInt32(443)
+	egressRule := orchestratorv1alpha3.NetworkPolicyEgressRule{
+		To:    []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/16"}}},
+		Ports: []orchestratorv1alpha3.NetworkPolicyPort{{Port: &httpsPort}},
+	}
+	networkPolicies := orchestratorv1alpha3.NetworkPolicies{
+		AdditionalIngress: []networkingv1.NetworkPolicyPeer{gatewayPeer},
+		Egress:            []orchestratorv1alpha3.NetworkPolicyEgressRule{egressRule},
+	}
+	// the additional ingress policy drifted from the Orchestrator
+	driftedNP := newNetworkPolicy(allowAdditionalIngressToSonataflow, testNamespace, testInstance, networkingv1.PolicyTypeIngress,
+		[]networkingv1.NetworkPolicyIngressRule{{}}, nil)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(driftedNP).Build()
+
+	err := handleNetworkPolicy(fakeClient, ctx, testNamespace, testRHDHNamespace, testDatabaseNamespace, false, networkPolicies, nil, testOwner)
+	assert.NoError(t, err)
+
+	ingressNP := &networkingv1.NetworkPolicy{}
+	err = fakeClient.Get(ctx, types.NamespacedName{Name: allowAdditionalIngressToSonataflow, Namespace: testNamespace}, ingressNP)
+	assert.NoError(t, err)
+	assert.Equal(t, []networkingv1.NetworkPolicyIngressRule{{From: []networkingv1.NetworkPolicyPeer{gatewayPeer}}}, ingressNP.Spec.Ingress)
+
+	egressNP := &networkingv1.NetworkPolicy{}
+	err = fakeClient.Get(ctx, types.NamespacedName{Name: allowEgressFromSonataflowWorkflows, Namespace: testNamespace}, egressNP)
+	assert.NoError(t, err)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}, egressNP.Spec.PolicyTypes)
+	assert.Len(t, egressNP.Spec.Egress, 2)
+	assert.Contains(t, egressNP.Spec.Egress[0].To, networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{metaDataNameLabel: testDatabaseNamespace}},
+	})
+	tcp := corev1.ProtocolTCP
+	assert.Equal(t, networkingv1.NetworkPolicyEgressRule{
+		To:    egressRule.To,
+		Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &httpsPort}},
+	}, egressNP.Spec.Egress[1])
+}
+
+func TestGetDesiredNetworkPoliciesDisabled(t *testing.T) {
+	networkPolicies := orchestratorv1alpha3.NetworkPolicies{
+		Disabled: true,
+		Egress:   []orchestratorv1alpha3.NetworkPolicyEgressRule{{}},
+	}
+	assert.Empty(t, getDesiredNetworkPolicies(testNamespace, testRHDHNamespace, testDatabaseNamespace, true, networkPolicies, testInstance))
+}
+
+func TestHandleNetworkPolicyDeletesStalePolicies(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(networkingv1.AddToScheme(scheme))
+
+	const previousNamespace = "test-previous-namespace"
+	newPolicy := func(name, namespace string) *networkingv1.NetworkPolicy {
+		return newNetworkPolicy(name, namespace, testInstance, networkingv1.PolicyTypeIngress, nil, nil)
+	}
+	// the policies of another Orchestrator sharing the workflow namespace are not deleted
+	otherNP := newNetworkPolicy(allowEgressFromSonataflowWorkflows, testNamespace, "other-instance", networkingv1.PolicyTypeEgress, nil, nil)
+	unmanagedNP := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: testNamespace},
+	}
+
+	testCases := []struct {
+		name              string
+		networkPolicies   orchestratorv1alpha3.NetworkPolicies
+		appliedNamespaces []string
+		expectedDeleted   []types.NamespacedName
+		expectedKept      []types.NamespacedName
+	}{
+		{
+			name:              "Deletes the monitoring policy when monitoring is disabled",
+			appliedNamespaces: []string{testNamespace},
+			expectedDeleted: []types.NamespacedName{
+				{Name: allowMonitoringToSonataflowWorkflows, Namespace: testNamespace},
+			},
+			expectedKept: []types.NamespacedName{
+				{Name: allowIntraNamespace, Namespace: testNamespace},
+				{Name: "unmanaged", Namespace: testNamespace},
+				{Name: allowEgressFromSonataflowWorkflows, Namespace: testNamespace},
+				{Name: allowIntraNamespace, Namespace: previousNamespace},
+			},
+		},
+		{
+			name:              "Deletes the policies of the previous namespace",
+			appliedNamespaces: []string{previousNamespace},
+			expectedDeleted: []types.NamespacedName{
+				{Name: allowIntraNamespace, Namespace: previousNamespace},
+				{Name: allowMonitoringToSonataflowWorkflows, Namespace: testNamespace},
+			},
+			expectedKept: []types.NamespacedName{
+				{Name: allowIntraNamespace, Namespace: testNamespace},
+			},
+		},
+		{
+			name:              "Deletes all the policies when network policies are disabled",
+			networkPolicies:   orchestratorv1alpha3.NetworkPolicies{Disabled: true},
+			appliedNamespaces: []string{testNamespace},
+			expectedDeleted: []types.NamespacedName{
+				{Name: allowIntraNamespace, Namespace: testNamespace},
+				{Name: allowMonitoringToSonataflowWorkflows, Namespace: testNamespace},
+			},
+			expectedKept: []types.NamespacedName{
+				{Name: "unmanaged", Namespace: testNamespace},
+				{Name: allowEgressFromSonataflowWorkflows, Namespace: testNamespace},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				newPolicy(allowIntraNamespace, testNamespace),
+				newPolicy(allowMonitoringToSonataflowWorkflows, testNamespace),
+				newPolicy(allowIntraNamespace, previousNamespace),
+				unmanagedNP.DeepCopy(),
+				otherNP.DeepCopy(),
+			).Build()
+
+			err := handleNetworkPolicy(fakeClient, ctx, testNamespace, testRHDHNamespace, testDatabaseNamespace, false,
+				tc.networkPolicies, tc.appliedNamespaces, testOwner)
+			assert.NoError(t, err)
+
+			for _, name := range tc.expectedDeleted {
+				err := fakeClient.Get(ctx, name, &networkingv1.NetworkPolicy{})
+				assert.True(t, apierrors.IsNotFound(err), "expected %s to be deleted", name)
+			}
+			for _, name := range tc.expectedKept {
+				assert.NoError(t, fakeClient.Get(ctx, name, &networkingv1.NetworkPolicy{}))
+			}
+		})
+	}
+}
+
+func TestHandleNetworkPolicyConcurrently(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(networkingv1.AddToScheme(scheme))
+
+	// the creation of network policies fails in the failing namespace only
+	const failingNamespace = "test-failing-namespace"
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if obj.GetNamespace() == failingNamespace {
+				return fmt.Errorf("creation of %s is forbidden", obj.GetName())
+			}
+			return client.Create(ctx, obj, opts...)
+		},
+	}).Build()
+
+	namespaces := []string{testNamespace, failingNamespace, testNamespace + "-2", testNamespace + "-3"}
+	errs := make([]error, len(namespaces))
+	var wg sync.WaitGroup
+	for i, namespace := range namespaces {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = handleNetworkPolicy(fakeClient, ctx, namespace, testRHDHNamespace, testDatabaseNamespace, true,
+				orchestratorv1alpha3.NetworkPolicies{}, nil, testOwner)
+		}()
+	}
+	wg.Wait()
+
+	for i, namespace := range namespaces {
+		if namespace == failingNamespace {
+			assert.ErrorContains(t, errs[i], "creation of "+allowIntraNamespace+" is forbidden")
+			continue
+		}
+		assert.NoError(t, errs[i], namespace)
+	}
+
+	// the errors of a previous reconciliation are not reported again
+	assert.NoError(t, handleNetworkPolicy(fakeClient, ctx, testNamespace, testRHDHNamespace, testDatabaseNamespace, true,
+		orchestratorv1alpha3.NetworkPolicies{}, nil, testOwner))
+}
+
 func TestCreateIngressSwitch(t *testing.T) {
 	// Create a fake client scheme
 	scheme := runtime.NewScheme()
@@ -19,7 +19,6 @@ package controller
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
@@ -32,6 +31,7 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -41,8 +41,9 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	olmclientset "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned"
-	orchestratorv1alpha2 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
 	orchestratorgitops "github.com/rhdhorchestrator/orchestrator-operator/internal/controller/gitops"
+	orchestratormetrics "github.com/rhdhorchestrator/orchestrator-operator/internal/controller/metrics"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -50,11 +51,6 @@ import (
 )
 
 const (
-	// Definition to manage Orchestrator condition status.
-	TypeAvailable string = "Available"
-	TypeCompleted string = "Completed"
-	TypeDegrading string = "Degrading"
-
 	// Finalizer Definition
 	FinalizerCRCleanup = "rhdh.redhat.com/orchestrator-cleanup"
 
@@ -74,16 +70,18 @@ type OrchestratorReconciler struct {
 //+kubebuilder:rbac:groups=rhdh.redhat.com,resources=orchestrators/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=secrets;configmaps;namespaces;events,verbs=list;get;create;delete;patch;watch;update
+//+kubebuilder:rbac:groups=core,resources=services;endpoints,verbs=get;list;watch
 //+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 //+kubebuilder:rbac:groups=operators.coreos.com,resources=subscriptions;operatorgroups;clusterserviceversions;catalogsources;installplans,verbs=get;list;watch;create;delete;patch;update
 //+kubebuilder:rbac:groups=sonataflow.org,resources=sonataflows;sonataflowclusterplatforms;sonataflowplatforms,verbs=get;list;watch;create;delete;patch;update
 //+kubebuilder:rbac:groups=operator.knative.dev,resources=knativeeventings;knativeservings,verbs=get;list;watch;create;delete;patch;update
+//+kubebuilder:rbac:groups=eventing.knative.dev,resources=brokers,verbs=get;list;watch;create;delete;patch;update
 //+kubebuilder:rbac:groups=rhdh.redhat.com,resources=backstages,verbs=get;list;create;delete;patch;watch;update
 //+kubebuilder:rbac:groups=config.openshift.io,resources=ingresses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses;networkpolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=tekton.dev,resources=pipelines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=tekton.dev,resources=tasks,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=argoproj.io,resources=appprojects,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=argoproj.io,resources=appprojects;applications,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -101,7 +99,7 @@ func (r *OrchestratorReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// Fetch the Orchestrator instance
 	// The purpose is to check if the Custom Resource for the Kind Orchestrator
 	// is applied on the cluster if not we return nil to stop the reconciliation
-	orchestrator := &orchestratorv1alpha2.Orchestrator{}
+	orchestrator := &orchestratorv1alpha3.Orchestrator{}
 
 	err := r.Get(ctx, req.NamespacedName, orchestrator) // Lookup the Orchestrator instance for this reconcile request
 	if err != nil {
@@ -126,7 +124,9 @@ func (r *OrchestratorReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		if err := r.Update(ctx, orchestrator); err != nil {
 			return ctrl.Result{}, err
 		}
+		orchestratormetrics.DeleteOrchestratorMetrics(orchestrator.Namespace, orchestrator.Name)
 		logger.Info("Successfully removed Orchestrator Custom Resource")
+		return ctrl.Result{}, nil
 	}
 
 	// Add finalizer if not present
@@ -135,14 +135,17 @@ func (r *OrchestratorReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	// Set the status to Unknown when no status is available - usually initial reconciliation.
-	if orchestrator.Status.Conditions == nil || len(orchestrator.Status.Conditions) == 0 {
-		if err := r.UpdateStatus(ctx, orchestrator, orchestratorv1alpha2.RunningPhase, metav1.Condition{
-			Type:               TypeAvailable,
+	if len(orchestrator.Status.Conditions) == 0 {
+		meta.SetStatusCondition(&orchestrator.Status.Conditions, metav1.Condition{
+			Type:               TypeReady,
 			Status:             metav1.ConditionUnknown,
-			Reason:             "Reconciling",
+			Reason:             ReasonReconciling,
 			Message:            "Starting Reconciliation",
-			LastTransitionTime: metav1.Now(),
-		}); err != nil {
+			ObservedGeneration: orchestrator.Generation,
+		})
+		orchestrator.Status.Phase = orchestratorv1alpha3.RunningPhase
+		if err := r.Status().Update(ctx, orchestrator); err != nil {
+			logger.Error(err, "Failed to update Orchestrator status")
 			return ctrl.Result{}, err
 		}
 		// Re-fetch orchestrator Custom Resource after updating the status
@@ -152,105 +155,118 @@ func (r *OrchestratorReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		}
 	}
 
-	argoCDEnabled := orchestrator.Spec.ArgoCd.Enabled
-	tektonEnabled := orchestrator.Spec.Tekton.Enabled
-	serverlessWorkflowNamespace := orchestrator.Spec.PlatformConfig.Namespace
+	// the InstallPlans waiting for approval and the operator statuses are collected again by the subsystems
+	orchestrator.Status.PendingInstallPlans = nil
+	orchestrator.Status.Operators = nil
 
-	// handle serverless logic
-	if err := r.reconcileServerlessLogic(ctx, orchestrator); err != nil {
-		if apierrors.IsNotFound(err) {
-			return ctrl.Result{Requeue: true, RequeueAfter: RequeueAfterTime}, nil
+	// the operators whose installation was switched off are uninstalled before the subsystems are reconciled
+	var reconcileErrors []error
+	uninstalled, err := r.uninstallDisabledOperators(ctx, orchestrator)
+	if err != nil {
+		logger.Error(err, "Error occurred when uninstalling disabled operators")
+		reconcileErrors = append(reconcileErrors, err)
+	}
+	setOperatorsUninstalledCondition(orchestrator, uninstalled, err)
+
+	// the operators installed outside of the orchestrator are detected from one listing shared by the subsystems
+	installations := kube.NewOperatorInstallations(r.Client, r.OLMClient)
+
+	// Each subsystem is reconciled independently and reports its outcome in its own condition,
+	// so that a failure in one subsystem does not hide the state of the others.
+	subsystems := []struct {
+		conditionType string
+		name          string
+		enabled       bool
+		reconcile     func() error
+	}{
+		{
+			conditionType: TypeServerlessLogicReady,
+			name:          "Serverless Logic",
+			enabled:       orchestrator.Spec.ServerlessLogicOperator.InstallOperator,
+			reconcile:     func() error { return r.reconcileServerlessLogic(ctx, orchestrator, installations) },
+		},
+		{
+			conditionType: TypeKnativeReady,
+			name:          "K-Native Serverless",
+			enabled:       orchestrator.Spec.ServerlessOperator.InstallOperator,
+			reconcile:     func() error { return r.reconcileKnative(ctx, orchestrator, installations) },
+		},
+		{
+			conditionType: TypeRHDHReady,
+			name:          "RHDH",
+			enabled:       true, // an existing RHDH instance is configured when the operator is not installed
+			reconcile:     func() error { return r.reconcileRHDH(ctx, orchestrator, installations) },
+		},
+		{
+			conditionType: TypeNetworkPoliciesReady,
+			name:          "Network Policies",
+			enabled:       !orchestrator.Spec.PlatformConfig.NetworkPolicies.Disabled,
+			reconcile:     func() error { return r.reconcileNetworkPolicy(ctx, orchestrator) },
+		},
+		{
+			conditionType: TypeArgoCDReady,
+			name:          "ArgoCD",
+			enabled:       orchestrator.Spec.ArgoCd.Enabled,
+			reconcile:     func() error { return r.reconcileArgoCD(ctx, orchestrator) },
+		},
+		{
+			conditionType: TypeTektonReady,
+			name:          "Tekton",
+			enabled:       orchestrator.Spec.Tekton.Enabled,
+			reconcile:     func() error { return r.reconcileTekton(ctx, orchestrator) },
+		},
+		{
+			conditionType: TypePostgresReachable,
+			name:          "PostgreSQL",
+			enabled:       true,
+			reconcile:     func() error { return r.reconcilePostgres(ctx, orchestrator) },
+		},
+	}
+
+	waitingForDependency := false
+	for _, subsystem := range subsystems {
+		start := time.Now()
+		err := subsystem.reconcile()
+		orchestratormetrics.ObserveReconcileDuration(subsystem.name, start)
+		setSubsystemCondition(orchestrator, subsystem.conditionType, subsystem.name, subsystem.enabled, err)
+		orchestratormetrics.SetSubsystemReady(orchestrator.Namespace, orchestrator.Name, subsystem.name,
+			meta.IsStatusConditionTrue(orchestrator.Status.Conditions, subsystem.conditionType))
+		if err == nil {
+			continue
 		}
-		logger.Error(err, "Error occurred when installing Serverless Logic resources")
-		_ = r.UpdateStatus(ctx, orchestrator, orchestratorv1alpha2.FailedPhase, metav1.Condition{
-			Type:               TypeDegrading,
-			Status:             metav1.ConditionFalse,
-			Reason:             "ReconcilingOSLResourcesFailed",
-			Message:            err.Error(),
-			LastTransitionTime: metav1.Now(),
-		})
-		return ctrl.Result{RequeueAfter: RequeueAfterTime}, err
-	}
-
-	// handle knative
-	serverlessOperator := orchestrator.Spec.ServerlessOperator
-	if err := r.reconcileKnative(ctx, serverlessOperator); err != nil {
-		if apierrors.IsNotFound(err) {
-			return ctrl.Result{Requeue: true, RequeueAfter: RequeueAfterTime}, nil
+		if isWaitingForDependency(err) {
+			logger.Info("Subsystem is waiting for a dependency", "Subsystem", subsystem.name, "Reason", err.Error())
+			waitingForDependency = true
+			continue
 		}
-		logger.Error(err, "Error occurred when installing K-Native resources")
-		_ = r.UpdateStatus(ctx, orchestrator, orchestratorv1alpha2.FailedPhase, metav1.Condition{
-			Type:               TypeDegrading,
-			Status:             metav1.ConditionFalse,
-			Reason:             "ReconcilingKNativeResourcesFailed",
-			Message:            err.Error(),
-			LastTransitionTime: metav1.Now(),
-		})
-		return ctrl.Result{RequeueAfter: RequeueAfterTime}, err
+		logger.Error(err, "Error occurred when reconciling subsystem", "Subsystem", subsystem.name)
+		reconcileErrors = append(reconcileErrors, fmt.Errorf("%s: %w", subsystem.name, err))
 	}
 
-	// handle RHDH
-	rhdhConfig := orchestrator.Spec.RHDHConfig
-	if err := r.reconcileRHDH(ctx, serverlessWorkflowNamespace, argoCDEnabled, tektonEnabled, rhdhConfig); err != nil {
-		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
-			return ctrl.Result{Requeue: true, RequeueAfter: RequeueAfterTime}, nil
-		}
-		logger.Error(err, "Error occurred when creating RHDH resources")
-		_ = r.UpdateStatus(ctx, orchestrator, orchestratorv1alpha2.FailedPhase, metav1.Condition{
-			Type:               TypeDegrading,
-			Status:             metav1.ConditionFalse,
-			Reason:             "ReconcilingRHDHResourcesFailed",
-			Message:            err.Error(),
-			LastTransitionTime: metav1.Now(),
-		})
-		return ctrl.Result{Requeue: true, RequeueAfter: RequeueAfterTime}, err
-	}
+	plan, err := r.getCleanUpPlan(ctx, orchestrator)
+	deleted, orphaned := describeCleanUpPlan(r.Client, plan)
+	setDeletionPlannedCondition(orchestrator, deleted, orphaned, err)
 
-	// handle network policies
-	if err := r.reconcileNetworkPolicy(ctx, orchestrator); err != nil {
-		if apierrors.IsNotFound(err) {
-			return ctrl.Result{Requeue: true, RequeueAfter: RequeueAfterTime}, nil
-		}
-		logger.Error(err, "Error occurred when installing NetworkPolicy")
-		_ = r.UpdateStatus(ctx, orchestrator, orchestratorv1alpha2.FailedPhase, metav1.Condition{
-			Type:               TypeDegrading,
-			Status:             metav1.ConditionFalse,
-			Reason:             "ReconcilingNetworkPolicyFailed",
-			Message:            err.Error(),
-			LastTransitionTime: metav1.Now(),
-		})
-		return ctrl.Result{Requeue: true, RequeueAfter: RequeueAfterTime}, err
-	}
+	orchestratormetrics.PendingInstallPlans.WithLabelValues(orchestrator.Namespace, orchestrator.Name).Set(float64(len(orchestrator.Status.PendingInstallPlans)))
 
-	// handle gitops
-	if err := r.reconcileGitOps(ctx, orchestrator); err != nil {
-		if apierrors.IsNotFound(err) {
-			return ctrl.Result{Requeue: true, RequeueAfter: RequeueAfterTime}, nil
-		}
-		logger.Error(err, "Error occurred when installing GitOps")
-		_ = r.UpdateStatus(ctx, orchestrator, orchestratorv1alpha2.FailedPhase, metav1.Condition{
-			Type:               TypeDegrading,
-			Status:             metav1.ConditionFalse,
-			Reason:             "ReconcilingGitOpsFailed",
-			Message:            err.Error(),
-			LastTransitionTime: metav1.Now(),
-		})
-		return ctrl.Result{Requeue: true, RequeueAfter: RequeueAfterTime}, err
+	if err := r.UpdateStatus(ctx, orchestrator); err != nil {
+		return ctrl.Result{RequeueAfter: RequeueAfterTime}, err
 	}
 
-	_ = r.UpdateStatus(ctx, orchestrator, orchestratorv1alpha2.CompletedPhase, metav1.Condition{
-		Type:               TypeCompleted,
-		Status:             metav1.ConditionTrue,
-		Reason:             "ReconciliationCompleted",
-		Message:            "Reconciliation has completed",
-		LastTransitionTime: metav1.Now(),
-	})
+	if len(reconcileErrors) > 0 {
+		return ctrl.Result{RequeueAfter: RequeueAfterTime}, utilerrors.NewAggregate(reconcileErrors)
+	}
+	if waitingForDependency {
+		return ctrl.Result{Requeue: true, RequeueAfter: RequeueAfterTime}, nil
+	}
+	orchestratormetrics.LastSuccessfulReconcile.WithLabelValues(orchestrator.Namespace, orchestrator.Name).SetToCurrentTime()
 	return ctrl.Result{}, nil
 }
 
 func (r *OrchestratorReconciler) reconcileServerlessLogic(
 	ctx context.Context,
-	orchestrator *orchestratorv1alpha2.Orchestrator) error {
+	orchestrator *orchestratorv1alpha3.Orchestrator,
+	installations *kube.OperatorInstallations) error {
 
 	sfLogger := log.FromContext(ctx)
 	sfLogger.Info("Starting reconciliation for Serverless Logic")
@@ -258,16 +274,17 @@ func (r *OrchestratorReconciler) reconcileServerlessLogic(
 
 	serverlessWorkflowNamespace := orchestrator.Spec.PlatformConfig.Namespace
 
-	// check if install operator is disabled and handle clean up if necessary
+	// the operator is uninstalled by uninstallDisabledOperators when its installation is disabled
 	if !serverlessLogicOperator.InstallOperator {
-		sfLogger.Info("Operator is disabled. Handle Clean up process if necessary")
+		sfLogger.Info("Operator is disabled")
+		r.recordUnmanagedOperator(ctx, orchestrator, installations, serverlessLogicSubscriptionName, serverlessLogicOperatorNamespace)
 		return nil
 	}
 	// Subscription is enabled; check namespace exist
 	if _, err := kube.CheckNamespaceExist(ctx, r.Client, serverlessWorkflowNamespace); err != nil {
 		if apierrors.IsNotFound(err) {
 			sfLogger.Info("Creating namespace", "NS", serverlessWorkflowNamespace)
-			if err := kube.CreateNamespace(ctx, r.Client, serverlessWorkflowNamespace); err != nil {
+			if err := kube.CreateInstanceNamespace(ctx, r.Client, serverlessWorkflowNamespace, kube.GetInstance(orchestrator)); err != nil {
 				sfLogger.Error(err, "Error occurred when creating namespace", "NS", serverlessWorkflowNamespace)
 				return err
 			}
@@ -276,10 +293,25 @@ func (r *OrchestratorReconciler) reconcileServerlessLogic(
 		return err
 	}
 
-	if err := handleServerlessLogicOperatorInstallation(ctx, r.Client, r.OLMClient); err != nil {
-		sfLogger.Error(err, "Error occurred when installing OSL Operator resources")
+	// the operator is not installed again when it was installed outside of the orchestrator
+	detected, err := r.detectOperator(ctx, orchestrator, installations, serverlessLogicSubscriptionName, serverlessLogicOperatorNamespace)
+	if err != nil {
 		return err
 	}
+	if !detected {
+		pendingInstallPlan, err := handleServerlessLogicOperatorInstallation(
+			ctx, r.Client, r.OLMClient, serverlessLogicOperator.Subscription, getApprovedCSV(orchestrator))
+		if err != nil {
+			sfLogger.Error(err, "Error occurred when installing OSL Operator resources")
+			return err
+		}
+		recordPendingInstallPlan(orchestrator, pendingInstallPlan)
+
+		// operator is installed; check its CSV succeeded
+		if err := r.checkOperatorStatus(ctx, orchestrator, serverlessLogicSubscriptionName, serverlessLogicOperatorNamespace); err != nil {
+			return err
+		}
+	}
 
 	// subscription exists; check if CRD exists;
 	sonataFlowClusterPlatformCRD := &apiextensionsv1.CustomResourceDefinition{}
@@ -301,24 +333,45 @@ func (r *OrchestratorReconciler) reconcileServerlessLogic(
 	return nil
 }
 
-func (r *OrchestratorReconciler) reconcileKnative(ctx context.Context, serverlessOperator orchestratorv1alpha2.ServerlessOperator) error {
+func (r *OrchestratorReconciler) reconcileKnative(
+	ctx context.Context,
+	orchestrator *orchestratorv1alpha3.Orchestrator,
+	installations *kube.OperatorInstallations) error {
+
 	knativeLogger := log.FromContext(ctx)
 	knativeLogger.Info("Starting Reconciliation for K-Native Serverless")
+	serverlessOperator := orchestrator.Spec.ServerlessOperator
 
-	// if subscription is disabled; check if subscription exists and handle delete
+	// the operator is uninstalled by uninstallDisabledOperators when its installation is disabled
 	if !serverlessOperator.InstallOperator {
-		knativeLogger.Info("Operator is disabled. Handle Clean up process if necessary")
+		knativeLogger.Info("Operator is disabled")
+		r.recordUnmanagedOperator(ctx, orchestrator, installations, knative.KnativeSubscriptionName, knative.KnativeOperatorNamespace)
 		return nil
 	}
 
-	// Subscription is enabled;
-	if err := knative.HandleKNativeOperatorInstallation(ctx, r.Client, r.OLMClient); err != nil {
-		knativeLogger.Error(err, "Error occurred when installing Knative Operator resources")
+	// the operator is not installed again when it was installed outside of the orchestrator
+	detected, err := r.detectOperator(ctx, orchestrator, installations, knative.KnativeSubscriptionName, knative.KnativeOperatorNamespace)
+	if err != nil {
 		return err
 	}
+	if !detected {
+		// Subscription is enabled;
+		pendingInstallPlan, err := knative.HandleKNativeOperatorInstallation(
+			ctx, r.Client, r.OLMClient, serverlessOperator.Subscription, getApprovedCSV(orchestrator))
+		if err != nil {
+			knativeLogger.Error(err, "Error occurred when installing Knative Operator resources")
+			return err
+		}
+		recordPendingInstallPlan(orchestrator, pendingInstallPlan)
+
+		// operator is installed; check its CSV succeeded
+		if err := r.checkOperatorStatus(ctx, orchestrator, knative.KnativeSubscriptionName, knative.KnativeOperatorNamespace); err != nil {
+			return err
+		}
+	}
 
 	// handle knative CRs
-	if err := knative.HandleKnativeCR(ctx, r.Client); err != nil {
+	if err := knative.HandleKnativeCR(ctx, r.Client, orchestrator.Spec.ServerlessOperator.Knative); err != nil {
 		knativeLogger.Error(err, "Error occurred when handling Knative Custom Resources")
 		return err
 	}
@@ -327,25 +380,51 @@ func (r *OrchestratorReconciler) reconcileKnative(ctx context.Context, serverles
 }
 
 func (r *OrchestratorReconciler) reconcileRHDH(
-	ctx context.Context, serverlessWorkflowNamespace string,
-	argoCDEnabled, tektonEnabled bool,
-	rhdhConfig orchestratorv1alpha2.RHDHConfig) error {
+	ctx context.Context,
+	orchestrator *orchestratorv1alpha3.Orchestrator,
+	installations *kube.OperatorInstallations) error {
 
 	logger := log.FromContext(ctx)
 	logger.Info("Starting Reconciliation for RHDH")
 
+	rhdhConfig := orchestrator.Spec.RHDHConfig
+	serverlessWorkflowNamespace := orchestrator.Spec.PlatformConfig.Namespace
+	argoCDEnabled := orchestrator.Spec.ArgoCd.Enabled
+	tektonEnabled := orchestrator.Spec.Tekton.Enabled
 	namespace := rhdhConfig.Namespace
 
-	// if install operator is disabled; handle clean up
+	// if install operator is disabled; attach the orchestrator to the existing RHDH instance
 	if !rhdhConfig.InstallOperator {
-		logger.Info("Operator is disabled. Handle Clean up process if necessary")
+		logger.Info("Operator is disabled. Configuring the orchestrator plugins of the existing RHDH instance")
+		r.recordUnmanagedOperator(ctx, orchestrator, installations, rhdh.RHDHSubscriptionName, rhdh.RHDHOperatorNamespace)
+		conflicts, err := rhdh.HandleExistingRHDH(ctx, r.Client, serverlessWorkflowNamespace, argoCDEnabled, tektonEnabled, rhdhConfig)
+		if err != nil {
+			logger.Error(err, "Error occurred when configuring existing RHDH instance", "CR-Name", rhdhConfig.Name)
+			return err
+		}
+		setRHDHConfigSyncedCondition(orchestrator, conflicts)
 		return nil
 	}
 
-	if err := rhdh.HandleRHDHOperatorInstallation(ctx, r.Client, r.OLMClient); err != nil {
-		logger.Error(err, "Error occurred when installing RHDH Operator resources")
+	// the operator is not installed again when it was installed outside of the orchestrator
+	detected, err := r.detectOperator(ctx, orchestrator, installations, rhdh.RHDHSubscriptionName, rhdh.RHDHOperatorNamespace)
+	if err != nil {
 		return err
 	}
+	if !detected {
+		pendingInstallPlan, err := rhdh.HandleRHDHOperatorInstallation(
+			ctx, r.Client, r.OLMClient, rhdhConfig.Subscription, getApprovedCSV(orchestrator))
+		if err != nil {
+			logger.Error(err, "Error occurred when installing RHDH Operator resources")
+			return err
+		}
+		recordPendingInstallPlan(orchestrator, pendingInstallPlan)
+
+		// operator is installed; check its CSV succeeded
+		if err := r.checkOperatorStatus(ctx, orchestrator, rhdh.RHDHSubscriptionName, rhdh.RHDHOperatorNamespace); err != nil {
+			return err
+		}
+	}
 
 	// get cluster domain name
 	clusterDomain, err := r.getClusterDomain(ctx)
@@ -358,21 +437,204 @@ func (r *OrchestratorReconciler) reconcileRHDH(
 		return err
 	}
 
-	// create configmap
-	logger.Info("Creating configmap for RHDH CR...")
-	bsConfigMapList, err := rhdh.GetOrCreateConfigMaps(ctx, r.Client, clusterDomain, serverlessWorkflowNamespace, argoCDEnabled, tektonEnabled, rhdhConfig)
+	// create or update configmap
+	logger.Info("Reconciling configmaps for RHDH CR...")
+	bsConfigMapList, conflicts, err := rhdh.CreateOrUpdateConfigMaps(ctx, r.Client, clusterDomain, serverlessWorkflowNamespace, argoCDEnabled, tektonEnabled, rhdhConfig)
 	if err != nil {
 		return err
 	}
 	logger.Info("Configmap list", "CM-List", bsConfigMapList)
 
 	// handle RHDH CR
-	if err := rhdh.HandleRHDHCR(rhdhConfig, bsConfigMapList, ctx, r.Client); err != nil {
+	backstageConflicts, err := rhdh.HandleRHDHCR(rhdhConfig, bsConfigMapList, kube.GetInstance(orchestrator), ctx, r.Client)
+	if err != nil {
 		return err
 	}
+	setRHDHConfigSyncedCondition(orchestrator, append(conflicts, backstageConflicts...))
 	return nil
 }
 
+// getApprovedCSV returns the CSV whose InstallPlan was approved with the ApproveCSVAnnotation of the orchestrator.
+func getApprovedCSV(orchestrator *orchestratorv1alpha3.Orchestrator) string {
+	return orchestrator.Annotations[orchestratorv1alpha3.ApproveCSVAnnotation]
+}
+
+// recordPendingInstallPlan adds the InstallPlan left waiting for approval to the status of the orchestrator.
+func recordPendingInstallPlan(orchestrator *orchestratorv1alpha3.Orchestrator, pendingInstallPlan *orchestratorv1alpha3.PendingInstallPlan) {
+	if pendingInstallPlan == nil {
+		return
+	}
+	orchestrator.Status.PendingInstallPlans = append(orchestrator.Status.PendingInstallPlans, *pendingInstallPlan)
+}
+
+// checkOperatorStatus records the status of the CSV installed by the Subscription of an operator in the status
+// of the orchestrator, and returns an error when the CSV has not succeeded so the operands are not created yet.
+func (r *OrchestratorReconciler) checkOperatorStatus(
+	ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator,
+	subscriptionName, namespace string) error {
+
+	operatorStatus, err := kube.GetOperatorStatus(ctx, r.OLMClient, subscriptionName, namespace)
+	operatorStatus.Ownership = orchestratorv1alpha3.OperatorManaged
+	orchestrator.Status.Operators = append(orchestrator.Status.Operators, operatorStatus)
+	return err
+}
+
+// detectOperator records in the status an installation of the subscriptionName operator package made outside of
+// the orchestrator, in which case the operator is neither installed again nor managed. The returned error reports
+// a detected operator whose CSV has not succeeded.
+func (r *OrchestratorReconciler) detectOperator(
+	ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator, installations *kube.OperatorInstallations,
+	subscriptionName, namespace string) (bool, error) {
+
+	operatorStatus, err := installations.Detect(ctx, subscriptionName, subscriptionName, namespace)
+	if operatorStatus == nil {
+		return false, err
+	}
+	orchestrator.Status.Operators = append(orchestrator.Status.Operators, *operatorStatus)
+	return true, err
+}
+
+// recordUnmanagedOperator records in the status an installation of the subscriptionName operator package made
+// outside of the orchestrator while its installation is disabled. Its state does not affect the orchestrator.
+func (r *OrchestratorReconciler) recordUnmanagedOperator(
+	ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator, installations *kube.OperatorInstallations,
+	subscriptionName, namespace string) {
+
+	operatorStatus, err := installations.Detect(ctx, subscriptionName, subscriptionName, namespace)
+	if operatorStatus == nil {
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Error occurred when detecting operator", "SubscriptionName", subscriptionName)
+		}
+		return
+	}
+	operatorStatus.Ownership = orchestratorv1alpha3.OperatorUnmanaged
+	orchestrator.Status.Operators = append(orchestrator.Status.Operators, *operatorStatus)
+}
+
+// managedOperator describes an operator installed by the Orchestrator operator when installOperator is set.
+type managedOperator struct {
+	name              string
+	subscriptionName  string
+	namespace         string
+	operatorGroupName string
+	// installs returns whether orchestrator installs the operator
+	installs func(orchestrator *orchestratorv1alpha3.Orchestrator) bool
+	// listOperands returns the operands of the operator owned by owner
+	listOperands func(owner kube.Owner) ([]client.Object, error)
+	// deletionPolicy applied to the operands when the operator is uninstalled
+	deletionPolicy orchestratorv1alpha3.DeletionPolicy
+}
+
+// uninstallDisabledOperators uninstalls the operators whose installOperator flag is switched off. Like the clean up
+// of a deleted Orchestrator, the deletion policy of the subsystem of an operator applies to its operands, only the
+// objects created by the Orchestrator operator are removed, and an operator is kept while another Orchestrator
+// installs it. It returns the descriptions of the removed objects.
+func (r *OrchestratorReconciler) uninstallDisabledOperators(ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator) ([]string, error) {
+	operators := []managedOperator{
+		{
+			name:              "Serverless Logic",
+			subscriptionName:  serverlessLogicSubscriptionName,
+			namespace:         serverlessLogicOperatorNamespace,
+			operatorGroupName: serverlessOperatorGroupName,
+			installs: func(orchestrator *orchestratorv1alpha3.Orchestrator) bool {
+				return orchestrator.Spec.ServerlessLogicOperator.InstallOperator
+			},
+			listOperands: func(owner kube.Owner) ([]client.Object, error) {
+				return listServerlessLogicCleanUpObjects(ctx, r.Client, orchestrator.Spec.PlatformConfig.Namespace, owner)
+			},
+			deletionPolicy: orchestrator.Spec.DeletionPolicy.ServerlessLogic,
+		},
+		{
+			name:              "K-Native Serverless",
+			subscriptionName:  knative.KnativeSubscriptionName,
+			namespace:         knative.KnativeOperatorNamespace,
+			operatorGroupName: knative.KnativeOperatorGroupName,
+			installs: func(orchestrator *orchestratorv1alpha3.Orchestrator) bool {
+				return orchestrator.Spec.ServerlessOperator.InstallOperator
+			},
+			listOperands: func(owner kube.Owner) ([]client.Object, error) {
+				return knative.ListKnativeCleanUpObjects(ctx, r.Client, owner)
+			},
+			deletionPolicy: orchestrator.Spec.DeletionPolicy.Serverless,
+		},
+		{
+			name:              "RHDH",
+			subscriptionName:  rhdh.RHDHSubscriptionName,
+			namespace:         rhdh.RHDHOperatorNamespace,
+			operatorGroupName: rhdh.RHDHOperatorGroupName,
+			installs: func(orchestrator *orchestratorv1alpha3.Orchestrator) bool {
+				return orchestrator.Spec.RHDHConfig.InstallOperator
+			},
+			listOperands: func(owner kube.Owner) ([]client.Object, error) {
+				return rhdh.ListRHDHCleanUpObjects(ctx, r.Client, orchestrator.Spec.RHDHConfig.Namespace, owner)
+			},
+			deletionPolicy: orchestrator.Spec.DeletionPolicy.RHDH,
+		},
+	}
+
+	var removed []string
+	var errorList []error
+	for _, operator := range operators {
+		if operator.installs(orchestrator) {
+			continue
+		}
+		operatorRemoved, err := r.uninstallOperator(ctx, orchestrator, operator)
+		removed = append(removed, operatorRemoved...)
+		if err != nil {
+			errorList = append(errorList, fmt.Errorf("failed to uninstall the %s operator: %w", operator.name, err))
+		}
+	}
+	return removed, utilerrors.NewAggregate(errorList)
+}
+
+// uninstallOperator applies the deletion policy of operator to its operands, then deletes its Subscription, CSV and
+// OperatorGroup. The operator is only uninstalled when its Subscription was created by the Orchestrator operator
+// and no other Orchestrator installs it. The operands are kept by the Retain and Orphan policies.
+func (r *OrchestratorReconciler) uninstallOperator(
+	ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator, operator managedOperator) ([]string, error) {
+
+	logger := log.FromContext(ctx)
+
+	subscription, err := kube.GetManagedSubscription(ctx, r.OLMClient, operator.subscriptionName, operator.namespace)
+	if err != nil || subscription == nil {
+		return nil, err
+	}
+
+	orchestratorList := &orchestratorv1alpha3.OrchestratorList{}
+	if err := r.List(ctx, orchestratorList); err != nil {
+		logger.Error(err, "Error occurred when listing Orchestrators")
+		return nil, err
+	}
+	for i := range orchestratorList.Items {
+		other := &orchestratorList.Items[i]
+		if other.UID != orchestrator.UID && operator.installs(other) {
+			logger.Info("Keeping operator installed by another Orchestrator", "Operator", operator.name, "Orchestrator", other.Name)
+			return nil, nil
+		}
+	}
+
+	logger.Info("Uninstalling disabled operator", "Operator", operator.name, "DeletionPolicy", operator.deletionPolicy)
+	owner, err := r.getOwner(ctx, orchestrator)
+	if err != nil {
+		return nil, err
+	}
+	operands, err := operator.listOperands(owner)
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	if operator.deletionPolicy == orchestratorv1alpha3.DeletionPolicyDelete {
+		for _, operand := range operands {
+			removed = append(removed, kube.DescribeObject(r.Client, operand))
+		}
+	}
+	if err := kube.ApplyDeletionPolicy(ctx, r.Client, operator.deletionPolicy, operands); err != nil {
+		return nil, err
+	}
+	operatorRemoved, err := kube.UninstallOperator(ctx, r.Client, r.OLMClient, subscription, operator.operatorGroupName)
+	return append(removed, operatorRemoved...), err
+}
+
 // getClusterDomain retrieves the OpenShift cluster domain from the Ingress resource
 func (r *OrchestratorReconciler) getClusterDomain(ctx context.Context) (string, error) {
 	gcdLogger := log.FromContext(ctx)
@@ -392,7 +654,7 @@ func (r *OrchestratorReconciler) getClusterDomain(ctx context.Context) (string,
 	return clusterDomain, nil
 }
 
-func (r *OrchestratorReconciler) addFinalizers(ctx context.Context, orchestrator *orchestratorv1alpha2.Orchestrator) error {
+func (r *OrchestratorReconciler) addFinalizers(ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator) error {
 	if !controllerutil.ContainsFinalizer(orchestrator, FinalizerCRCleanup) {
 		controllerutil.AddFinalizer(orchestrator, FinalizerCRCleanup)
 		if err := r.Update(ctx, orchestrator); err != nil {
@@ -402,28 +664,132 @@ func (r *OrchestratorReconciler) addFinalizers(ctx context.Context, orchestrator
 	return nil
 }
 
-func (r *OrchestratorReconciler) handleCleanUp(ctx context.Context, orchestrator *orchestratorv1alpha2.Orchestrator) error {
-	// cleanup Knative
-	if err := knative.HandleKnativeCleanUp(ctx, r.Client); err != nil {
-		return err
+// subsystemCleanUp describes the resources of a subsystem removed according to its deletion policy.
+type subsystemCleanUp struct {
+	name    string
+	policy  orchestratorv1alpha3.DeletionPolicy
+	objects []client.Object
+}
+
+// getCleanUpPlan returns the resources owned by orchestrator that are deleted or orphaned once it is deleted. The
+// resources of the subsystems retained by their deletion policy are not listed.
+func (r *OrchestratorReconciler) getCleanUpPlan(ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator) ([]subsystemCleanUp, error) {
+	owner, err := r.getOwner(ctx, orchestrator)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := orchestrator.Spec.DeletionPolicy
+	subsystems := []struct {
+		name        string
+		policy      orchestratorv1alpha3.DeletionPolicy
+		listObjects func() ([]client.Object, error)
+	}{
+		{
+			name:   "GitOps",
+			policy: policies.GitOps,
+			listObjects: func() ([]client.Object, error) {
+				return orchestratorgitops.ListGitOpsCleanUpObjects(r.Client, ctx, orchestrator.Spec.ArgoCd.Namespace,
+					getTektonNamespace(orchestrator), owner)
+			},
+		},
+		{
+			name:   "K-Native Serverless",
+			policy: policies.Serverless,
+			listObjects: func() ([]client.Object, error) {
+				return knative.ListKnativeCleanUpObjects(ctx, r.Client, owner)
+			},
+		},
+		{
+			name:   "Serverless Logic",
+			policy: policies.ServerlessLogic,
+			listObjects: func() ([]client.Object, error) {
+				return listServerlessLogicCleanUpObjects(ctx, r.Client, orchestrator.Spec.PlatformConfig.Namespace, owner)
+			},
+		},
+		{
+			name:   "RHDH",
+			policy: policies.RHDH,
+			listObjects: func() ([]client.Object, error) {
+				return rhdh.ListRHDHCleanUpObjects(ctx, r.Client, orchestrator.Spec.RHDHConfig.Namespace, owner)
+			},
+		},
+	}
+
+	var plan []subsystemCleanUp
+	for _, subsystem := range subsystems {
+		if subsystem.policy != orchestratorv1alpha3.DeletionPolicyDelete && subsystem.policy != orchestratorv1alpha3.DeletionPolicyOrphan {
+			continue
+		}
+		objects, err := subsystem.listObjects()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the %s resources to clean up: %w", subsystem.name, err)
+		}
+		plan = append(plan, subsystemCleanUp{name: subsystem.name, policy: subsystem.policy, objects: objects})
 	}
-	// cleanup Serverless Logic
-	if err := handleServerlessLogicCleanUp(ctx, r.Client, orchestrator.Spec.PlatformConfig.Namespace); err != nil {
-		return err
+	return plan, nil
+}
+
+// describeCleanUpPlan returns the descriptions of the resources deleted and orphaned by plan.
+func describeCleanUpPlan(k8Client client.Client, plan []subsystemCleanUp) (deleted, orphaned []string) {
+	for _, cleanUp := range plan {
+		for _, obj := range cleanUp.objects {
+			switch cleanUp.policy {
+			case orchestratorv1alpha3.DeletionPolicyDelete:
+				deleted = append(deleted, kube.DescribeObject(k8Client, obj))
+			case orchestratorv1alpha3.DeletionPolicyOrphan:
+				orphaned = append(orphaned, kube.DescribeObject(k8Client, obj))
+			}
+		}
 	}
-	// cleanup RHDH
-	if err := rhdh.HandleRHDHCleanUp(ctx, r.Client, orchestrator.Spec.RHDHConfig.Namespace); err != nil {
+	return deleted, orphaned
+}
+
+// handleCleanUp removes the resources owned by orchestrator according to the deletion policy of their subsystem.
+// The resources shared by all the Orchestrators are only removed with the last one.
+func (r *OrchestratorReconciler) handleCleanUp(ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator) error {
+	logger := log.FromContext(ctx)
+
+	plan, err := r.getCleanUpPlan(ctx, orchestrator)
+	if err != nil {
+		logger.Error(err, "Error occurred when planning the clean up")
 		return err
 	}
-	return nil
+	var errorList []error
+	for _, cleanUp := range plan {
+		logger.Info("Cleaning up subsystem", "Subsystem", cleanUp.name, "DeletionPolicy", cleanUp.policy)
+		if err := kube.ApplyDeletionPolicy(ctx, r.Client, cleanUp.policy, cleanUp.objects); err != nil {
+			errorList = append(errorList, fmt.Errorf("%s: %w", cleanUp.name, err))
+		}
+	}
+	return utilerrors.NewAggregate(errorList)
 }
 
-// UpdateStatus sets the status of orchestrator.
-func (r *OrchestratorReconciler) UpdateStatus(ctx context.Context, orchestrator *orchestratorv1alpha2.Orchestrator, phase orchestratorv1alpha2.OrchestratorPhase, condition metav1.Condition) error {
+// getOwner returns the owner of the resources of orchestrator, which is the last owner when no other Orchestrator
+// exists in the cluster.
+func (r *OrchestratorReconciler) getOwner(ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator) (kube.Owner, error) {
 	logger := log.FromContext(ctx)
 
-	orchestrator.Status.Phase = phase
-	meta.SetStatusCondition(&orchestrator.Status.Conditions, condition)
+	orchestratorList := &orchestratorv1alpha3.OrchestratorList{}
+	if err := r.List(ctx, orchestratorList); err != nil {
+		logger.Error(err, "Error occurred when listing Orchestrators")
+		return kube.Owner{}, err
+	}
+	last := true
+	for _, item := range orchestratorList.Items {
+		if item.UID != orchestrator.UID {
+			last = false
+			break
+		}
+	}
+	return kube.Owner{Instance: kube.GetInstance(orchestrator), Last: last}, nil
+}
+
+// UpdateStatus computes the Ready condition and phase from the subsystem conditions and persists the status of orchestrator.
+func (r *OrchestratorReconciler) UpdateStatus(ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator) error {
+	logger := log.FromContext(ctx)
+
+	setReadyCondition(orchestrator)
 
 	err := r.Status().Update(ctx, orchestrator)
 	if err != nil {
@@ -433,7 +799,7 @@ func (r *OrchestratorReconciler) UpdateStatus(ctx context.Context, orchestrator
 	return nil
 }
 
-func (r *OrchestratorReconciler) reconcileNetworkPolicy(ctx context.Context, orchestrator *orchestratorv1alpha2.Orchestrator) error {
+func (r *OrchestratorReconciler) reconcileNetworkPolicy(ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator) error {
 	logger := log.FromContext(ctx)
 	logger.Info("Reconciling Network Policies...")
 
@@ -444,69 +810,114 @@ func (r *OrchestratorReconciler) reconcileNetworkPolicy(ctx context.Context, orc
 		return err
 	}
 
+	owner, err := r.getOwner(ctx, orchestrator)
+	if err != nil {
+		return err
+	}
 	monitoringFlag := orchestrator.Spec.PlatformConfig.Monitoring.Enabled
-	networkPolicyErrors := handleNetworkPolicy(r.Client, ctx, namespace, orchestrator.Spec.RHDHConfig.Namespace, orchestrator.Spec.PostgresConfig.Namespace, monitoringFlag)
-
-	if len(networkPolicyErrors) > 0 {
-		var networkPolicyNames []string
-		for networkPolicyName, err := range networkPolicyErrors {
-			logger.Error(err, "Error occurred when reconciling Network Policy", "NP", networkPolicyName)
-			networkPolicyNames = append(networkPolicyNames, networkPolicyName)
-		}
-		return fmt.Errorf("error occurred when reconciling the following Network Policies: %s ", strings.Join(networkPolicyNames, ", "))
+	networkPolicies := orchestrator.Spec.PlatformConfig.NetworkPolicies
+	appliedNamespaces := orchestrator.Status.NetworkPolicyNamespaces
+	err = handleNetworkPolicy(r.Client, ctx, namespace, orchestrator.Spec.RHDHConfig.Namespace, orchestrator.Spec.PostgresConfig.Namespace,
+		monitoringFlag, networkPolicies, appliedNamespaces, owner)
+	if err != nil {
+		// the stale network policies of the previous namespaces are deleted on the next reconciliation
+		orchestrator.Status.NetworkPolicyNamespaces = appendNamespace(appliedNamespaces, namespace)
+		logger.Error(err, "Error occurred when reconciling Network Policies")
+		return fmt.Errorf("error occurred when reconciling Network Policies: %w", err)
 	}
 
+	orchestrator.Status.NetworkPolicyNamespaces = nil
+	if !networkPolicies.Disabled {
+		orchestrator.Status.NetworkPolicyNamespaces = []string{namespace}
+	}
 	return nil
 }
 
-func (r *OrchestratorReconciler) reconcileGitOps(ctx context.Context, orchestrator *orchestratorv1alpha2.Orchestrator) error {
+func (r *OrchestratorReconciler) reconcileArgoCD(ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator) error {
 	logger := log.FromContext(ctx)
-	logger.Info("Reconciling GitOps...")
+	logger.Info("Reconciling ArgoCD...")
 
-	if !(orchestrator.Spec.ArgoCd.Enabled && orchestrator.Spec.Tekton.Enabled) {
-		logger.Info("Handling clean up  for GitOps...")
+	orchestrator.Status.Workflows = nil
+	if !orchestrator.Spec.ArgoCd.Enabled {
+		logger.Info("Handling clean up for ArgoCD...")
 
-		// handle argocd clean up
-		err := orchestratorgitops.HandleGitOpsCleanUp(r.Client, ctx, orchestrator.Spec.ArgoCd.Namespace)
+		owner, err := r.getOwner(ctx, orchestrator)
 		if err != nil {
 			return err
 		}
-
-		// handle tekton clean up
-		return nil
+		return orchestratorgitops.HandleArgoCDCleanUp(r.Client, ctx, orchestrator.Spec.ArgoCd.Namespace, owner)
 	}
 
-	logger.Info("Handling for GitOps...")
-	if err := orchestratorgitops.HandleGitOps(r.Client, ctx, orchestrator.Spec.ArgoCd.Namespace); err != nil {
+	logger.Info("Handling for ArgoCD...")
+	if err := orchestratorgitops.HandleArgoCD(r.Client, ctx, orchestrator.Spec.ArgoCd.Namespace, kube.GetInstance(orchestrator),
+		orchestrator.Spec.ArgoCd.Project); err != nil {
 		return err
 	}
 
-	return nil
+	workflowStatuses, err := orchestratorgitops.HandleWorkflowApplications(r.Client, ctx, orchestrator.Spec.ArgoCd.Namespace,
+		orchestrator.Spec.PlatformConfig.Namespace, kube.GetInstance(orchestrator), orchestrator.Spec.ArgoCd.Workflows)
+	orchestrator.Status.Workflows = workflowStatuses
+	return err
 }
 
-func (r *OrchestratorReconciler) reconcileSubscription(ctx context.Context, object client.Object) []reconcile.Request {
+func (r *OrchestratorReconciler) reconcileTekton(ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator) error {
 	logger := log.FromContext(ctx)
-	logger.Info("Reconciling Operator's Subscription...")
-	subscriptionObject := object.(*olmv1alpha1.Subscription)
-
-	if subscriptionObject != nil && kube.CheckLabelExist(subscriptionObject.Labels) {
-		if (subscriptionObject.Namespace == serverlessLogicOperatorNamespace) && (subscriptionObject.Name == serverlessLogicSubscriptionName) {
-			err := handleServerlessLogicOperatorInstallation(ctx, r.Client, r.OLMClient)
-			if err != nil && !apierrors.IsNotFound(err) {
-				logger.Error(err, "Error occurred when reconciling ServerlessLogic Operator's Subscription resource")
-				return nil
-			}
-		}
-		if (subscriptionObject.Namespace == knative.KnativeOperatorNamespace) && (subscriptionObject.Name == knative.KnativeSubscriptionName) {
-			err := knative.HandleKNativeOperatorInstallation(ctx, r.Client, r.OLMClient)
-			if err != nil && !apierrors.IsNotFound(err) {
-				logger.Error(err, "Error occurred when reconciling Serverless(K-Native) Operator's Subscription resource")
-				return nil
-			}
+	logger.Info("Reconciling Tekton...")
+
+	orchestrator.Status.TektonResources = nil
+	if !orchestrator.Spec.Tekton.Enabled {
+		logger.Info("Handling clean up for Tekton...")
+
+		owner, err := r.getOwner(ctx, orchestrator)
+		if err != nil {
+			return err
 		}
+		return orchestratorgitops.HandleTektonCleanUp(r.Client, ctx, getTektonNamespace(orchestrator), owner)
+	}
+
+	logger.Info("Handling for Tekton...")
+	tektonStatuses, err := orchestratorgitops.HandleTekton(r.Client, ctx, getTektonNamespace(orchestrator), kube.GetInstance(orchestrator),
+		orchestrator.Spec.Tekton.Pipeline)
+	orchestrator.Status.TektonResources = tektonStatuses
+	return err
+}
 
+// getTektonNamespace returns the namespace of the Tekton resources of orchestrator. The Orchestrators created before
+// Tekton had its own namespace, and not updated since, have their Tekton resources in the ArgoCD namespace.
+func getTektonNamespace(orchestrator *orchestratorv1alpha3.Orchestrator) string {
+	if orchestrator.Spec.Tekton.Namespace != "" {
+		return orchestrator.Spec.Tekton.Namespace
 	}
-	return nil
+	return orchestrator.Spec.ArgoCd.Namespace
+}
+
+func (r *OrchestratorReconciler) reconcilePostgres(ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator) error {
+	logger := log.FromContext(ctx)
+	logger.Info("Reconciling PostgreSQL reachability...")
+
+	return handlePostgresReachability(ctx, r.Client, orchestrator.Spec.PostgresConfig)
+}
+
+// reconcileSubscription enqueues the Orchestrators when a Subscription created by the operator changes,
+// so that the Subscription is reconciled against the subscription configuration of the Orchestrator.
+func (r *OrchestratorReconciler) reconcileSubscription(ctx context.Context, object client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx)
+	subscriptionObject, ok := object.(*olmv1alpha1.Subscription)
+	if !ok || !kube.CheckLabelExist(subscriptionObject.Labels) {
+		return nil
+	}
+	logger.Info("Reconciling Operator's Subscription...", "SubscriptionName", subscriptionObject.Name, "NS", subscriptionObject.Namespace)
+
+	orchestratorList := &orchestratorv1alpha3.OrchestratorList{}
+	if err := r.List(ctx, orchestratorList); err != nil {
+		logger.Error(err, "Error occurred when listing Orchestrators for Subscription", "SubscriptionName", subscriptionObject.Name)
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(orchestratorList.Items))
+	for _, orchestrator := range orchestratorList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: orchestrator.Name, Namespace: orchestrator.Namespace}})
+	}
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -521,9 +932,9 @@ func (r *OrchestratorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.OLMClient = olmClient
 
 	o := ctrl.NewControllerManagedBy(mgr).
-		For(&orchestratorv1alpha2.Orchestrator{}).
+		For(&orchestratorv1alpha3.Orchestrator{}).
 		Watches(&olmv1alpha1.Subscription{}, handler.EnqueueRequestsFromMapFunc(r.reconcileSubscription)).
-		Owns(&orchestratorv1alpha2.Orchestrator{}).
+		Owns(&orchestratorv1alpha3.Orchestrator{}).
 		WithOptions(controller.Options{MaxConcurrentReconciles: 2})
 
 	return o.Complete(r)
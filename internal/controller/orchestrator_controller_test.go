@@ -18,16 +18,24 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"sync"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	olmclientsetfake "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	orchestratorv1alpha2 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
 )
 
 var _ = Describe("Orchestrator Controller", func() {
@@ -40,13 +48,13 @@ var _ = Describe("Orchestrator Controller", func() {
 			Name:      resourceName,
 			Namespace: "default", // TODO(user):Modify as needed
 		}
-		orchestrator := &orchestratorv1alpha2.Orchestrator{}
+		orchestrator := &orchestratorv1alpha3.Orchestrator{}
 
 		BeforeEach(func() {
 			By("creating the custom resource for the Kind Orchestrator")
 			err := k8sClient.Get(ctx, typeNamespacedName, orchestrator)
 			if err != nil && errors.IsNotFound(err) {
-				resource := &orchestratorv1alpha2.Orchestrator{
+				resource := &orchestratorv1alpha3.Orchestrator{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      resourceName,
 						Namespace: "default",
@@ -59,7 +67,7 @@ var _ = Describe("Orchestrator Controller", func() {
 
 		AfterEach(func() {
 			// TODO(user): Cleanup logic after each test, like removing the resource instance.
-			resource := &orchestratorv1alpha2.Orchestrator{}
+			resource := &orchestratorv1alpha3.Orchestrator{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
 			Expect(err).NotTo(HaveOccurred())
 
@@ -82,3 +90,105 @@ var _ = Describe("Orchestrator Controller", func() {
 		})
 	})
 })
+
+// Run with the race detector (go test -race) to detect the state shared between concurrent reconciles, as the
+// controller runs with MaxConcurrentReconciles greater than one.
+var _ = Describe("Orchestrator Controller with concurrent reconciles", func() {
+	Context("When reconciling several resources in parallel", func() {
+		const resourceCount = 4
+
+		ctx := context.Background()
+
+		var typeNamespacedNames []types.NamespacedName
+		workflowNamespace := func(i int) string { return fmt.Sprintf("concurrent-workflows-%d", i) }
+
+		BeforeEach(func() {
+			typeNamespacedNames = nil
+			for i := range resourceCount {
+				namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: workflowNamespace(i)}}
+				err := k8sClient.Create(ctx, namespace)
+				if err != nil && !errors.IsAlreadyExists(err) {
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				By(fmt.Sprintf("creating the Orchestrator %d", i))
+				resource := &orchestratorv1alpha3.Orchestrator{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("concurrent-resource-%d", i),
+						Namespace: "default",
+					},
+					Spec: orchestratorv1alpha3.OrchestratorSpec{
+						ServerlessLogicOperator: orchestratorv1alpha3.ServerlessLogicOperator{InstallOperator: false},
+						ServerlessOperator:      orchestratorv1alpha3.ServerlessOperator{InstallOperator: false},
+						RHDHConfig:              orchestratorv1alpha3.RHDHConfig{Name: "backstage", Namespace: "rhdh"},
+						PostgresConfig: orchestratorv1alpha3.PostgresConfig{
+							Name:         "sonataflow-psql-postgresql",
+							Namespace:    "sonataflow-infra",
+							DatabaseName: "sonataflow",
+							AuthSecret: orchestratorv1alpha3.PostgresAuthSecret{
+								SecretName:  "sonataflow-psql-postgresql",
+								UserKey:     "postgres-username",
+								PasswordKey: "postgres-password",
+							},
+						},
+						PlatformConfig: orchestratorv1alpha3.PlatformConfig{
+							Namespace:  workflowNamespace(i),
+							Monitoring: orchestratorv1alpha3.MonitoringConfig{Enabled: i%2 == 0},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+				typeNamespacedNames = append(typeNamespacedNames, client.ObjectKeyFromObject(resource))
+			}
+		})
+
+		AfterEach(func() {
+			for _, typeNamespacedName := range typeNamespacedNames {
+				resource := &orchestratorv1alpha3.Orchestrator{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+
+				By("Cleanup the specific resource instance Orchestrator")
+				controllerutil.RemoveFinalizer(resource, FinalizerCRCleanup)
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+		})
+
+		It("should reconcile the network policies of every resource", func() {
+			controllerReconciler := &OrchestratorReconciler{
+				Client:    k8sClient,
+				OLMClient: olmclientsetfake.NewSimpleClientset(),
+				Scheme:    k8sClient.Scheme(),
+			}
+
+			By("Reconciling the created resources in parallel")
+			var wg sync.WaitGroup
+			for _, typeNamespacedName := range typeNamespacedNames {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					// the subsystems depending on operators that are not installed in the test environment fail
+					_, _ = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				}()
+			}
+			wg.Wait()
+
+			for i, typeNamespacedName := range typeNamespacedNames {
+				resource := &orchestratorv1alpha3.Orchestrator{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+				Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, TypeNetworkPoliciesReady)).To(BeTrue(),
+					"%s: %v", typeNamespacedName, meta.FindStatusCondition(resource.Status.Conditions, TypeNetworkPoliciesReady))
+				Expect(resource.Status.NetworkPolicyNamespaces).To(Equal([]string{workflowNamespace(i)}))
+
+				networkPolicies := &networkingv1.NetworkPolicyList{}
+				Expect(k8sClient.List(ctx, networkPolicies, client.InNamespace(workflowNamespace(i)))).To(Succeed())
+				expectedCount := len(NetworkPoliciesList)
+				if !resource.Spec.PlatformConfig.Monitoring.Enabled {
+					expectedCount--
+				}
+				Expect(networkPolicies.Items).To(HaveLen(expectedCount))
+			}
+		})
+	})
+})
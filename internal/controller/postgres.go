@@ -0,0 +1,53 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// handlePostgresReachability checks that the PostgreSQL service used by the Data Index and Job Service
+// exists and is backed by at least one ready endpoint.
+func handlePostgresReachability(ctx context.Context, client client.Client, postgresConfig orchestratorv1alpha3.PostgresConfig) error {
+	logger := log.FromContext(ctx)
+	logger.Info("Checking PostgreSQL service is reachable", "Service", postgresConfig.Name, "NS", postgresConfig.Namespace)
+
+	service := &corev1.Service{}
+	if err := client.Get(ctx, types.NamespacedName{Name: postgresConfig.Name, Namespace: postgresConfig.Namespace}, service); err != nil {
+		logger.Error(err, "Error occurred when retrieving PostgreSQL service", "Service", postgresConfig.Name, "NS", postgresConfig.Namespace)
+		return err
+	}
+
+	endpoints := &corev1.Endpoints{}
+	if err := client.Get(ctx, types.NamespacedName{Name: postgresConfig.Name, Namespace: postgresConfig.Namespace}, endpoints); err != nil {
+		logger.Error(err, "Error occurred when retrieving PostgreSQL service endpoints", "Service", postgresConfig.Name, "NS", postgresConfig.Namespace)
+		return err
+	}
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("postgreSQL service %s/%s has no ready endpoints", postgresConfig.Namespace, postgresConfig.Name)
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHandlePostgresReachability(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+
+	postgresConfig := orchestratorv1alpha3.PostgresConfig{Name: "sonataflow-psql-postgresql", Namespace: testDatabaseNamespace}
+	objectMeta := metav1.ObjectMeta{Name: postgresConfig.Name, Namespace: postgresConfig.Namespace}
+
+	testCases := []struct {
+		name           string
+		objects        []client.Object
+		expectError    bool
+		expectNotFound bool
+	}{
+		{
+			name:           "Service does not exist",
+			objects:        []client.Object{},
+			expectError:    true,
+			expectNotFound: true,
+		},
+		{
+			name: "Service has no ready endpoints",
+			objects: []client.Object{
+				&corev1.Service{ObjectMeta: objectMeta},
+				&corev1.Endpoints{ObjectMeta: objectMeta},
+			},
+			expectError: true,
+		},
+		{
+			name: "Service has ready endpoints",
+			objects: []client.Object{
+				&corev1.Service{ObjectMeta: objectMeta},
+				&corev1.Endpoints{
+					ObjectMeta: objectMeta,
+					Subsets: []corev1.EndpointSubset{
+						{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objects...).Build()
+			err := handlePostgresReachability(ctx, fakeClient, postgresConfig)
+			if !tc.expectError {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Equal(t, tc.expectNotFound, apierrors.IsNotFound(err))
+		})
+	}
+}
@@ -5,29 +5,33 @@ import (
 	"encoding/json"
 	"fmt"
 	olmclientset "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned"
-	orchestratorv1alpha2 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
 	kubeoperations "github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/metrics"
 	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/util"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"maps"
 	rhdhv1alpha3 "redhat-developer/red-hat-developer-hub-operator/api/v1alpha3"
 	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"slices"
 )
 
 const (
-	rhdhOperatorGroup                 = "rhdh-operator-group"
+	RHDHOperatorGroupName             = "rhdh-operator-group"
 	rhdhAPIVersion                    = "rhdh.redhat.com/v1alpha2"
 	rhdhKind                          = "Backstage"
 	rhdhCRDName                       = "backstages.rhdh.redhat.com"
 	rhdhReplica                 int32 = 1
-	rhdhSubscriptionName              = "rhdh"
+	RHDHSubscriptionName              = "rhdh"
 	rhdhSubscriptionChannel           = "fast-1.6"
-	rhdhOperatorNamespace             = "rhdh-operator"
+	RHDHOperatorNamespace             = "rhdh-operator"
 	rhdhSubscriptionStartingCSV       = "rhdh-operator.v1.6.1"
 )
 
@@ -38,63 +42,67 @@ var ConfigMapNameAndConfigDataKey = map[string]string{
 	AppConfigRHDHDynamicPluginName: "dynamic-plugins.yaml",
 }
 
-func HandleRHDHOperatorInstallation(ctx context.Context, client client.Client, olmClientSet olmclientset.Interface) error {
+// HandleRHDHOperatorInstallation performs operator installation for the RHDH operand
+// and returns the InstallPlan of the operator left waiting for approval, if any
+func HandleRHDHOperatorInstallation(
+	ctx context.Context, client client.Client, olmClientSet olmclientset.Interface,
+	subscriptionConfig orchestratorv1alpha3.Subscription, approvedCSV string) (*orchestratorv1alpha3.PendingInstallPlan, error) {
 	rhdhLogger := log.FromContext(ctx)
 
-	if _, err := kubeoperations.CheckNamespaceExist(ctx, client, rhdhOperatorNamespace); err != nil {
+	if _, err := kubeoperations.CheckNamespaceExist(ctx, client, RHDHOperatorNamespace); err != nil {
 		if apierrors.IsNotFound(err) {
-			if err := kubeoperations.CreateNamespace(ctx, client, rhdhOperatorNamespace); err != nil {
-				rhdhLogger.Error(err, "Error occurred when creating namespace for RHDH operator", "NS", rhdhOperatorNamespace)
-				return nil
+			if err := kubeoperations.CreateNamespace(ctx, client, RHDHOperatorNamespace); err != nil {
+				rhdhLogger.Error(err, "Error occurred when creating namespace for RHDH operator", "NS", RHDHOperatorNamespace)
+				return nil, nil
 			}
 		}
-		rhdhLogger.Error(err, "Error occurred when checking namespace exists for RHDH operator", "NS", rhdhOperatorNamespace)
-		return err
+		rhdhLogger.Error(err, "Error occurred when checking namespace exists for RHDH operator", "NS", RHDHOperatorNamespace)
+		return nil, err
 	}
 
 	// check if subscription exist
 	rhdhSubscription := kubeoperations.CreateSubscriptionObject(
-		rhdhSubscriptionName,
-		rhdhOperatorNamespace,
+		RHDHSubscriptionName,
+		RHDHOperatorNamespace,
 		rhdhSubscriptionChannel,
-		rhdhSubscriptionStartingCSV)
+		rhdhSubscriptionStartingCSV,
+		subscriptionConfig)
 
 	// check if subscription exists
 	subscriptionExists, existingSubscription, err := kubeoperations.CheckSubscriptionExists(ctx, olmClientSet, rhdhSubscription)
 	if err != nil {
-		rhdhLogger.Error(err, "Error occurred when checking subscription exists", "SubscriptionName", rhdhSubscriptionName)
-		return err
+		rhdhLogger.Error(err, "Error occurred when checking subscription exists", "SubscriptionName", RHDHSubscriptionName)
+		return nil, err
 	}
 	if !subscriptionExists {
 		if err := kubeoperations.InstallSubscriptionAndOperatorGroup(
 			ctx, client, olmClientSet,
-			rhdhOperatorGroup, rhdhSubscription); err != nil {
-			rhdhLogger.Error(err, "Error occurred when installing operator", "SubscriptionName", rhdhSubscriptionName)
-			return err
+			RHDHOperatorGroupName, rhdhSubscription); err != nil {
+			rhdhLogger.Error(err, "Error occurred when installing operator", "SubscriptionName", RHDHSubscriptionName)
+			return nil, err
 		}
-		rhdhLogger.Info("Operator successfully installed", "SubscriptionName", rhdhSubscriptionName)
+		rhdhLogger.Info("Operator successfully installed", "SubscriptionName", RHDHSubscriptionName)
 	} else {
 		// Compare the current and desired state
 		if !reflect.DeepEqual(existingSubscription.Spec, rhdhSubscription.Spec) {
 			// Update the existing subscription with the new Spec
 			existingSubscription.Spec = rhdhSubscription.Spec
 			if err := client.Update(ctx, existingSubscription); err != nil {
-				rhdhLogger.Error(err, "Error occurred when updating subscription spec", "SubscriptionName", rhdhSubscriptionName)
-				return err
+				rhdhLogger.Error(err, "Error occurred when updating subscription spec", "SubscriptionName", RHDHSubscriptionName)
+				return nil, err
 			}
-			rhdhLogger.Info("Successfully updated subscription spec", "SubscriptionName", rhdhSubscriptionName)
+			rhdhLogger.Info("Successfully updated subscription spec", "SubscriptionName", RHDHSubscriptionName)
+			metrics.RecordDriftCorrection("Subscription")
 		}
 	}
 
 	// approve install plan
-	if existingSubscription.Status.InstallPlanRef != nil && existingSubscription.Status.CurrentCSV == rhdhSubscriptionStartingCSV {
-		installPlanName := existingSubscription.Status.InstallPlanRef.Name
-		if err := kubeoperations.ApproveInstallPlan(client, ctx, installPlanName, existingSubscription.Namespace); err != nil {
-			rhdhLogger.Error(err, "Error occurred while approving install plan for subscription", "SubscriptionName", installPlanName)
-			return err
-		}
+	pendingInstallPlan, err := kubeoperations.HandleInstallPlanApproval(ctx, client, existingSubscription, subscriptionConfig, approvedCSV)
+	if err != nil {
+		rhdhLogger.Error(err, "Error occurred while approving install plan for subscription", "SubscriptionName", RHDHSubscriptionName)
+		return nil, err
 	}
-	return nil
+	return pendingInstallPlan, nil
 }
 
 func CreateRHDHSecret(secretNamespace string, ctx context.Context, client client.Client) error {
@@ -136,20 +144,23 @@ func CreateRHDHSecret(secretNamespace string, ctx context.Context, client client
 	return nil
 }
 
+// HandleRHDHCR creates or updates the Backstage CR. An existing Backstage CR is only updated when its spec was
+// not changed since the operator last rendered it; otherwise it is left untouched and returned as a conflict.
 func HandleRHDHCR(
-	rhdhConfig orchestratorv1alpha2.RHDHConfig,
+	rhdhConfig orchestratorv1alpha3.RHDHConfig,
 	bsConfigMapList []rhdhv1alpha3.FileObjectRef,
-	ctx context.Context, client client.Client) error {
+	instance string,
+	ctx context.Context, client client.Client) ([]string, error) {
 	rhdhLogger := log.FromContext(ctx)
 
 	// subscription exists; check if CRD exists for RHDH
 	if err := kubeoperations.CheckCRDExists(ctx, client, rhdhCRDName); err != nil {
 		if apierrors.IsNotFound(err) {
 			rhdhLogger.Info("CRD resource not found or ready", "CRD", rhdhCRDName)
-			return err
+			return nil, err
 		}
 		rhdhLogger.Error(err, "Error occurred when retrieving CRD", "CRD", rhdhCRDName)
-		return err
+		return nil, err
 	}
 
 	rhdhLogger.Info("Handling RHDH CR resource")
@@ -161,88 +172,215 @@ func HandleRHDHCR(
 	if err != nil {
 		rhdhLogger.Error(err, "Error occurred when parsing deployment patch for Backstage InitContainer",
 			"BackstageInitContainer", backstageInitContainerInBytes)
-		return err
+		return nil, err
+	}
+
+	backstageSpec := getBackstageSpec(bsConfigMapList, backstageInitContainerInBytes)
+	specHash, err := kubeoperations.ComputeConfigHash(backstageSpec)
+	if err != nil {
+		rhdhLogger.Error(err, "Error occurred when computing hash of Backstage spec", "CR-Name", rhdhName)
+		return nil, err
 	}
 
-	if err := client.Get(ctx, types.NamespacedName{Namespace: rhdhNamespace, Name: rhdhName}, &rhdhv1alpha3.Backstage{}); err != nil {
+	existingBackstageCR := &rhdhv1alpha3.Backstage{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: rhdhNamespace, Name: rhdhName}, existingBackstageCR); err != nil {
 		if apierrors.IsNotFound(err) {
-			secret := rhdhv1alpha3.EnvObjectRef{
-				Name: BackendAuthSecretName,
-			}
 			backstageCR := &rhdhv1alpha3.Backstage{
 				TypeMeta: metav1.TypeMeta{
 					APIVersion: rhdhAPIVersion,
 					Kind:       rhdhKind,
 				},
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      rhdhName,
-					Namespace: rhdhConfig.Namespace,
-					Labels:    kubeoperations.GetOrchestratorLabel(),
-				},
-				Spec: rhdhv1alpha3.BackstageSpec{
-					Application: &rhdhv1alpha3.Application{
-						AppConfig:                   &rhdhv1alpha3.AppConfig{ConfigMaps: bsConfigMapList},
-						DynamicPluginsConfigMapName: AppConfigRHDHDynamicPluginName,
-						ExtraEnvs: &rhdhv1alpha3.ExtraEnvs{
-							Secrets: []rhdhv1alpha3.EnvObjectRef{secret},
-						},
-						Replicas: util.MakePointer(rhdhReplica),
-					},
-					Deployment: &rhdhv1alpha3.BackstageDeployment{
-						Patch: &apiextensionsv1.JSON{
-							Raw: backstageInitContainerInBytes,
-						}},
+					Name:        rhdhName,
+					Namespace:   rhdhConfig.Namespace,
+					Labels:      kubeoperations.GetInstanceLabels(instance),
+					Annotations: map[string]string{kubeoperations.ConfigHashAnnotation: specHash},
 				},
+				Spec: backstageSpec,
 			}
 			rhdhLogger.Info("Creating Backstage CR", "CR-Name", backstageCR.Name)
 			if err := client.Create(ctx, backstageCR); err != nil {
 				rhdhLogger.Error(err, "Error occurred when creating RHDH resource", "CR-Name", rhdhName)
-				return err
+				return nil, err
 			}
 			rhdhLogger.Info("Successfully created RHDH resource", "CR-Name", rhdhName)
-			return nil
+			return nil, nil
 		}
 		rhdhLogger.Error(err, "Error occurred when retrieving RHDH resource", "CR-Name", rhdhName)
-		return err
+		return nil, err
 	}
-	return nil
+
+	liveHash, err := kubeoperations.ComputeConfigHash(getManagedBackstageSpec(existingBackstageCR.Spec))
+	if err != nil {
+		rhdhLogger.Error(err, "Error occurred when computing hash of Backstage spec", "CR-Name", rhdhName)
+		return nil, err
+	}
+	if liveHash != specHash && kubeoperations.IsModifiedOutsideOperator(existingBackstageCR, liveHash) {
+		rhdhLogger.Info("Backstage CR was modified outside of the operator and is not updated", "CR-Name", rhdhName)
+		return []string{fmt.Sprintf("%s %s/%s", rhdhKind, rhdhNamespace, rhdhName)}, nil
+	}
+	if liveHash == specHash && existingBackstageCR.Annotations[kubeoperations.ConfigHashAnnotation] == specHash {
+		return nil, nil
+	}
+
+	setManagedBackstageSpec(&existingBackstageCR.Spec, backstageSpec)
+	kubeoperations.SetConfigHash(existingBackstageCR, specHash)
+	if err := client.Update(ctx, existingBackstageCR); err != nil {
+		rhdhLogger.Error(err, "Error occurred when updating RHDH resource", "CR-Name", rhdhName)
+		return nil, err
+	}
+	rhdhLogger.Info("Successfully updated RHDH resource", "CR-Name", rhdhName)
+	metrics.RecordDriftCorrection(rhdhKind)
+	return nil, nil
+}
+
+func getBackstageSpec(bsConfigMapList []rhdhv1alpha3.FileObjectRef, backstageInitContainerInBytes []byte) rhdhv1alpha3.BackstageSpec {
+	return rhdhv1alpha3.BackstageSpec{
+		Application: &rhdhv1alpha3.Application{
+			AppConfig:                   &rhdhv1alpha3.AppConfig{ConfigMaps: bsConfigMapList},
+			DynamicPluginsConfigMapName: AppConfigRHDHDynamicPluginName,
+			ExtraEnvs: &rhdhv1alpha3.ExtraEnvs{
+				Secrets: []rhdhv1alpha3.EnvObjectRef{{Name: BackendAuthSecretName}},
+			},
+			Replicas: util.MakePointer(rhdhReplica),
+		},
+		Deployment: &rhdhv1alpha3.BackstageDeployment{
+			Patch: &apiextensionsv1.JSON{
+				Raw: backstageInitContainerInBytes,
+			}},
+	}
+}
+
+// getManagedBackstageSpec returns the fields of spec rendered by getBackstageSpec. Only they are hashed, since the
+// Backstage CRD sets the defaults of the other fields, such as the mount path of the app-config, on admission.
+func getManagedBackstageSpec(spec rhdhv1alpha3.BackstageSpec) rhdhv1alpha3.BackstageSpec {
+	managedSpec := rhdhv1alpha3.BackstageSpec{}
+	if application := spec.Application; application != nil {
+		managedSpec.Application = &rhdhv1alpha3.Application{
+			DynamicPluginsConfigMapName: application.DynamicPluginsConfigMapName,
+			Replicas:                    application.Replicas,
+		}
+		if application.AppConfig != nil {
+			managedSpec.Application.AppConfig = &rhdhv1alpha3.AppConfig{ConfigMaps: application.AppConfig.ConfigMaps}
+		}
+		if application.ExtraEnvs != nil {
+			managedSpec.Application.ExtraEnvs = &rhdhv1alpha3.ExtraEnvs{Secrets: application.ExtraEnvs.Secrets}
+		}
+	}
+	if spec.Deployment != nil {
+		managedSpec.Deployment = &rhdhv1alpha3.BackstageDeployment{Patch: spec.Deployment.Patch}
+	}
+	return managedSpec
+}
+
+// setManagedBackstageSpec sets the fields of spec rendered by getBackstageSpec to those of desired and keeps the
+// other ones.
+func setManagedBackstageSpec(spec *rhdhv1alpha3.BackstageSpec, desired rhdhv1alpha3.BackstageSpec) {
+	if spec.Application == nil {
+		spec.Application = &rhdhv1alpha3.Application{}
+	}
+	if spec.Application.AppConfig == nil {
+		spec.Application.AppConfig = &rhdhv1alpha3.AppConfig{}
+	}
+	if spec.Application.ExtraEnvs == nil {
+		spec.Application.ExtraEnvs = &rhdhv1alpha3.ExtraEnvs{}
+	}
+	if spec.Deployment == nil {
+		spec.Deployment = &rhdhv1alpha3.BackstageDeployment{}
+	}
+	spec.Application.AppConfig.ConfigMaps = desired.Application.AppConfig.ConfigMaps
+	spec.Application.DynamicPluginsConfigMapName = desired.Application.DynamicPluginsConfigMapName
+	spec.Application.ExtraEnvs.Secrets = desired.Application.ExtraEnvs.Secrets
+	spec.Application.Replicas = desired.Application.Replicas
+	spec.Deployment.Patch = desired.Deployment.Patch
 }
 
-// GetOrCreateConfigMaps creates or gets the configmap list
-func GetOrCreateConfigMaps(ctx context.Context, client client.Client,
+// CreateOrUpdateConfigMaps creates or updates the RHDH configmaps and returns the list of app-config configmaps.
+// An existing configmap is only updated when its data was not changed since the operator last rendered it;
+// otherwise it is left untouched and returned as a conflict.
+func CreateOrUpdateConfigMaps(ctx context.Context, client client.Client,
 	clusterDomain, serverlessWorkflowNamespace string,
-	tektonEnabled, argoCDEnabled bool,
-	rhdhConfig orchestratorv1alpha2.RHDHConfig) ([]rhdhv1alpha3.FileObjectRef, error) {
+	argoCDEnabled, tektonEnabled bool,
+	rhdhConfig orchestratorv1alpha3.RHDHConfig) ([]rhdhv1alpha3.FileObjectRef, []string, error) {
 
 	cmLogger := log.FromContext(ctx)
 	cmLogger.Info("Processing ConfigMaps...")
 
 	configmapList := make([]rhdhv1alpha3.FileObjectRef, 0)
+	var conflicts []string
 	namespace := rhdhConfig.Namespace
-	for cmName, configDataKey := range ConfigMapNameAndConfigDataKey {
+	// the configmaps are processed in a stable order so the Backstage spec does not change between reconciliations
+	for _, cmName := range slices.Sorted(maps.Keys(ConfigMapNameAndConfigDataKey)) {
+		configDataKey := ConfigMapNameAndConfigDataKey[cmName]
 		if cmName != AppConfigRHDHDynamicPluginName {
 			configmapList = append(configmapList, rhdhv1alpha3.FileObjectRef{Name: cmName})
 		}
-		cmLogger.Info("Starting Configmap creation for:", "CM", cmName, "NS", namespace)
+		cmLogger.Info("Starting Configmap reconciliation for:", "CM", cmName, "NS", namespace)
 
-		err := client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: cmName}, &corev1.ConfigMap{})
+		configValue, err := ConfigMapTemplateFactory(cmName, clusterDomain, serverlessWorkflowNamespace, argoCDEnabled, tektonEnabled, rhdhConfig)
 		if err != nil {
-			if apierrors.IsNotFound(err) {
-				cmLogger.Info("Configmap does not exist, creating CM", "CM", cmName)
-				configValue, err := ConfigMapTemplateFactory(cmName, clusterDomain, serverlessWorkflowNamespace, argoCDEnabled, tektonEnabled, rhdhConfig)
-				if err != nil {
-					cmLogger.Error(err, "Error occurred when parsing config data for configmap", "CM", cmName)
-					return configmapList, fmt.Errorf("failed to parse template data for configmap: %s", err)
-				} else {
-					if err := CreateConfigMap(cmName, configDataKey, namespace, configValue, ctx, client); err != nil {
-						cmLogger.Error(err, "Error occurred when creating ConfigMap", "CM", cmName)
-						return configmapList, err
-					}
-				}
+			cmLogger.Error(err, "Error occurred when parsing config data for configmap", "CM", cmName)
+			return configmapList, conflicts, fmt.Errorf("failed to parse template data for configmap: %s", err)
+		}
+		data := map[string]string{configDataKey: configValue}
+
+		existingConfigMap := &corev1.ConfigMap{}
+		if err := client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: cmName}, existingConfigMap); err != nil {
+			if !apierrors.IsNotFound(err) {
+				cmLogger.Error(err, "Error occurred when retrieving ConfigMap", "CM", cmName)
+				return configmapList, conflicts, err
+			}
+			cmLogger.Info("Configmap does not exist, creating CM", "CM", cmName)
+			if err := CreateConfigMap(cmName, configDataKey, namespace, configValue, ctx, client); err != nil {
+				cmLogger.Error(err, "Error occurred when creating ConfigMap", "CM", cmName)
+				return configmapList, conflicts, err
 			}
+			continue
+		}
+
+		conflict, err := updateConfigMap(ctx, client, existingConfigMap, data)
+		if err != nil {
+			return configmapList, conflicts, err
+		}
+		if conflict {
+			conflicts = append(conflicts, fmt.Sprintf("ConfigMap %s/%s", namespace, cmName))
 		}
 	}
-	return configmapList, nil
+	return configmapList, conflicts, nil
+}
+
+// updateConfigMap updates the data of an existing configmap, unless it was modified outside of the operator,
+// in which case it returns true.
+func updateConfigMap(ctx context.Context, client client.Client, configMap *corev1.ConfigMap, data map[string]string) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	dataHash, err := kubeoperations.ComputeConfigHash(data)
+	if err != nil {
+		logger.Error(err, "Error occurred when computing hash of ConfigMap data", "CM", configMap.Name)
+		return false, err
+	}
+	liveHash, err := kubeoperations.ComputeConfigHash(configMap.Data)
+	if err != nil {
+		logger.Error(err, "Error occurred when computing hash of ConfigMap data", "CM", configMap.Name)
+		return false, err
+	}
+	if liveHash != dataHash && kubeoperations.IsModifiedOutsideOperator(configMap, liveHash) {
+		logger.Info("ConfigMap was modified outside of the operator and is not updated", "CM", configMap.Name)
+		return true, nil
+	}
+	if liveHash == dataHash && configMap.Annotations[kubeoperations.ConfigHashAnnotation] == dataHash {
+		return false, nil
+	}
+
+	configMap.Data = data
+	kubeoperations.SetConfigHash(configMap, dataHash)
+	if err := client.Update(ctx, configMap); err != nil {
+		logger.Error(err, "Error occurred when updating ConfigMap", "CM", configMap.Name)
+		return false, err
+	}
+	logger.Info("Successfully updated ConfigMap", "CM", configMap.Name)
+	metrics.RecordDriftCorrection("ConfigMap")
+	return false, nil
 }
 
 func CreateConfigMap(
@@ -251,16 +389,24 @@ func CreateConfigMap(
 
 	logger := log.FromContext(ctx)
 
+	data := map[string]string{
+		configDataKey: configValue,
+	}
+	dataHash, err := kubeoperations.ComputeConfigHash(data)
+	if err != nil {
+		logger.Error(err, "Error occurred when computing hash of ConfigMap data", "CM", name)
+		return err
+	}
+
 	// Create the ConfigMap object
 	configMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: namespace,
-			Labels:    kubeoperations.GetOrchestratorLabel(),
-		},
-		Data: map[string]string{
-			configDataKey: configValue,
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      kubeoperations.GetOrchestratorLabel(),
+			Annotations: map[string]string{kubeoperations.ConfigHashAnnotation: dataHash},
 		},
+		Data: data,
 	}
 	if err := client.Create(ctx, configMap); err != nil {
 		logger.Error(err, "Error occurred when creating ConfigMap", "CM", name)
@@ -270,52 +416,42 @@ func CreateConfigMap(
 	return nil
 }
 
-func HandleRHDHCleanUp(ctx context.Context, client client.Client, rhdhNamespace string) error {
-	rhdhLogger := log.FromContext(ctx)
-
-	namespaceExist, _ := kubeoperations.CheckNamespaceExist(ctx, client, rhdhNamespace)
-	if namespaceExist {
-		backstageCRList, err := listBackstageCRs(ctx, client, rhdhNamespace)
-
-		if err != nil || len(backstageCRList) == 0 {
-			rhdhLogger.Error(err, "Failed to list RHDH CRs or have no RHDH CRs created by Orchestrator Operator and cannot perform clean up process")
-			return err
-		}
-		if len(backstageCRList) == 1 {
-			// remove namespace
-			if err := kubeoperations.CleanUpNamespace(ctx, rhdhNamespace, client); err != nil {
-				rhdhLogger.Error(err, "Error occurred when deleting namespace", "NS", "namespace")
-				return err
-			}
+// ListRHDHCleanUpObjects returns the Backstage CRs owned by owner in the RHDH namespace, the RHDH namespace when it
+// is owned by owner and no other Backstage CR is left in it, and the RHDH operator namespace when it is owned by
+// owner. They are removed in this order according to the rhdh deletion policy.
+func ListRHDHCleanUpObjects(ctx context.Context, k8Client client.Client, rhdhNamespace string, owner kubeoperations.Owner) ([]client.Object, error) {
+	backstageCRList := &rhdhv1alpha3.BackstageList{}
+	if err := k8Client.List(ctx, backstageCRList, client.InNamespace(rhdhNamespace)); err != nil && !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+	var objects []client.Object
+	otherBackstageCRs := 0
+	for i := range backstageCRList.Items {
+		backstageCR := &backstageCRList.Items[i]
+		if !owner.Owns(backstageCR) {
+			otherBackstageCRs++
+			continue
 		}
+		objects = append(objects, backstageCR)
 	}
 
-	// remove operator namespace
-	if err := kubeoperations.CleanUpNamespace(ctx, rhdhOperatorNamespace, client); err != nil {
-		rhdhLogger.Error(err, "Error occurred when deleting namespace", "NS", rhdhOperatorNamespace)
-		return err
+	var namespaceNames []string
+	if otherBackstageCRs == 0 {
+		namespaceNames = append(namespaceNames, rhdhNamespace)
 	}
-	return nil
-}
-
-func listBackstageCRs(ctx context.Context, k8client client.Client, namespace string) ([]rhdhv1alpha3.Backstage, error) {
-	rhdhLogger := log.FromContext(ctx)
-
-	crList := &rhdhv1alpha3.BackstageList{}
-
-	listOptions := []client.ListOption{
-		client.InNamespace(namespace),
-		client.MatchingLabels{kubeoperations.CreatedByLabelKey: kubeoperations.CreatedByLabelValue},
+	if rhdhNamespace != RHDHOperatorNamespace {
+		namespaceNames = append(namespaceNames, RHDHOperatorNamespace)
 	}
-
-	// List the CRs
-	if err := k8client.List(ctx, crList, listOptions...); err != nil {
-		rhdhLogger.Error(err, "Error occurred when listing RHDH CRs")
-		return nil, err
+	for _, namespaceName := range namespaceNames {
+		namespace, err := kubeoperations.GetOwnedNamespace(ctx, k8Client, namespaceName, owner)
+		if err != nil {
+			return nil, err
+		}
+		if namespace != nil {
+			objects = append(objects, namespace)
+		}
 	}
-
-	rhdhLogger.Info("Successfully listed RHDH CRs", "Total", len(crList.Items))
-	return crList.Items, nil
+	return objects, nil
 }
 
 func getPatchObjectForBackstageCR(ctx context.Context) ([]byte, error) {
@@ -0,0 +1,239 @@
+package rhdh
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	kubeoperations "github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	rhdhv1alpha3 "redhat-developer/red-hat-developer-hub-operator/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	rhdhNamespace     = "rhdh"
+	workflowNamespace = "sonataflow-infra"
+	clusterDomain     = "apps.example.com"
+	testInstance      = "orchestrator"
+)
+
+var rhdhConfig = orchestratorv1alpha3.RHDHConfig{Name: "backstage", Namespace: rhdhNamespace}
+
+func newFakeClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+	utilruntime.Must(rhdhv1alpha3.AddToScheme(scheme))
+
+	crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: rhdhCRDName}}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objects, crd)...).Build()
+}
+
+func getConfigMap(t *testing.T, k8Client client.Client, name string) *corev1.ConfigMap {
+	configMap := &corev1.ConfigMap{}
+	assert.NoError(t, k8Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: rhdhNamespace}, configMap))
+	return configMap
+}
+
+func TestCreateOrUpdateConfigMaps(t *testing.T) {
+	ctx := context.TODO()
+	fakeClient := newFakeClient()
+
+	configMapList, conflicts, err := CreateOrUpdateConfigMaps(ctx, fakeClient, clusterDomain, workflowNamespace, false, false, rhdhConfig)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, []rhdhv1alpha3.FileObjectRef{
+		{Name: AppConfigRHDHName}, {Name: AppConfigRHDHAuthName}, {Name: AppConfigRHDHCatalogName},
+	}, configMapList)
+
+	dynamicPlugins := getConfigMap(t, fakeClient, AppConfigRHDHDynamicPluginName)
+	assert.Contains(t, dynamicPlugins.Annotations, kubeoperations.ConfigHashAnnotation)
+	renderedHash := dynamicPlugins.Annotations[kubeoperations.ConfigHashAnnotation]
+
+	// enabling tekton re-renders the dynamic plugins
+	_, conflicts, err = CreateOrUpdateConfigMaps(ctx, fakeClient, clusterDomain, workflowNamespace, false, true, rhdhConfig)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	dynamicPlugins = getConfigMap(t, fakeClient, AppConfigRHDHDynamicPluginName)
+	assert.NotEqual(t, renderedHash, dynamicPlugins.Annotations[kubeoperations.ConfigHashAnnotation])
+
+	// a configmap edited by the user is not overwritten
+	dynamicPlugins.Data = map[string]string{"dynamic-plugins.yaml": "plugins: []"}
+	assert.NoError(t, fakeClient.Update(ctx, dynamicPlugins))
+
+	_, conflicts, err = CreateOrUpdateConfigMaps(ctx, fakeClient, clusterDomain, workflowNamespace, false, false, rhdhConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ConfigMap rhdh/dynamic-plugins-rhdh"}, conflicts)
+	assert.Equal(t, "plugins: []", getConfigMap(t, fakeClient, AppConfigRHDHDynamicPluginName).Data["dynamic-plugins.yaml"])
+}
+
+func TestCreateOrUpdateConfigMapsPlugins(t *testing.T) {
+	const tektonPlugin = "backstage-community-plugin-tekton"
+	const argoCDPlugin = "backstage-community-plugin-redhat-argocd"
+
+	testCases := []struct {
+		name          string
+		argoCDEnabled bool
+		tektonEnabled bool
+	}{
+		{name: "ArgoCD and Tekton disabled"},
+		{name: "ArgoCD enabled", argoCDEnabled: true},
+		{name: "Tekton enabled", tektonEnabled: true},
+		{name: "ArgoCD and Tekton enabled", argoCDEnabled: true, tektonEnabled: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := newFakeClient()
+			_, _, err := CreateOrUpdateConfigMaps(context.TODO(), fakeClient, clusterDomain, workflowNamespace, tc.argoCDEnabled, tc.tektonEnabled, rhdhConfig)
+			assert.NoError(t, err)
+
+			dynamicPlugins := getConfigMap(t, fakeClient, AppConfigRHDHDynamicPluginName).Data["dynamic-plugins.yaml"]
+			assert.Equal(t, tc.tektonEnabled, strings.Contains(dynamicPlugins, tektonPlugin))
+			assert.Equal(t, tc.argoCDEnabled, strings.Contains(dynamicPlugins, argoCDPlugin))
+			appConfig := getConfigMap(t, fakeClient, AppConfigRHDHName).Data["app-config-rhdh.yaml"]
+			assert.Equal(t, tc.argoCDEnabled, strings.Contains(appConfig, "argocd:"))
+		})
+	}
+}
+
+func TestCreateOrUpdateConfigMapsWithoutHashAnnotation(t *testing.T) {
+	ctx := context.TODO()
+	objectMeta := metav1.ObjectMeta{Namespace: rhdhNamespace}
+
+	createdByPreviousVersion := &corev1.ConfigMap{ObjectMeta: *objectMeta.DeepCopy(), Data: map[string]string{"app-config-rhdh.yaml": "app: {}"}}
+	createdByPreviousVersion.Name = AppConfigRHDHName
+	createdByPreviousVersion.Labels = kubeoperations.GetOrchestratorLabel()
+
+	createdByUser := &corev1.ConfigMap{ObjectMeta: *objectMeta.DeepCopy(), Data: map[string]string{"app-config-catalog.yaml": "catalog: {}"}}
+	createdByUser.Name = AppConfigRHDHCatalogName
+
+	fakeClient := newFakeClient(createdByPreviousVersion, createdByUser)
+
+	_, conflicts, err := CreateOrUpdateConfigMaps(ctx, fakeClient, clusterDomain, workflowNamespace, false, false, rhdhConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ConfigMap rhdh/app-config-rhdh-catalog"}, conflicts)
+	assert.NotEqual(t, "app: {}", getConfigMap(t, fakeClient, AppConfigRHDHName).Data["app-config-rhdh.yaml"])
+	assert.Equal(t, "catalog: {}", getConfigMap(t, fakeClient, AppConfigRHDHCatalogName).Data["app-config-catalog.yaml"])
+}
+
+func TestHandleRHDHCR(t *testing.T) {
+	ctx := context.TODO()
+	fakeClient := newFakeClient()
+	backstageKey := types.NamespacedName{Name: rhdhConfig.Name, Namespace: rhdhNamespace}
+
+	configMapList := []rhdhv1alpha3.FileObjectRef{{Name: AppConfigRHDHName}}
+	conflicts, err := HandleRHDHCR(rhdhConfig, configMapList, testInstance, ctx, fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	backstage := &rhdhv1alpha3.Backstage{}
+	assert.NoError(t, fakeClient.Get(ctx, backstageKey, backstage))
+	assert.Equal(t, configMapList, backstage.Spec.Application.AppConfig.ConfigMaps)
+	assert.Equal(t, kubeoperations.GetInstanceLabels(testInstance), backstage.Labels)
+	resourceVersion := backstage.ResourceVersion
+
+	// reconciling the same configuration does not update the CR
+	conflicts, err = HandleRHDHCR(rhdhConfig, configMapList, testInstance, ctx, fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.NoError(t, fakeClient.Get(ctx, backstageKey, backstage))
+	assert.Equal(t, resourceVersion, backstage.ResourceVersion)
+
+	// a new configuration is rendered into the CR
+	configMapList = append(configMapList, rhdhv1alpha3.FileObjectRef{Name: AppConfigRHDHAuthName})
+	conflicts, err = HandleRHDHCR(rhdhConfig, configMapList, testInstance, ctx, fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.NoError(t, fakeClient.Get(ctx, backstageKey, backstage))
+	assert.Equal(t, configMapList, backstage.Spec.Application.AppConfig.ConfigMaps)
+
+	// a CR edited by the user is not overwritten
+	backstage.Spec.Application.Replicas = nil
+	assert.NoError(t, fakeClient.Update(ctx, backstage))
+
+	conflicts, err = HandleRHDHCR(rhdhConfig, configMapList[:1], testInstance, ctx, fakeClient)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Backstage rhdh/backstage"}, conflicts)
+	assert.NoError(t, fakeClient.Get(ctx, backstageKey, backstage))
+	assert.Nil(t, backstage.Spec.Application.Replicas)
+	assert.Equal(t, configMapList, backstage.Spec.Application.AppConfig.ConfigMaps)
+}
+
+func TestHandleRHDHCRWithCRDDefaults(t *testing.T) {
+	ctx := context.TODO()
+	fakeClient := newFakeClient()
+	backstageKey := types.NamespacedName{Name: rhdhConfig.Name, Namespace: rhdhNamespace}
+
+	configMapList := []rhdhv1alpha3.FileObjectRef{{Name: AppConfigRHDHName}}
+	_, err := HandleRHDHCR(rhdhConfig, configMapList, testInstance, ctx, fakeClient)
+	assert.NoError(t, err)
+
+	// the Backstage CRD sets the defaults of the fields not rendered by the operator on admission
+	backstage := &rhdhv1alpha3.Backstage{}
+	assert.NoError(t, fakeClient.Get(ctx, backstageKey, backstage))
+	backstage.Spec.Application.AppConfig.MountPath = "/opt/app-root/src"
+	backstage.Spec.Application.ImagePullSecrets = []string{"pull-secret"}
+	assert.NoError(t, fakeClient.Update(ctx, backstage))
+	resourceVersion := backstage.ResourceVersion
+
+	conflicts, err := HandleRHDHCR(rhdhConfig, configMapList, testInstance, ctx, fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.NoError(t, fakeClient.Get(ctx, backstageKey, backstage))
+	assert.Equal(t, resourceVersion, backstage.ResourceVersion)
+
+	configMapList = append(configMapList, rhdhv1alpha3.FileObjectRef{Name: AppConfigRHDHAuthName})
+	conflicts, err = HandleRHDHCR(rhdhConfig, configMapList, testInstance, ctx, fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.NoError(t, fakeClient.Get(ctx, backstageKey, backstage))
+	assert.Equal(t, configMapList, backstage.Spec.Application.AppConfig.ConfigMaps)
+	assert.Equal(t, "/opt/app-root/src", backstage.Spec.Application.AppConfig.MountPath)
+	assert.Equal(t, []string{"pull-secret"}, backstage.Spec.Application.ImagePullSecrets)
+}
+
+func TestListRHDHCleanUpObjects(t *testing.T) {
+	ctx := context.TODO()
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: rhdhNamespace, Labels: kubeoperations.GetOrchestratorLabel()}}
+	operatorNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: RHDHOperatorNamespace, Labels: kubeoperations.GetOrchestratorLabel()}}
+	backstage := &rhdhv1alpha3.Backstage{
+		ObjectMeta: metav1.ObjectMeta{Name: "backstage", Namespace: rhdhNamespace, Labels: kubeoperations.GetInstanceLabels(testInstance)},
+	}
+	otherBackstage := &rhdhv1alpha3.Backstage{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: rhdhNamespace, Labels: kubeoperations.GetInstanceLabels("other")},
+	}
+	fakeClient := newFakeClient(namespace, operatorNamespace, backstage, otherBackstage)
+
+	describe := func(objects []client.Object) []string {
+		var descriptions []string
+		for _, obj := range objects {
+			descriptions = append(descriptions, kubeoperations.DescribeObject(fakeClient, obj))
+		}
+		return descriptions
+	}
+
+	// the namespace is kept while another Orchestrator runs RHDH in it
+	objects, err := ListRHDHCleanUpObjects(ctx, fakeClient, rhdhNamespace, kubeoperations.Owner{Instance: testInstance, Last: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Backstage rhdh/backstage", "Namespace " + RHDHOperatorNamespace}, describe(objects))
+
+	assert.NoError(t, fakeClient.Delete(ctx, otherBackstage))
+	objects, err = ListRHDHCleanUpObjects(ctx, fakeClient, rhdhNamespace, kubeoperations.Owner{Instance: testInstance, Last: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Backstage rhdh/backstage", "Namespace rhdh", "Namespace " + RHDHOperatorNamespace}, describe(objects))
+
+	// the shared namespaces are kept while other Orchestrators exist
+	objects, err = ListRHDHCleanUpObjects(ctx, fakeClient, rhdhNamespace, kubeoperations.Owner{Instance: testInstance})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Backstage rhdh/backstage"}, describe(objects))
+}
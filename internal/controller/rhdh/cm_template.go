@@ -86,7 +86,7 @@ func ConfigMapTemplateFactory(
 		if err != nil {
 			return "", err
 		}
-		return formattedConfig, nil
+		return applyPluginOverrides(formattedConfig, rhdhConfig.RHDHPlugins.Overrides)
 	default:
 		return "", nil
 	}
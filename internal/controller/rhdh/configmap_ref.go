@@ -9,3 +9,6 @@ const (
 	Scope                          = "@redhat"
 	CatalogBranch                  = "v1.6.x"
 )
+
+// AppConfigRHDHOrchestratorName is the app-config configmap added to an existing RHDH instance
+const AppConfigRHDHOrchestratorName = "app-config-rhdh-orchestrator"
@@ -0,0 +1,372 @@
+package rhdh
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	kubeoperations "github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/metrics"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	rhdhv1alpha3 "redhat-developer/red-hat-developer-hub-operator/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	dynamicPluginsDefaultInclude = "dynamic-plugins.default.yaml"
+	appConfigOrchestratorDataKey = "app-config-orchestrator.yaml"
+	// addedPluginsAnnotation lists the packages of the plugins added by the operator to the dynamic plugins configmap
+	addedPluginsAnnotation = "rhdh.redhat.com/added-plugins"
+)
+
+const OrchestratorAppConfigTempl = `orchestrator:
+  dataIndexService:
+    url: http://sonataflow-platform-data-index-service.{{ .WorkflowNamespace }}
+{{- if .ArgoCDEnabled }}
+argocd:
+  appLocatorMethods:
+    - instances:
+        - name: main
+          url: {{ printf "${%s}" .ArgoCDUrl }}
+          username: {{ printf "${%s}" .ArgoCDUsername }}
+          password: {{ printf "${%s}" .ArgoCDPassword }}
+      type: config
+{{- end }}
+`
+
+type OrchestratorAppConfig struct {
+	WorkflowNamespace string
+	ArgoCDEnabled     bool
+	ArgoCDUrl         string
+	ArgoCDUsername    string
+	ArgoCDPassword    string
+}
+
+// tektonPlugins and argoCDPlugins are the packages of the dynamic plugins enabled by tekton.enabled and
+// argocd.enabled, which are configured in the existing RHDH instance along with the orchestrator plugins.
+var (
+	tektonPlugins = []string{"./dynamic-plugins/dist/backstage-community-plugin-tekton"}
+	argoCDPlugins = []string{
+		"./dynamic-plugins/dist/backstage-community-plugin-redhat-argocd",
+		"./dynamic-plugins/dist/roadiehq-backstage-plugin-argo-cd-backend-dynamic",
+		"./dynamic-plugins/dist/roadiehq-scaffolder-backend-argocd-dynamic",
+	}
+)
+
+// HandleExistingRHDH configures the orchestrator plugins in an existing RHDH instance that is not installed by
+// the operator. The orchestrator plugins are merged into the dynamic plugins configmap of the Backstage CR, and an
+// app-config configmap with the data index URL is added to it; the rest of the Backstage CR is left untouched.
+// The ArgoCD and Tekton plugins are configured as well when argoCDEnabled and tektonEnabled are set, and the ArgoCD
+// plugins only when the backend auth secret has the ArgoCD environment, which is then loaded by the Backstage CR.
+// It returns the configmaps owned by the operator that were modified outside of it.
+func HandleExistingRHDH(ctx context.Context, client client.Client,
+	serverlessWorkflowNamespace string, argoCDEnabled, tektonEnabled bool,
+	rhdhConfig orchestratorv1alpha3.RHDHConfig) ([]string, error) {
+	rhdhLogger := log.FromContext(ctx)
+
+	if err := kubeoperations.CheckCRDExists(ctx, client, rhdhCRDName); err != nil {
+		if apierrors.IsNotFound(err) {
+			rhdhLogger.Info("RHDH instance is not managed by the RHDH operator. The orchestrator plugins must be configured manually", "CRD", rhdhCRDName)
+			return nil, nil
+		}
+		rhdhLogger.Error(err, "Error occurred when retrieving CRD", "CRD", rhdhCRDName)
+		return nil, err
+	}
+
+	rhdhNamespace := rhdhConfig.Namespace
+	rhdhName := rhdhConfig.Name
+	backstageCR := &rhdhv1alpha3.Backstage{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: rhdhNamespace, Name: rhdhName}, backstageCR); err != nil {
+		rhdhLogger.Error(err, "Error occurred when retrieving existing RHDH resource", "CR-Name", rhdhName, "NS", rhdhNamespace)
+		return nil, err
+	}
+
+	if err := CreateRHDHSecret(rhdhNamespace, ctx, client); err != nil {
+		return nil, err
+	}
+
+	if argoCDEnabled {
+		configured, err := hasArgoCDEnv(ctx, client, rhdhNamespace)
+		if err != nil {
+			return nil, err
+		}
+		if !configured {
+			rhdhLogger.Info("ArgoCD plugins are not configured, as the secret does not have the ArgoCD environment",
+				"Secret", BackendAuthSecretName, "NS", rhdhNamespace)
+		}
+		argoCDEnabled = configured
+	}
+
+	// merge the orchestrator plugins into the dynamic plugins of the instance
+	orchestratorPlugins, err := getOrchestratorPlugins(serverlessWorkflowNamespace, argoCDEnabled, tektonEnabled, rhdhConfig)
+	if err != nil {
+		rhdhLogger.Error(err, "Error occurred when rendering the orchestrator dynamic plugins")
+		return nil, err
+	}
+	dynamicPluginsConfigMapName := AppConfigRHDHDynamicPluginName
+	if backstageCR.Spec.Application != nil && backstageCR.Spec.Application.DynamicPluginsConfigMapName != "" {
+		dynamicPluginsConfigMapName = backstageCR.Spec.Application.DynamicPluginsConfigMapName
+	}
+	if err := mergeDynamicPluginsConfigMap(ctx, client, dynamicPluginsConfigMapName, rhdhNamespace, orchestratorPlugins); err != nil {
+		return nil, err
+	}
+
+	// add the orchestrator app-config
+	var conflicts []string
+	appConfig, err := parseConfigTemplate(OrchestratorAppConfigTempl, OrchestratorAppConfig{
+		WorkflowNamespace: serverlessWorkflowNamespace,
+		ArgoCDEnabled:     argoCDEnabled,
+		ArgoCDUrl:         ArgoCDUrl,
+		ArgoCDUsername:    ArgoCDUsername,
+		ArgoCDPassword:    ArgoCDPassword,
+	})
+	if err != nil {
+		rhdhLogger.Error(err, "Error occurred when parsing config data for configmap", "CM", AppConfigRHDHOrchestratorName)
+		return nil, err
+	}
+	existingConfigMap := &corev1.ConfigMap{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: rhdhNamespace, Name: AppConfigRHDHOrchestratorName}, existingConfigMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			rhdhLogger.Error(err, "Error occurred when retrieving ConfigMap", "CM", AppConfigRHDHOrchestratorName)
+			return nil, err
+		}
+		if err := CreateConfigMap(AppConfigRHDHOrchestratorName, appConfigOrchestratorDataKey, rhdhNamespace, appConfig, ctx, client); err != nil {
+			return nil, err
+		}
+	} else {
+		conflict, err := updateConfigMap(ctx, client, existingConfigMap, map[string]string{appConfigOrchestratorDataKey: appConfig})
+		if err != nil {
+			return nil, err
+		}
+		if conflict {
+			conflicts = append(conflicts, fmt.Sprintf("ConfigMap %s/%s", rhdhNamespace, AppConfigRHDHOrchestratorName))
+		}
+	}
+
+	if err := patchExistingBackstageCR(ctx, client, backstageCR, dynamicPluginsConfigMapName, argoCDEnabled); err != nil {
+		return conflicts, err
+	}
+	return conflicts, nil
+}
+
+// hasArgoCDEnv returns whether the backend auth secret in namespace has the environment of the ArgoCD instance
+// referenced by the orchestrator app-config.
+func hasArgoCDEnv(ctx context.Context, k8Client client.Client, namespace string) (bool, error) {
+	secret := &corev1.Secret{}
+	if err := k8Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: BackendAuthSecretName}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		log.FromContext(ctx).Error(err, "Error occurred when retrieving secret", "Secret", BackendAuthSecretName, "NS", namespace)
+		return false, err
+	}
+	for _, key := range []string{ArgoCDUrl, ArgoCDUsername, ArgoCDPassword} {
+		if _, found := secret.Data[key]; !found {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// patchExistingBackstageCR references the configmaps of the orchestrator in the Backstage CR, and the backend auth
+// secret in its environment when argoCDEnabled is set. References are only added, never removed.
+func patchExistingBackstageCR(ctx context.Context, k8Client client.Client,
+	backstageCR *rhdhv1alpha3.Backstage, dynamicPluginsConfigMapName string, argoCDEnabled bool) error {
+	rhdhLogger := log.FromContext(ctx)
+
+	original := backstageCR.DeepCopy()
+	if backstageCR.Spec.Application == nil {
+		backstageCR.Spec.Application = &rhdhv1alpha3.Application{}
+	}
+	application := backstageCR.Spec.Application
+	application.DynamicPluginsConfigMapName = dynamicPluginsConfigMapName
+	if application.AppConfig == nil {
+		application.AppConfig = &rhdhv1alpha3.AppConfig{}
+	}
+	if !slices.ContainsFunc(application.AppConfig.ConfigMaps, func(ref rhdhv1alpha3.FileObjectRef) bool {
+		return ref.Name == AppConfigRHDHOrchestratorName
+	}) {
+		application.AppConfig.ConfigMaps = append(application.AppConfig.ConfigMaps, rhdhv1alpha3.FileObjectRef{Name: AppConfigRHDHOrchestratorName})
+	}
+	if argoCDEnabled {
+		if application.ExtraEnvs == nil {
+			application.ExtraEnvs = &rhdhv1alpha3.ExtraEnvs{}
+		}
+		// a reference without key loads all the keys of the secret
+		if !slices.ContainsFunc(application.ExtraEnvs.Secrets, func(ref rhdhv1alpha3.EnvObjectRef) bool {
+			return ref.Name == BackendAuthSecretName && ref.Key == ""
+		}) {
+			application.ExtraEnvs.Secrets = append(application.ExtraEnvs.Secrets, rhdhv1alpha3.EnvObjectRef{Name: BackendAuthSecretName})
+		}
+	}
+	if reflect.DeepEqual(original.Spec, backstageCR.Spec) {
+		return nil
+	}
+
+	patch := client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})
+	if err := k8Client.Patch(ctx, backstageCR, patch); err != nil {
+		rhdhLogger.Error(err, "Error occurred when patching existing RHDH resource", "CR-Name", backstageCR.Name)
+		return err
+	}
+	rhdhLogger.Info("Successfully referenced orchestrator configmaps in existing RHDH resource", "CR-Name", backstageCR.Name)
+	metrics.RecordDriftCorrection(rhdhKind)
+	return nil
+}
+
+// mergeDynamicPluginsConfigMap adds the orchestrator plugins to the dynamic plugins configmap, replacing any
+// other version of them, or creates the configmap when it does not exist. The plugins added by the operator are
+// recorded in the addedPluginsAnnotation, so that they are removed once not desired anymore.
+func mergeDynamicPluginsConfigMap(ctx context.Context, client client.Client, name, namespace string, plugins []any) error {
+	logger := log.FromContext(ctx)
+	configDataKey := ConfigMapNameAndConfigDataKey[AppConfigRHDHDynamicPluginName]
+
+	configMap := &corev1.ConfigMap{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "Error occurred when retrieving ConfigMap", "CM", name)
+			return err
+		}
+		configValue, added, _, err := mergeDynamicPlugins("", plugins, nil)
+		if err != nil {
+			logger.Error(err, "Error occurred when merging dynamic plugins", "CM", name)
+			return err
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				Labels:      kubeoperations.GetOrchestratorLabel(),
+				Annotations: map[string]string{addedPluginsAnnotation: strings.Join(added, ",")},
+			},
+			Data: map[string]string{configDataKey: configValue},
+		}
+		if err := client.Create(ctx, configMap); err != nil {
+			logger.Error(err, "Error occurred when creating ConfigMap", "CM", name)
+			return err
+		}
+		logger.Info("Successfully created ConfigMap", "CM", name)
+		return nil
+	}
+
+	var previouslyAdded []string
+	if annotation := configMap.Annotations[addedPluginsAnnotation]; annotation != "" {
+		previouslyAdded = strings.Split(annotation, ",")
+	}
+	configValue, added, changed, err := mergeDynamicPlugins(configMap.Data[configDataKey], plugins, previouslyAdded)
+	if err != nil {
+		logger.Error(err, "Error occurred when merging dynamic plugins", "CM", name)
+		return err
+	}
+	if !changed && slices.Equal(added, previouslyAdded) {
+		return nil
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[configDataKey] = configValue
+	if configMap.Annotations == nil {
+		configMap.Annotations = map[string]string{}
+	}
+	configMap.Annotations[addedPluginsAnnotation] = strings.Join(added, ",")
+	if err := client.Update(ctx, configMap); err != nil {
+		logger.Error(err, "Error occurred when updating ConfigMap", "CM", name)
+		return err
+	}
+	logger.Info("Successfully merged orchestrator plugins into ConfigMap", "CM", name)
+	metrics.RecordDriftCorrection("ConfigMap")
+	return nil
+}
+
+// mergeDynamicPlugins adds the plugins to the dynamic plugins configuration, replacing the entries of the same
+// packages regardless of their version, and removes the entries of the packages in added that are not part of
+// plugins. It returns the merged configuration, the packages of the entries added by the operator and whether the
+// configuration differs from config. The entries of the other packages are left untouched.
+func mergeDynamicPlugins(config string, plugins []any, added []string) (string, []string, bool, error) {
+	dynamicPlugins := map[string]any{}
+	if err := yaml.Unmarshal([]byte(config), &dynamicPlugins); err != nil {
+		return "", nil, false, fmt.Errorf("failed to parse dynamic plugins: %w", err)
+	}
+	if dynamicPlugins == nil {
+		dynamicPlugins = map[string]any{}
+	}
+	if _, found := dynamicPlugins["includes"]; !found {
+		dynamicPlugins["includes"] = []any{dynamicPluginsDefaultInclude}
+	}
+
+	names := make([]string, 0, len(plugins))
+	for _, plugin := range plugins {
+		names = append(names, getPluginName(plugin))
+	}
+	existingPlugins, _ := dynamicPlugins["plugins"].([]any)
+	existingCount := len(existingPlugins)
+	existingPlugins = slices.DeleteFunc(existingPlugins, func(existingPlugin any) bool {
+		name := getPluginName(existingPlugin)
+		return slices.Contains(added, name) && !slices.Contains(names, name)
+	})
+	changed := len(existingPlugins) != existingCount
+
+	var addedPlugins []string
+	for i, plugin := range plugins {
+		name := names[i]
+		index := slices.IndexFunc(existingPlugins, func(existingPlugin any) bool {
+			return getPluginName(existingPlugin) == name
+		})
+		if index < 0 || slices.Contains(added, name) {
+			addedPlugins = append(addedPlugins, name)
+		}
+		switch {
+		case index < 0:
+			existingPlugins = append(existingPlugins, plugin)
+			changed = true
+		case !reflect.DeepEqual(existingPlugins[index], plugin):
+			existingPlugins[index] = plugin
+			changed = true
+		}
+	}
+	dynamicPlugins["plugins"] = existingPlugins
+
+	merged, err := yaml.Marshal(dynamicPlugins)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("failed to marshal dynamic plugins: %w", err)
+	}
+	slices.Sort(addedPlugins)
+	return string(merged), addedPlugins, changed || config == "", nil
+}
+
+// getOrchestratorPlugins returns the entries of the orchestrator plugins, of the ArgoCD and Tekton plugins when
+// enabled and of the overridden plugins in the dynamic plugins configuration.
+func getOrchestratorPlugins(serverlessWorkflowNamespace string, argoCDEnabled, tektonEnabled bool,
+	rhdhConfig orchestratorv1alpha3.RHDHConfig) ([]any, error) {
+	config, err := ConfigMapTemplateFactory(AppConfigRHDHDynamicPluginName, "", serverlessWorkflowNamespace, argoCDEnabled, tektonEnabled, rhdhConfig)
+	if err != nil {
+		return nil, err
+	}
+	dynamicPlugins := struct {
+		Plugins []any `json:"plugins"`
+	}{}
+	if err := yaml.Unmarshal([]byte(config), &dynamicPlugins); err != nil {
+		return nil, err
+	}
+
+	// the ArgoCD and Tekton plugins, only rendered when enabled, and the plugins overridden in the orchestrator are
+	// configured along with the orchestrator plugins
+	packages := slices.Concat(tektonPlugins, argoCDPlugins)
+	for _, override := range rhdhConfig.RHDHPlugins.Overrides {
+		packages = append(packages, override.Package)
+	}
+	var plugins []any
+	for _, plugin := range dynamicPlugins.Plugins {
+		if name := getPluginName(plugin); strings.HasPrefix(name, Scope+"/") || slices.Contains(packages, name) {
+			plugins = append(plugins, plugin)
+		}
+	}
+	return plugins, nil
+}
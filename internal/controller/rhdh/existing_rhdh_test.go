@@ -0,0 +1,255 @@
+package rhdh
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/util"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	rhdhv1alpha3 "redhat-developer/red-hat-developer-hub-operator/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+const existingDynamicPlugins = `includes:
+  - dynamic-plugins.default.yaml
+plugins:
+  - package: ./dynamic-plugins/dist/backstage-plugin-kubernetes
+    disabled: false
+  - package: "@redhat/backstage-plugin-orchestrator@1.5.0"
+    disabled: false
+    integrity: sha512-outdated
+`
+
+func getDynamicPluginPackages(t *testing.T, configMap *corev1.ConfigMap) []string {
+	dynamicPlugins := struct {
+		Includes []string `json:"includes"`
+		Plugins  []struct {
+			Package string `json:"package"`
+		} `json:"plugins"`
+	}{}
+	assert.NoError(t, yaml.Unmarshal([]byte(configMap.Data["dynamic-plugins.yaml"]), &dynamicPlugins))
+	assert.Equal(t, []string{dynamicPluginsDefaultInclude}, dynamicPlugins.Includes)
+
+	var packages []string
+	for _, plugin := range dynamicPlugins.Plugins {
+		packages = append(packages, plugin.Package)
+	}
+	return packages
+}
+
+func TestHandleExistingRHDH(t *testing.T) {
+	ctx := context.TODO()
+	backstageKey := types.NamespacedName{Name: rhdhConfig.Name, Namespace: rhdhNamespace}
+	backstage := &rhdhv1alpha3.Backstage{
+		ObjectMeta: metav1.ObjectMeta{Name: rhdhConfig.Name, Namespace: rhdhNamespace},
+		Spec: rhdhv1alpha3.BackstageSpec{
+			Application: &rhdhv1alpha3.Application{
+				AppConfig:                   &rhdhv1alpha3.AppConfig{ConfigMaps: []rhdhv1alpha3.FileObjectRef{{Name: "my-app-config"}}},
+				DynamicPluginsConfigMapName: "my-dynamic-plugins",
+				Replicas:                    util.MakePointer(int32(3)),
+			},
+		},
+	}
+	dynamicPlugins := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-dynamic-plugins", Namespace: rhdhNamespace},
+		Data:       map[string]string{"dynamic-plugins.yaml": existingDynamicPlugins},
+	}
+	fakeClient := newFakeClient(backstage, dynamicPlugins)
+
+	conflicts, err := HandleExistingRHDH(ctx, fakeClient, workflowNamespace, false, false, rhdhConfig)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	// the orchestrator plugins are merged with the existing plugins
+	dynamicPlugins = getConfigMap(t, fakeClient, "my-dynamic-plugins")
+	plugins := getPlugins()
+	assert.Equal(t, []string{
+		"./dynamic-plugins/dist/backstage-plugin-kubernetes",
+		Scope + "/" + plugins[Orchestrator].Package,
+		Scope + "/" + plugins[OrchestratorBackend].Package,
+		Scope + "/" + plugins[ScaffolderBackendOrchestrator].Package,
+		Scope + "/" + plugins[OrchestratorFormWidgets].Package,
+	}, getDynamicPluginPackages(t, dynamicPlugins))
+	assert.Contains(t, dynamicPlugins.Data["dynamic-plugins.yaml"], "http://sonataflow-platform-data-index-service.sonataflow-infra")
+
+	appConfig := getConfigMap(t, fakeClient, AppConfigRHDHOrchestratorName)
+	assert.Contains(t, appConfig.Data[appConfigOrchestratorDataKey], "http://sonataflow-platform-data-index-service.sonataflow-infra")
+
+	// only the configmaps are referenced in the Backstage CR
+	assert.NoError(t, fakeClient.Get(ctx, backstageKey, backstage))
+	assert.Equal(t, []rhdhv1alpha3.FileObjectRef{{Name: "my-app-config"}, {Name: AppConfigRHDHOrchestratorName}},
+		backstage.Spec.Application.AppConfig.ConfigMaps)
+	assert.Equal(t, "my-dynamic-plugins", backstage.Spec.Application.DynamicPluginsConfigMapName)
+	assert.Equal(t, int32(3), *backstage.Spec.Application.Replicas)
+	assert.Nil(t, backstage.Spec.Deployment)
+	assert.Empty(t, backstage.Labels)
+
+	// reconciling again does not modify the instance
+	conflicts, err = HandleExistingRHDH(ctx, fakeClient, workflowNamespace, false, false, rhdhConfig)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	updatedBackstage := &rhdhv1alpha3.Backstage{}
+	assert.NoError(t, fakeClient.Get(ctx, backstageKey, updatedBackstage))
+	assert.Equal(t, backstage.ResourceVersion, updatedBackstage.ResourceVersion)
+	assert.Equal(t, dynamicPlugins.ResourceVersion, getConfigMap(t, fakeClient, "my-dynamic-plugins").ResourceVersion)
+}
+
+func TestHandleExistingRHDHWithoutDynamicPlugins(t *testing.T) {
+	ctx := context.TODO()
+	backstage := &rhdhv1alpha3.Backstage{ObjectMeta: metav1.ObjectMeta{Name: rhdhConfig.Name, Namespace: rhdhNamespace}}
+	fakeClient := newFakeClient(backstage)
+
+	conflicts, err := HandleExistingRHDH(ctx, fakeClient, workflowNamespace, false, false, rhdhConfig)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	dynamicPlugins := getConfigMap(t, fakeClient, AppConfigRHDHDynamicPluginName)
+	assert.Len(t, getDynamicPluginPackages(t, dynamicPlugins), len(getPlugins()))
+
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: rhdhConfig.Name, Namespace: rhdhNamespace}, backstage))
+	assert.Equal(t, AppConfigRHDHDynamicPluginName, backstage.Spec.Application.DynamicPluginsConfigMapName)
+	assert.Equal(t, []rhdhv1alpha3.FileObjectRef{{Name: AppConfigRHDHOrchestratorName}}, backstage.Spec.Application.AppConfig.ConfigMaps)
+}
+
+func TestHandleExistingRHDHWithArgoCDAndTekton(t *testing.T) {
+	ctx := context.TODO()
+	backstageKey := types.NamespacedName{Name: rhdhConfig.Name, Namespace: rhdhNamespace}
+	backstage := &rhdhv1alpha3.Backstage{ObjectMeta: metav1.ObjectMeta{Name: rhdhConfig.Name, Namespace: rhdhNamespace}}
+	fakeClient := newFakeClient(backstage)
+
+	_, err := HandleExistingRHDH(ctx, fakeClient, workflowNamespace, false, true, rhdhConfig)
+	assert.NoError(t, err)
+	packages := getDynamicPluginPackages(t, getConfigMap(t, fakeClient, AppConfigRHDHDynamicPluginName))
+	assert.Subset(t, packages, tektonPlugins)
+	for _, plugin := range argoCDPlugins {
+		assert.NotContains(t, packages, plugin)
+	}
+	assert.NotContains(t, getConfigMap(t, fakeClient, AppConfigRHDHOrchestratorName).Data[appConfigOrchestratorDataKey], "argocd:")
+
+	// the ArgoCD plugins are not configured until the backend auth secret has the ArgoCD environment
+	_, err = HandleExistingRHDH(ctx, fakeClient, workflowNamespace, true, true, rhdhConfig)
+	assert.NoError(t, err)
+	packages = getDynamicPluginPackages(t, getConfigMap(t, fakeClient, AppConfigRHDHDynamicPluginName))
+	for _, plugin := range argoCDPlugins {
+		assert.NotContains(t, packages, plugin)
+	}
+	assert.NotContains(t, getConfigMap(t, fakeClient, AppConfigRHDHOrchestratorName).Data[appConfigOrchestratorDataKey], "argocd:")
+	assert.NoError(t, fakeClient.Get(ctx, backstageKey, backstage))
+	assert.Nil(t, backstage.Spec.Application.ExtraEnvs)
+
+	assert.NoError(t, fakeClient.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: BackendAuthSecretName, Namespace: rhdhNamespace},
+		Data: map[string][]byte{
+			ArgoCDUrl:      []byte("https://argocd.example.com"),
+			ArgoCDUsername: []byte("admin"),
+			ArgoCDPassword: []byte("password"),
+		},
+	}))
+	_, err = HandleExistingRHDH(ctx, fakeClient, workflowNamespace, true, true, rhdhConfig)
+	assert.NoError(t, err)
+	packages = getDynamicPluginPackages(t, getConfigMap(t, fakeClient, AppConfigRHDHDynamicPluginName))
+	assert.Subset(t, packages, tektonPlugins)
+	assert.Subset(t, packages, argoCDPlugins)
+	assert.Contains(t, getConfigMap(t, fakeClient, AppConfigRHDHOrchestratorName).Data[appConfigOrchestratorDataKey], "argocd:")
+	assert.NoError(t, fakeClient.Get(ctx, backstageKey, backstage))
+	assert.Equal(t, []rhdhv1alpha3.EnvObjectRef{{Name: BackendAuthSecretName}}, backstage.Spec.Application.ExtraEnvs.Secrets)
+
+	// the ArgoCD and Tekton plugins added by the operator are removed once disabled
+	_, err = HandleExistingRHDH(ctx, fakeClient, workflowNamespace, false, false, rhdhConfig)
+	assert.NoError(t, err)
+	packages = getDynamicPluginPackages(t, getConfigMap(t, fakeClient, AppConfigRHDHDynamicPluginName))
+	assert.Len(t, packages, len(getPlugins()))
+	for _, plugin := range slices.Concat(tektonPlugins, argoCDPlugins) {
+		assert.NotContains(t, packages, plugin)
+	}
+	assert.NotContains(t, getConfigMap(t, fakeClient, AppConfigRHDHOrchestratorName).Data[appConfigOrchestratorDataKey], "argocd:")
+}
+
+func TestHandleExistingRHDHKeepsUserPlugins(t *testing.T) {
+	ctx := context.TODO()
+	backstage := &rhdhv1alpha3.Backstage{
+		ObjectMeta: metav1.ObjectMeta{Name: rhdhConfig.Name, Namespace: rhdhNamespace},
+		Spec: rhdhv1alpha3.BackstageSpec{
+			Application: &rhdhv1alpha3.Application{DynamicPluginsConfigMapName: "my-dynamic-plugins"},
+		},
+	}
+	dynamicPlugins := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-dynamic-plugins", Namespace: rhdhNamespace},
+		Data: map[string]string{"dynamic-plugins.yaml": existingDynamicPlugins + `  - package: ./dynamic-plugins/dist/backstage-community-plugin-tekton
+    disabled: false
+`},
+	}
+	fakeClient := newFakeClient(backstage, dynamicPlugins)
+
+	_, err := HandleExistingRHDH(ctx, fakeClient, workflowNamespace, false, true, rhdhConfig)
+	assert.NoError(t, err)
+	dynamicPlugins = getConfigMap(t, fakeClient, "my-dynamic-plugins")
+	assert.NotContains(t, dynamicPlugins.Annotations[addedPluginsAnnotation], tektonPlugins[0])
+	assert.Contains(t, dynamicPlugins.Annotations[addedPluginsAnnotation], Scope+"/backstage-plugin-orchestrator-form-widgets")
+
+	// the plugins configured by the user are kept once disabled in the orchestrator
+	_, err = HandleExistingRHDH(ctx, fakeClient, workflowNamespace, false, false, rhdhConfig)
+	assert.NoError(t, err)
+	packages := getDynamicPluginPackages(t, getConfigMap(t, fakeClient, "my-dynamic-plugins"))
+	assert.Contains(t, packages, "./dynamic-plugins/dist/backstage-plugin-kubernetes")
+	assert.Subset(t, packages, tektonPlugins)
+}
+
+func TestHandleExistingRHDHNotFound(t *testing.T) {
+	ctx := context.TODO()
+
+	_, err := HandleExistingRHDH(ctx, newFakeClient(), workflowNamespace, false, false, rhdhConfig)
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// an instance not managed by the RHDH operator is left alone
+	scheme := runtime.NewScheme()
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+	fakeClientWithoutCRD := fake.NewClientBuilder().WithScheme(scheme).Build()
+	conflicts, err := HandleExistingRHDH(ctx, fakeClientWithoutCRD, workflowNamespace, false, false, rhdhConfig)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+}
+
+func TestHandleExistingRHDHWithPluginOverrides(t *testing.T) {
+	ctx := context.TODO()
+	backstage := &rhdhv1alpha3.Backstage{
+		ObjectMeta: metav1.ObjectMeta{Name: rhdhConfig.Name, Namespace: rhdhNamespace},
+		Spec: rhdhv1alpha3.BackstageSpec{
+			Application: &rhdhv1alpha3.Application{DynamicPluginsConfigMapName: "my-dynamic-plugins"},
+		},
+	}
+	dynamicPlugins := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-dynamic-plugins", Namespace: rhdhNamespace},
+		Data:       map[string]string{"dynamic-plugins.yaml": existingDynamicPlugins},
+	}
+	fakeClient := newFakeClient(backstage, dynamicPlugins)
+
+	overriddenConfig := *rhdhConfig.DeepCopy()
+	overriddenConfig.RHDHPlugins.Overrides = []orchestratorv1alpha3.PluginOverride{
+		{Package: Scope + "/backstage-plugin-orchestrator", Version: "1.7.0", Integrity: "sha512-upgraded"},
+		{Package: "./dynamic-plugins/dist/backstage-plugin-kubernetes", Disabled: util.MakePointer(true)},
+	}
+	_, err := HandleExistingRHDH(ctx, fakeClient, workflowNamespace, false, false, overriddenConfig)
+	assert.NoError(t, err)
+
+	// the overridden plugins replace the existing entries
+	plugins := getPlugins()
+	assert.Equal(t, []string{
+		"./dynamic-plugins/dist/backstage-plugin-kubernetes",
+		Scope + "/backstage-plugin-orchestrator@1.7.0",
+		Scope + "/" + plugins[OrchestratorBackend].Package,
+		Scope + "/" + plugins[ScaffolderBackendOrchestrator].Package,
+		Scope + "/" + plugins[OrchestratorFormWidgets].Package,
+	}, getDynamicPluginPackages(t, getConfigMap(t, fakeClient, "my-dynamic-plugins")))
+	assert.Contains(t, getConfigMap(t, fakeClient, "my-dynamic-plugins").Data["dynamic-plugins.yaml"], "disabled: true")
+}
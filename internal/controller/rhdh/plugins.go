@@ -1,5 +1,16 @@
 package rhdh
 
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"sigs.k8s.io/yaml"
+)
+
 type Plugin struct {
 	Package   string
 	Integrity string
@@ -31,3 +42,88 @@ func getPlugins() map[string]Plugin {
 	}
 
 }
+
+// applyPluginOverrides merges the plugin overrides into the rendered dynamic plugins configuration. The plugins
+// that are not rendered by the operator are added to it.
+func applyPluginOverrides(config string, overrides []v1alpha3.PluginOverride) (string, error) {
+	if len(overrides) == 0 {
+		return config, nil
+	}
+
+	dynamicPlugins := map[string]any{}
+	if err := yaml.Unmarshal([]byte(config), &dynamicPlugins); err != nil {
+		return "", fmt.Errorf("failed to parse dynamic plugins: %w", err)
+	}
+	plugins, _ := dynamicPlugins["plugins"].([]any)
+	for _, override := range overrides {
+		index := slices.IndexFunc(plugins, func(plugin any) bool {
+			return getPluginName(plugin) == override.Package
+		})
+		if index < 0 {
+			plugins = append(plugins, map[string]any{"package": override.Package, "disabled": false})
+			index = len(plugins) - 1
+		}
+		if err := applyPluginOverride(plugins[index].(map[string]any), override); err != nil {
+			return "", err
+		}
+	}
+	dynamicPlugins["plugins"] = plugins
+
+	merged, err := yaml.Marshal(dynamicPlugins)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal dynamic plugins: %w", err)
+	}
+	return string(merged), nil
+}
+
+func applyPluginOverride(plugin map[string]any, override v1alpha3.PluginOverride) error {
+	if override.Version != "" {
+		plugin["package"] = override.Package + "@" + override.Version
+	}
+	if override.Integrity != "" {
+		plugin["integrity"] = override.Integrity
+	}
+	if override.Disabled != nil {
+		plugin["disabled"] = *override.Disabled
+	}
+	if override.PluginConfig != nil {
+		pluginConfig := map[string]any{}
+		if err := json.Unmarshal(override.PluginConfig.Raw, &pluginConfig); err != nil {
+			return fmt.Errorf("failed to parse pluginConfig of plugin %s: %w", override.Package, err)
+		}
+		renderedPluginConfig, _ := plugin["pluginConfig"].(map[string]any)
+		plugin["pluginConfig"] = mergeMaps(renderedPluginConfig, pluginConfig)
+	}
+	return nil
+}
+
+// mergeMaps merges override into a copy of base recursively, the values of override taking precedence.
+func mergeMaps(base, override map[string]any) map[string]any {
+	merged := maps.Clone(base)
+	if merged == nil {
+		merged = map[string]any{}
+	}
+	for key, value := range override {
+		baseValue, baseIsMap := merged[key].(map[string]any)
+		overrideValue, overrideIsMap := value.(map[string]any)
+		if baseIsMap && overrideIsMap {
+			merged[key] = mergeMaps(baseValue, overrideValue)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// getPluginName returns the package of a dynamic plugin entry without its version.
+func getPluginName(plugin any) string {
+	pluginConfig, ok := plugin.(map[string]any)
+	if !ok {
+		return ""
+	}
+	packageName, _ := pluginConfig["package"].(string)
+	if index := strings.LastIndex(packageName, "@"); index > 0 {
+		return packageName[:index]
+	}
+	return packageName
+}
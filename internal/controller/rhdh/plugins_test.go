@@ -0,0 +1,105 @@
+package rhdh
+
+import (
+	"os"
+	"testing"
+
+	"github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/util"
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const renderedDynamicPlugins = `includes:
+  - dynamic-plugins.default.yaml
+plugins:
+  - package: "@redhat/backstage-plugin-orchestrator@1.6.1"
+    integrity: sha512-rendered
+    disabled: false
+    pluginConfig:
+      dynamicPlugins:
+        frontend:
+          red-hat-developer-hub.backstage-plugin-orchestrator:
+            appIcons:
+              - name: orchestratorIcon
+  - package: "@redhat/backstage-plugin-orchestrator-form-widgets@1.6.1"
+    integrity: sha512-rendered
+    disabled: false
+`
+
+// TestGetPluginsMatchCRDAnnotations ensures the plugins rendered by the operator do not drift from the versions
+// published in the annotations of the Orchestrator CRD.
+func TestGetPluginsMatchCRDAnnotations(t *testing.T) {
+	data, err := os.ReadFile("../../../config/crd/bases/rhdh.redhat.com_orchestrators.yaml")
+	assert.NoError(t, err)
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	assert.NoError(t, yaml.Unmarshal(data, crd))
+
+	annotationPrefixes := map[string]string{
+		Orchestrator:                  "orchestrator",
+		OrchestratorBackend:           "orchestrator-backend-dynamic",
+		ScaffolderBackendOrchestrator: "orchestrator-scaffolder-backend",
+		OrchestratorFormWidgets:       "orchestrator-form-widgets",
+	}
+	plugins := getPlugins()
+	assert.Len(t, plugins, len(annotationPrefixes))
+	for name, prefix := range annotationPrefixes {
+		assert.Equal(t, crd.Annotations[prefix+"-package"], plugins[name].Package, "package of plugin %s", name)
+		assert.Equal(t, crd.Annotations[prefix+"-integrity"], plugins[name].Integrity, "integrity of plugin %s", name)
+	}
+}
+
+func TestApplyPluginOverrides(t *testing.T) {
+	config, err := applyPluginOverrides(renderedDynamicPlugins, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, renderedDynamicPlugins, config, "the rendered configuration is kept without overrides")
+
+	config, err = applyPluginOverrides(renderedDynamicPlugins, []v1alpha3.PluginOverride{
+		{
+			Package:      "@redhat/backstage-plugin-orchestrator",
+			Version:      "1.7.0",
+			Integrity:    "sha512-upgraded",
+			PluginConfig: &apiextensionsv1.JSON{Raw: []byte(`{"dynamicPlugins":{"frontend":{"red-hat-developer-hub.backstage-plugin-orchestrator":{"mountPoints":[]}}}}`)},
+		},
+		{Package: "@redhat/backstage-plugin-orchestrator-form-widgets", Disabled: util.MakePointer(true)},
+		{Package: "./dynamic-plugins/dist/my-plugin"},
+	})
+	assert.NoError(t, err)
+
+	dynamicPlugins := struct {
+		Includes []string         `json:"includes"`
+		Plugins  []map[string]any `json:"plugins"`
+	}{}
+	assert.NoError(t, yaml.Unmarshal([]byte(config), &dynamicPlugins))
+	assert.Equal(t, []string{"dynamic-plugins.default.yaml"}, dynamicPlugins.Includes)
+	assert.Len(t, dynamicPlugins.Plugins, 3)
+
+	orchestrator := dynamicPlugins.Plugins[0]
+	assert.Equal(t, "@redhat/backstage-plugin-orchestrator@1.7.0", orchestrator["package"])
+	assert.Equal(t, "sha512-upgraded", orchestrator["integrity"])
+	assert.Equal(t, false, orchestrator["disabled"])
+	assert.Equal(t, map[string]any{
+		"dynamicPlugins": map[string]any{
+			"frontend": map[string]any{
+				"red-hat-developer-hub.backstage-plugin-orchestrator": map[string]any{
+					"appIcons":    []any{map[string]any{"name": "orchestratorIcon"}},
+					"mountPoints": []any{},
+				},
+			},
+		},
+	}, orchestrator["pluginConfig"], "the pluginConfig override is merged into the rendered one")
+
+	formWidgets := dynamicPlugins.Plugins[1]
+	assert.Equal(t, "@redhat/backstage-plugin-orchestrator-form-widgets@1.6.1", formWidgets["package"])
+	assert.Equal(t, true, formWidgets["disabled"])
+
+	assert.Equal(t, map[string]any{"package": "./dynamic-plugins/dist/my-plugin", "disabled": false}, dynamicPlugins.Plugins[2])
+}
+
+func TestGetPluginName(t *testing.T) {
+	assert.Equal(t, "@redhat/backstage-plugin-orchestrator", getPluginName(map[string]any{"package": "@redhat/backstage-plugin-orchestrator@1.6.1"}))
+	assert.Equal(t, "@redhat/backstage-plugin-orchestrator", getPluginName(map[string]any{"package": "@redhat/backstage-plugin-orchestrator"}))
+	assert.Equal(t, "./dynamic-plugins/dist/my-plugin", getPluginName(map[string]any{"package": "./dynamic-plugins/dist/my-plugin"}))
+	assert.Equal(t, "", getPluginName("invalid"))
+}
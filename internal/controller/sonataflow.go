@@ -17,19 +17,19 @@ package controller
 import (
 	"context"
 	"fmt"
-	"k8s.io/apimachinery/pkg/util/errors"
 	"reflect"
 
 	sonataapi "github.com/apache/incubator-kie-tools/packages/sonataflow-operator/api/v1alpha08"
 	olmclientset "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned"
-	orchestratorv1alpha2 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/knative"
 	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	orchestratormetrics "github.com/rhdhorchestrator/orchestrator-operator/internal/controller/metrics"
 	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/util"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -47,13 +47,14 @@ const (
 	serverlessLogicOperatorNamespace       = "openshift-serverless-logic"
 	serverlessLogicSubscriptionName        = "logic-operator-rhel8"
 	serverlessLogicSubscriptionStartingCSV = "logic-operator-rhel8.v1.36.0"
-	knativeBrokerAPIVersion                = "eventing.knative.dev/v1"
-	knativeBrokerKind                      = "Broker"
 	sonataFlowPlatformReference            = "sonataflow-platform"
 )
 
 // handleServerlessLogicOperatorInstallation performs operator installation for the OSL operand
-func handleServerlessLogicOperatorInstallation(ctx context.Context, client client.Client, olmClientSet olmclientset.Interface) error {
+// and returns the InstallPlan of the operator left waiting for approval, if any
+func handleServerlessLogicOperatorInstallation(
+	ctx context.Context, client client.Client, olmClientSet olmclientset.Interface,
+	subscriptionConfig orchestratorv1alpha3.Subscription, approvedCSV string) (*orchestratorv1alpha3.PendingInstallPlan, error) {
 	sfLogger := log.FromContext(ctx)
 
 	// create namespace for operator
@@ -61,11 +62,11 @@ func handleServerlessLogicOperatorInstallation(ctx context.Context, client clien
 		if apierrors.IsNotFound(err) {
 			if err := kube.CreateNamespace(ctx, client, serverlessLogicOperatorNamespace); err != nil {
 				sfLogger.Error(err, "Error occurred when creating namespace for Serverless Logic operator", "NS", serverlessLogicOperatorNamespace)
-				return nil
+				return nil, nil
 			}
 		}
 		sfLogger.Error(err, "Error occurred when checking namespace exist for Serverless Logic operator", "NS", serverlessLogicOperatorNamespace)
-		return err
+		return nil, err
 	}
 
 	// check if subscription exist
@@ -74,12 +75,13 @@ func handleServerlessLogicOperatorInstallation(ctx context.Context, client clien
 		serverlessLogicOperatorNamespace,
 		serverlessLogicSubscriptionChannel,
 		serverlessLogicSubscriptionStartingCSV,
+		subscriptionConfig,
 	)
 
 	subscriptionExists, existingSubscription, err := kube.CheckSubscriptionExists(ctx, olmClientSet, oslSubscription)
 	if err != nil {
 		sfLogger.Error(err, "Error occurred when checking subscription exists", "SubscriptionName", serverlessLogicSubscriptionName)
-		return err
+		return nil, err
 	}
 	if !subscriptionExists {
 		err := kube.InstallSubscriptionAndOperatorGroup(
@@ -88,7 +90,7 @@ func handleServerlessLogicOperatorInstallation(ctx context.Context, client clien
 			oslSubscription)
 		if err != nil {
 			sfLogger.Error(err, "Error occurred when installing operator via Subscription", "SubscriptionName", serverlessLogicSubscriptionName)
-			return err
+			return nil, err
 		}
 		sfLogger.Info("Operator successfully installed via Subscription", "SubscriptionName", serverlessLogicSubscriptionName)
 	} else {
@@ -98,25 +100,24 @@ func handleServerlessLogicOperatorInstallation(ctx context.Context, client clien
 			existingSubscription.Spec = oslSubscription.Spec
 			if err := client.Update(ctx, existingSubscription); err != nil {
 				sfLogger.Error(err, "Error occurred when updating subscription spec", "SubscriptionName", serverlessLogicSubscriptionName)
-				return err
+				return nil, err
 			}
 			sfLogger.Info("Successfully updated updating subscription spec", "SubscriptionName", serverlessLogicSubscriptionName)
+			orchestratormetrics.RecordDriftCorrection("Subscription")
 		}
 	}
 
 	// approve install plan
-	if existingSubscription.Status.InstallPlanRef != nil && existingSubscription.Status.CurrentCSV == serverlessLogicSubscriptionStartingCSV {
-		installPlanName := existingSubscription.Status.InstallPlanRef.Name
-		if err := kube.ApproveInstallPlan(client, ctx, installPlanName, existingSubscription.Namespace); err != nil {
-			sfLogger.Error(err, "Error occurred while approving install plan for subscription", "SubscriptionName", installPlanName)
-			return err
-		}
+	pendingInstallPlan, err := kube.HandleInstallPlanApproval(ctx, client, existingSubscription, subscriptionConfig, approvedCSV)
+	if err != nil {
+		sfLogger.Error(err, "Error occurred while approving install plan for subscription", "SubscriptionName", serverlessLogicSubscriptionName)
+		return nil, err
 	}
-	return nil
+	return pendingInstallPlan, nil
 }
 
 // handleServerlessLogicCR performs the creation of serverless logic namespace and CRs
-func handleServerlessLogicCR(ctx context.Context, client client.Client, orchestrator *orchestratorv1alpha2.Orchestrator) error {
+func handleServerlessLogicCR(ctx context.Context, client client.Client, orchestrator *orchestratorv1alpha3.Orchestrator) error {
 	sfLogger := log.FromContext(ctx)
 	sfLogger.Info("Handling ServerlessLogic CR...")
 	serverlessWorkflowNamespace := orchestrator.Spec.PlatformConfig.Namespace
@@ -130,20 +131,32 @@ func handleServerlessLogicCR(ctx context.Context, client client.Client, orchestr
 		return err
 	}
 
-	if err := handleSonataFlowClusterCR(ctx, client, sonataFlowClusterPlatformCRName, serverlessWorkflowNamespace); err != nil {
+	if err := handleSonataFlowClusterCR(ctx, client, sonataFlowClusterPlatformCRName, serverlessWorkflowNamespace, kube.GetInstance(orchestrator)); err != nil {
 		sfLogger.Error(err, "Error occurred when creating SonataFlowClusterCR", "CR-Name", sonataFlowClusterPlatformCRName)
 		return err
 
 	}
+	// create the broker before the sonataflowplatform CR, which only references it once it is ready
+	broker := orchestrator.Spec.PlatformConfig.Eventing.Broker
+	if err := knative.DeleteStaleBrokers(ctx, client, broker, serverlessWorkflowNamespace, kube.GetInstance(orchestrator)); err != nil {
+		sfLogger.Error(err, "Error occurred when deleting stale Brokers")
+		return err
+	}
+	if broker.Create {
+		if err := knative.HandleBrokerCR(ctx, client, broker, serverlessWorkflowNamespace, kube.GetInstance(orchestrator)); err != nil {
+			sfLogger.Error(err, "Error occurred when handling Broker", "CR-Name", broker.Name)
+			return err
+		}
+	}
 	// create sonataflowplatform  CR
-	if err := handleSonataFlowPlatformCR(ctx, client, orchestrator, sonataFlowClusterPlatformCRName, serverlessWorkflowNamespace); err != nil {
-		sfLogger.Error(err, "Error occurred when creating SonataFlowPlatform", "CR-Name", sonataFlowClusterPlatformCRName)
+	if err := handleSonataFlowPlatformCR(ctx, client, orchestrator, serverlessWorkflowNamespace); err != nil {
+		sfLogger.Error(err, "Error occurred when creating SonataFlowPlatform", "CR-Name", sonataFlowPlatformCRName)
 		return err
 	}
 	return nil
 }
 
-func getServerlessLogicPersistence(orchestrator *orchestratorv1alpha2.Orchestrator) *sonataapi.PersistenceOptionsSpec {
+func getServerlessLogicPersistence(orchestrator *orchestratorv1alpha3.Orchestrator) *sonataapi.PersistenceOptionsSpec {
 	return &sonataapi.PersistenceOptionsSpec{
 		PostgreSQL: &sonataapi.PersistencePostgreSQL{
 			SecretRef: sonataapi.PostgreSQLSecretOptions{
@@ -162,40 +175,27 @@ func getServerlessLogicPersistence(orchestrator *orchestratorv1alpha2.Orchestrat
 	}
 }
 
-func handleSonataFlowClusterCR(ctx context.Context, client client.Client, crName, namespace string) error {
+// handleSonataFlowClusterCR creates or updates the SonataFlowClusterPlatform CR with server-side apply
+func handleSonataFlowClusterCR(ctx context.Context, client client.Client, crName, namespace, instance string) error {
 	logger := log.FromContext(ctx)
-	logger.Info("Starting CR creation for SonataFlowCluster...")
-
-	// check sonataflowlusterplatform CR exists
-	sfcCR := &sonataapi.SonataFlowClusterPlatform{}
-
-	err := client.Get(ctx, types.NamespacedName{Name: crName, Namespace: namespace}, sfcCR)
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			// Create sonataflowcluster CR object
-			sonataFlowClusterCR := &sonataapi.SonataFlowClusterPlatform{
-				TypeMeta: metav1.TypeMeta{
-					APIVersion: sonataFlowAPIVersion,
-					Kind:       sonataFlowClusterPlatformKind,
-				},
-				ObjectMeta: metav1.ObjectMeta{
-					Name:   sonataFlowClusterPlatformCRName,
-					Labels: kube.GetOrchestratorLabel(),
-				},
-				Spec: getSonataFlowClusterSpec(namespace),
-			}
+	logger.Info("Applying SonataFlowClusterPlatform CR...", "CR-Name", crName)
 
-			// Create sonataflowcluster CR
-			if err := client.Create(ctx, sonataFlowClusterCR); err != nil {
-				logger.Error(err, "Error occurred when creating Custom Resource", "CR-Name", crName)
-				return err
-			}
-			logger.Info("Successfully created SonataFlowClusterPlatform resource", "CR-Name", sonataFlowClusterCR.Name)
-			return nil
-		}
-		logger.Error(err, "Error occurred when retrieving SonataFlowClusterPlatform CR", "CR-Name", crName)
+	sonataFlowClusterCR := &sonataapi.SonataFlowClusterPlatform{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: sonataFlowAPIVersion,
+			Kind:       sonataFlowClusterPlatformKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   crName,
+			Labels: kube.GetInstanceLabels(instance),
+		},
+		Spec: getSonataFlowClusterSpec(namespace),
+	}
+	if err := kube.ApplyObject(ctx, client, sonataFlowClusterCR); err != nil {
+		logger.Error(err, "Error occurred when applying SonataFlowClusterPlatform CR", "CR-Name", crName)
 		return err
 	}
+	logger.Info("Successfully applied SonataFlowClusterPlatform CR", "CR-Name", crName)
 	return nil
 }
 
@@ -208,52 +208,35 @@ func getSonataFlowClusterSpec(namespace string) sonataapi.SonataFlowClusterPlatf
 	}
 }
 
+// handleSonataFlowPlatformCR creates or updates the SonataFlowPlatform CR with server-side apply, so that changes
+// to the platform configuration of the orchestrator are rolled out to an existing SonataFlowPlatform
 func handleSonataFlowPlatformCR(
 	ctx context.Context, client client.Client,
-	orchestrator *orchestratorv1alpha2.Orchestrator, crName, namespace string) error {
+	orchestrator *orchestratorv1alpha3.Orchestrator, namespace string) error {
 	logger := log.FromContext(ctx)
+	logger.Info("Applying SonataFlowPlatform CR...", "CR-Name", sonataFlowPlatformCRName, "NS", namespace)
 
-	logger.Info("Starting CR creation for SonataFlowPlatform...")
-
-	sfpCR := &sonataapi.SonataFlowPlatform{}
-	err := client.Get(ctx, types.NamespacedName{
-		Namespace: namespace,
-		Name:      sonataFlowPlatformCRName,
-	}, sfpCR)
-
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			logger.Info("SonataFlowPlatform not found. Proceed to creating CR...")
-
-			// Create sonataflow platform CR object
-			sonataFlowPlatformCR := &sonataapi.SonataFlowPlatform{
-				TypeMeta: metav1.TypeMeta{
-					APIVersion: sonataFlowAPIVersion,
-					Kind:       sonataFlowPlatformKind,
-				},
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      sonataFlowPlatformCRName,
-					Namespace: namespace,
-					Labels:    kube.GetOrchestratorLabel(),
-				},
-				Spec: getSonataFlowPlatformSpec(ctx, orchestrator),
-			}
-			logger.Info("Persistence function", "Persistent", getServerlessLogicPersistence(orchestrator))
-			// Create sonataflowplatform CR
-			if err := client.Create(ctx, sonataFlowPlatformCR); err != nil {
-				logger.Error(err, "Failed to create Custom Resource", "CR-Name", crName)
-				return err
-			}
-			logger.Info("Successfully created SonataFlowPlatform CR", "CR-Name", sonataFlowPlatformCR.Name)
-			return nil
-		}
-		logger.Error(err, "Error occurred when retrieving SonataFlowPlatform CR", "CR-Name", crName)
+	sonataFlowPlatformCR := &sonataapi.SonataFlowPlatform{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: sonataFlowAPIVersion,
+			Kind:       sonataFlowPlatformKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sonataFlowPlatformCRName,
+			Namespace: namespace,
+			Labels:    kube.GetInstanceLabels(kube.GetInstance(orchestrator)),
+		},
+		Spec: getSonataFlowPlatformSpec(ctx, orchestrator),
+	}
+	if err := kube.ApplyObject(ctx, client, sonataFlowPlatformCR); err != nil {
+		logger.Error(err, "Error occurred when applying SonataFlowPlatform CR", "CR-Name", sonataFlowPlatformCRName, "NS", namespace)
 		return err
 	}
+	logger.Info("Successfully applied SonataFlowPlatform CR", "CR-Name", sonataFlowPlatformCRName, "NS", namespace)
 	return nil
 }
 
-func getSonataFlowPlatformSpec(ctx context.Context, orchestrator *orchestratorv1alpha2.Orchestrator) sonataapi.SonataFlowPlatformSpec {
+func getSonataFlowPlatformSpec(ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator) sonataapi.SonataFlowPlatformSpec {
 	limitResourceMap := make(map[corev1.ResourceName]resource.Quantity)
 
 	cpuQuantity, _ := resource.ParseQuantity(orchestrator.Spec.PlatformConfig.Resources.Limits.Cpu)
@@ -296,50 +279,39 @@ func getSonataFlowPlatformSpec(ctx context.Context, orchestrator *orchestratorv1
 	}
 }
 
-func handleServerlessLogicCleanUp(ctx context.Context, client client.Client, namespace string) error {
-	logger := log.FromContext(ctx)
-	logger.Info("Starting Clean Up for Serverless Logic ...")
-
-	// remove operand resources: sonataflowclusterplatform and sonataflowplatform
-	if err := handleRemovalOfOSLCRs(ctx, client, namespace); err != nil {
-		logger.Error(err, "Error occurred when removing OSL CR", "NS", namespace)
-		return err
-	}
-
-	// remove operator namespace
-	if err := kube.CleanUpNamespace(ctx, serverlessLogicOperatorNamespace, client); err != nil {
-		logger.Error(err, "Error occurred when deleting namespace", "NS", serverlessLogicOperatorNamespace)
-		return err
-	}
-	return nil
-}
-
-func createEventingSpec(ctx context.Context, orchestrator *orchestratorv1alpha2.Orchestrator) *sonataapi.PlatformEventingSpec {
+func createEventingSpec(ctx context.Context, orchestrator *orchestratorv1alpha3.Orchestrator) *sonataapi.PlatformEventingSpec {
 	sfLogger := log.FromContext(ctx)
+	broker := orchestrator.Spec.PlatformConfig.Eventing.Broker
 
 	// Check if Broker is empty
-	if reflect.ValueOf(orchestrator.Spec.PlatformConfig.Eventing.Broker).IsZero() {
+	if broker.Name == "" {
 		sfLogger.Info("No existing eventing broker")
 		return &sonataapi.PlatformEventingSpec{}
 	}
 
-	sfLogger.Info("An existing eventing broker is configured")
+	brokerNamespace := broker.Namespace
+	if broker.Create {
+		sfLogger.Info("An eventing broker is created by the operator")
+		brokerNamespace = orchestrator.Spec.PlatformConfig.Namespace
+	} else {
+		sfLogger.Info("An existing eventing broker is configured")
+	}
 	return &sonataapi.PlatformEventingSpec{
 		Broker: &duckv1.Destination{
 			Ref: &duckv1.KReference{
-				Kind:       knativeBrokerKind,
-				Name:       orchestrator.Spec.PlatformConfig.Eventing.Broker.Name,
-				Namespace:  orchestrator.Spec.PlatformConfig.Eventing.Broker.Namespace,
-				APIVersion: knativeBrokerAPIVersion,
+				Kind:       knative.BrokerKind,
+				Name:       broker.Name,
+				Namespace:  brokerNamespace,
+				APIVersion: knative.BrokerAPIVersion,
 			},
 		},
 	}
 }
 
-func handleRemovalOfOSLCRs(ctx context.Context, k8Client client.Client, namespace string) error {
-	logger := log.FromContext(ctx)
-	logger.Info("Starting Clean Up for OSL CR Removal", "NS", namespace)
-
+// listServerlessLogicCleanUpObjects returns the SonataFlow platforms, the Brokers and their Kafka ConfigMaps owned by
+// owner, and the Serverless Logic operator namespace when it is owned by owner, which are removed according to the
+// serverlessLogic deletion policy.
+func listServerlessLogicCleanUpObjects(ctx context.Context, k8Client client.Client, namespace string, owner kube.Owner) ([]client.Object, error) {
 	type crCleanupObj struct {
 		name     string
 		objList  client.ObjectList
@@ -373,17 +345,27 @@ func handleRemovalOfOSLCRs(ctx context.Context, k8Client client.Client, namespac
 		},
 	}
 
-	var errorList []error
+	var objects []client.Object
 	for _, crCleanup := range cleanups {
-		sfpErr := kube.RemoveCustomResourcesInNamespace(ctx, k8Client, crCleanup.objList, crCleanup.getItems, namespace)
-		if sfpErr != nil {
-			logger.Error(sfpErr, fmt.Sprintf("Error occurred when removing %s CR", crCleanup.name), "NS", namespace)
-			errorList = append(errorList, sfpErr)
+		crObjects, err := kube.ListOwnedCustomResources(ctx, k8Client, crCleanup.objList, crCleanup.getItems, namespace, owner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s CRs: %w", crCleanup.name, err)
 		}
+		objects = append(objects, crObjects...)
 	}
-	if len(errorList) > 0 {
-		return errors.NewAggregate(errorList)
+
+	brokerObjects, err := knative.ListBrokerCleanUpObjects(ctx, k8Client, owner)
+	if err != nil {
+		return nil, err
 	}
-	logger.Info(fmt.Sprintf("Successfully removed Orchestrator labelled CRs "), "NS", namespace)
-	return nil
+	objects = append(objects, brokerObjects...)
+
+	operatorNamespace, err := kube.GetOwnedNamespace(ctx, k8Client, serverlessLogicOperatorNamespace, owner)
+	if err != nil {
+		return nil, err
+	}
+	if operatorNamespace != nil {
+		objects = append(objects, operatorNamespace)
+	}
+	return objects, nil
 }
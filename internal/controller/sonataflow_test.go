@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	sonataapi "github.com/apache/incubator-kie-tools/packages/sonataflow-operator/api/v1alpha08"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/knative"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newApplyRecordingClient returns a fake client that records the objects applied with server-side apply, which the
+// fake client does not support.
+func newApplyRecordingClient(t *testing.T, applied map[string]client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(sonataapi.AddToScheme(scheme))
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			assert.Equal(t, types.ApplyPatchType, patch.Type())
+			patchOptions := &client.PatchOptions{}
+			patchOptions.ApplyOptions(opts)
+			assert.Equal(t, kube.FieldManager, patchOptions.FieldManager)
+			assert.True(t, *patchOptions.Force)
+
+			applied[obj.GetObjectKind().GroupVersionKind().Kind] = obj.DeepCopyObject().(client.Object)
+			return nil
+		},
+	}).Build()
+}
+
+func TestHandleServerlessLogicCRAppliesPlatformChanges(t *testing.T) {
+	ctx := context.TODO()
+	applied := map[string]client.Object{}
+	fakeClient := newApplyRecordingClient(t, applied)
+
+	orchestrator := &orchestratorv1alpha3.Orchestrator{
+		Spec: orchestratorv1alpha3.OrchestratorSpec{
+			PlatformConfig: orchestratorv1alpha3.PlatformConfig{
+				Namespace: "sonataflow-infra",
+				Resources: orchestratorv1alpha3.Resource{
+					Requests: orchestratorv1alpha3.MemoryCpu{Cpu: "250m", Memory: "64Mi"},
+					Limits:   orchestratorv1alpha3.MemoryCpu{Cpu: "500m", Memory: "1Gi"},
+				},
+			},
+		},
+	}
+
+	assert.NoError(t, handleSonataFlowClusterCR(ctx, fakeClient, sonataFlowClusterPlatformCRName, "sonataflow-infra", "orchestrator"))
+	clusterPlatform := applied[sonataFlowClusterPlatformKind].(*sonataapi.SonataFlowClusterPlatform)
+	assert.Equal(t, sonataFlowClusterPlatformCRName, clusterPlatform.Name)
+	assert.Equal(t, "sonataflow-infra", clusterPlatform.Spec.PlatformRef.Namespace)
+
+	assert.NoError(t, handleSonataFlowPlatformCR(ctx, fakeClient, orchestrator, "sonataflow-infra"))
+	platform := applied[sonataFlowPlatformKind].(*sonataapi.SonataFlowPlatform)
+	assert.Equal(t, sonataFlowAPIVersion, platform.APIVersion)
+	assert.Equal(t, types.NamespacedName{Name: sonataFlowPlatformCRName, Namespace: "sonataflow-infra"},
+		types.NamespacedName{Name: platform.Name, Namespace: platform.Namespace})
+	assert.False(t, platform.Spec.Monitoring.Enabled)
+	assert.Nil(t, platform.Spec.Eventing.Broker)
+
+	// changes to the orchestrator are applied to the existing platform
+	orchestrator.Spec.PlatformConfig.Resources.Limits.Memory = "2Gi"
+	orchestrator.Spec.PlatformConfig.Monitoring.Enabled = true
+	orchestrator.Spec.PlatformConfig.Eventing.Broker = orchestratorv1alpha3.Broker{Name: "kafka-broker", Namespace: "sonataflow-infra"}
+
+	assert.NoError(t, handleSonataFlowPlatformCR(ctx, fakeClient, orchestrator, "sonataflow-infra"))
+	platform = applied[sonataFlowPlatformKind].(*sonataapi.SonataFlowPlatform)
+	assert.Equal(t, resource.MustParse("2Gi"), platform.Spec.Build.Template.Resources.Limits[corev1.ResourceMemory])
+	assert.True(t, platform.Spec.Monitoring.Enabled)
+	assert.Equal(t, "kafka-broker", platform.Spec.Eventing.Broker.Ref.Name)
+}
+
+func TestCreateEventingSpecWithCreatedBroker(t *testing.T) {
+	orchestrator := &orchestratorv1alpha3.Orchestrator{
+		Spec: orchestratorv1alpha3.OrchestratorSpec{
+			PlatformConfig: orchestratorv1alpha3.PlatformConfig{
+				Namespace: "sonataflow-infra",
+				Eventing: orchestratorv1alpha3.Eventing{
+					Broker: orchestratorv1alpha3.Broker{Name: "orchestrator-broker", Create: true},
+				},
+			},
+		},
+	}
+
+	// the broker created by the operator lives in the workflow namespace
+	eventing := createEventingSpec(context.TODO(), orchestrator)
+	assert.Equal(t, "orchestrator-broker", eventing.Broker.Ref.Name)
+	assert.Equal(t, "sonataflow-infra", eventing.Broker.Ref.Namespace)
+	assert.Equal(t, knative.BrokerKind, eventing.Broker.Ref.Kind)
+	assert.Equal(t, knative.BrokerAPIVersion, eventing.Broker.Ref.APIVersion)
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	olmclientsetfake "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/fake"
+	orchestratorv1alpha3 "github.com/rhdhorchestrator/orchestrator-operator/api/v1alpha3"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/knative"
+	"github.com/rhdhorchestrator/orchestrator-operator/internal/controller/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUninstallDisabledOperators(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(orchestratorv1alpha3.AddToScheme(scheme))
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(operatorsv1.AddToScheme(scheme))
+
+	testCases := []struct {
+		name             string
+		policy           orchestratorv1alpha3.DeletionPolicy
+		expectedDeleted  bool
+		expectedLabelled bool
+		expectedRemoved  []string
+	}{
+		{
+			name:             "Retain keeps the namespaces",
+			policy:           orchestratorv1alpha3.DeletionPolicyRetain,
+			expectedLabelled: true,
+			expectedRemoved:  []string{"Subscription openshift-serverless/serverless-operator"},
+		},
+		{
+			name:            "Orphan keeps the namespaces without the labels of the operator",
+			policy:          orchestratorv1alpha3.DeletionPolicyOrphan,
+			expectedRemoved: []string{"Subscription openshift-serverless/serverless-operator"},
+		},
+		{
+			name:            "Delete deletes the namespaces",
+			policy:          orchestratorv1alpha3.DeletionPolicyDelete,
+			expectedDeleted: true,
+			expectedRemoved: []string{
+				"Namespace knative-eventing",
+				"Namespace knative-serving",
+				"Namespace openshift-serverless",
+				"Subscription openshift-serverless/serverless-operator",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			orchestrator := &orchestratorv1alpha3.Orchestrator{
+				ObjectMeta: metav1.ObjectMeta{Name: "orchestrator", Namespace: "default", UID: "orchestrator-uid"},
+				Spec: orchestratorv1alpha3.OrchestratorSpec{
+					ServerlessLogicOperator: orchestratorv1alpha3.ServerlessLogicOperator{InstallOperator: true},
+					ServerlessOperator:      orchestratorv1alpha3.ServerlessOperator{InstallOperator: false},
+					RHDHConfig:              orchestratorv1alpha3.RHDHConfig{InstallOperator: true},
+					DeletionPolicy:          orchestratorv1alpha3.DeletionPolicies{Serverless: tc.policy},
+				},
+			}
+			instanceLabels := kube.GetInstanceLabels(kube.GetInstance(orchestrator))
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				orchestrator,
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: knative.KnativeEventingNamespacedName, Labels: instanceLabels}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: knative.KnativeServingNamespacedName, Labels: instanceLabels}},
+				// the operator namespace is shared by the Orchestrators
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: knative.KnativeOperatorNamespace, Labels: kube.GetOrchestratorLabel()}},
+			).Build()
+			fakeOLMClientSet := olmclientsetfake.NewSimpleClientset(&v1alpha1.Subscription{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      knative.KnativeSubscriptionName,
+					Namespace: knative.KnativeOperatorNamespace,
+					Labels:    kube.GetOrchestratorLabel(),
+				},
+			})
+			r := &OrchestratorReconciler{Client: fakeClient, OLMClient: fakeOLMClientSet, Scheme: scheme}
+
+			removed, err := r.uninstallDisabledOperators(ctx, orchestrator)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedRemoved, removed)
+
+			_, err = fakeOLMClientSet.OperatorsV1alpha1().Subscriptions(knative.KnativeOperatorNamespace).
+				Get(ctx, knative.KnativeSubscriptionName, metav1.GetOptions{})
+			assert.True(t, apierrors.IsNotFound(err))
+			for _, name := range []string{knative.KnativeEventingNamespacedName, knative.KnativeServingNamespacedName} {
+				namespace := &corev1.Namespace{}
+				err := fakeClient.Get(ctx, types.NamespacedName{Name: name}, namespace)
+				if tc.expectedDeleted {
+					assert.True(t, apierrors.IsNotFound(err), "expected %s to be deleted", name)
+					continue
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedLabelled, kube.CheckLabelExist(namespace.Labels), name)
+			}
+		})
+	}
+}